@@ -0,0 +1,21 @@
+package main
+
+import (
+	"log"
+	"mus_lib/internal/app/songinfo"
+	"net/http"
+)
+
+// Адрес, на котором по умолчанию запускается фейковый сторонний API
+const fakeInfoAddr = ":8081"
+
+// Функция, выполняющая команду fake-info (запускает фейковый сторонний API для локальной разработки)
+func runFakeInfo(args []string) error {
+	addr := fakeInfoAddr
+	if len(args) > 0 {
+		addr = args[0]
+	}
+
+	log.Printf("Fake song info provider is listening on %s", addr)
+	return http.ListenAndServe(addr, songinfo.NewFake())
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"log"
 	"mus_lib/internal/app/api"
-	"mus_lib/migrations"
+	"os"
 
 	"github.com/joho/godotenv"
 )
@@ -15,18 +15,43 @@ import (
 //	@host		localhost:8080
 //	@BasePath	/api
 
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				Token from POST /auth/login or API key as 'Bearer <token>'
+
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+//	@description				API key from POST /auth/keys
+
 func init() {
 	// Считываем переменные окружения
 	err := godotenv.Load()
 	if err != nil {
 		log.Fatal("Can't find .env file with configuration")
 	}
-
-	// Регистрируем миграции доступные в приложении
-	migrations.AddMigration()
 }
 
 func main() {
+	// Команда migrate управляет схемой БД и не запускает сервер
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(os.Args[2:])
+		if err != nil {
+			log.Fatalf("Migration failed: %s", err)
+		}
+		return
+	}
+
+	// Команда fake-info запускает фейковый сторонний API с информацией о песнях
+	if len(os.Args) > 1 && os.Args[1] == "fake-info" {
+		err := runFakeInfo(os.Args[2:])
+		if err != nil {
+			log.Fatalf("Fake song info provider stopped: %s", err)
+		}
+		return
+	}
+
 	// Создаем инстанс нашего приложения (сервера)
 	server := api.New()
 
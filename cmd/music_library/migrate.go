@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mus_lib/migrations"
+	"mus_lib/storage"
+	"strconv"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Подсказка по использованию команды migrate
+const migrateUsage = "usage: music_library migrate up|down|status|redo|to <version>"
+
+// Функция, выполняющая команду migrate (управление версионными миграциями БД)
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	// Открываем соединение с БД (миграции поддерживаются только для Postgres)
+	db := storage.New()
+	err := db.Open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	provider, err := db.Migrations()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		results, err := provider.Up(ctx)
+		printResults(results...)
+		return err
+	case "down":
+		result, err := provider.Down(ctx)
+		printResults(result)
+		return err
+	case "redo":
+		result, err := provider.Down(ctx)
+		printResults(result)
+		if err != nil {
+			return err
+		}
+		result, err = provider.UpByOne(ctx)
+		printResults(result)
+		return err
+	case "to":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("uncorrected version %q: %s", args[1], migrateUsage)
+		}
+		return migrateTo(ctx, provider, version)
+	case "status":
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "-"
+			if status.State == goose.StateApplied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%05d %-30s %-8s %s\n", status.Source.Version, migrations.Name(status.Source.Version), status.State, appliedAt)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// Функция, приводящая схему БД к заданной версии (накатывая или откатывая миграции)
+func migrateTo(ctx context.Context, provider *goose.Provider, version int64) error {
+	current, err := provider.GetDBVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	var results []*goose.MigrationResult
+	if version >= current {
+		results, err = provider.UpTo(ctx, version)
+	} else {
+		results, err = provider.DownTo(ctx, version)
+	}
+	printResults(results...)
+
+	// Отсутствие миграций для применения не является ошибкой (схема уже в нужной версии)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return nil
+	}
+	return err
+}
+
+// Функция, выводящая результаты применения миграций
+func printResults(results ...*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		fmt.Printf("%-4s %05d %-30s %s\n", result.Direction, result.Source.Version, migrations.Name(result.Source.Version), result.Duration)
+	}
+}
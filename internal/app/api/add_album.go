@@ -0,0 +1,140 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/models"
+	"mus_lib/storage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель с информацией об альбоме (для работы с request body)
+type requestBodyAlbum struct {
+	Group       string              `json:"group"`
+	Title       string              `json:"title"`
+	ReleaseDate string              `json:"releaseDate"`
+	CoverLink   string              `json:"coverLink"`
+	Tracks      []requestAlbumTrack `json:"tracks"`
+}
+
+// Модель трека альбома (для работы с request body)
+type requestAlbumTrack struct {
+	SongID      int64 `json:"songId"`
+	DiscNumber  int   `json:"discNumber"`
+	TrackNumber int   `json:"trackNumber"`
+}
+
+// AddAlbum godoc
+//	@Summary		AddAlbum
+//	@Tags			album
+//	@Description	Create album on given info (songs from track list are attached to album)
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		requestBodyAlbum	true	"Album info"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		201		{object}	responceCreated
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		409		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/albums [post]
+
+// Хэндлер для добавления альбома
+func (a *API) AddAlbum(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: AddAlbum api/albums'")
+
+	// Парсим request body
+	album, ok := a.bindRequestBodyAlbum(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: AddAlbum")
+
+	// Добавляем альбом в БД
+	err := a.storage.Album().AddAlbum(album)
+	if !a.handleAlbumWriteError(c, err) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusCreated, responceCreated{Message: fmt.Sprintf("Album successfully add. Group: %s, title: %s", album.Group, album.Title), ID: album.ID})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: AddAlbum api/albums' successfully done")
+}
+
+// Метод, считывающий и проверяющий request body с информацией об альбоме (в случае ошибки сам отвечает пользователю)
+func (a *API) bindRequestBodyAlbum(c *gin.Context) (*models.Album, bool) {
+	var reqAlbum requestBodyAlbum
+	err := c.ShouldBindJSON(&reqAlbum)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+		return nil, false
+	}
+	if reqAlbum.Group == "" || reqAlbum.Title == "" {
+		a.logger.Error("User provide uncorrected JSON: group or title is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: group and title value must be not empty"})
+		return nil, false
+	}
+	if reqAlbum.ReleaseDate != "" {
+		_, _, err = models.ParseReleaseDate(reqAlbum.ReleaseDate)
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: %s", err))
+			c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: releaseDate value must be a date in dd.mm.yyyy, mm.yyyy or yyyy format"})
+			return nil, false
+		}
+	}
+
+	album := &models.Album{
+		Group:       reqAlbum.Group,
+		Title:       reqAlbum.Title,
+		ReleaseDate: reqAlbum.ReleaseDate,
+		CoverLink:   reqAlbum.CoverLink,
+	}
+
+	// Список треков необязателен (nil означает, что треки не меняются)
+	if reqAlbum.Tracks != nil {
+		album.Tracks = make([]*models.AlbumTrack, 0, len(reqAlbum.Tracks))
+	}
+	for _, track := range reqAlbum.Tracks {
+		// Номер диска по умолчанию равен 1
+		if track.DiscNumber == 0 {
+			track.DiscNumber = 1
+		}
+		if track.SongID <= 0 || track.DiscNumber < 0 || track.TrackNumber <= 0 {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: track %+v", track))
+			c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: songId, discNumber and trackNumber value must be positive numbers"})
+			return nil, false
+		}
+
+		album.Tracks = append(album.Tracks, &models.AlbumTrack{SongID: track.SongID, DiscNumber: track.DiscNumber, TrackNumber: track.TrackNumber})
+	}
+
+	return album, true
+}
+
+// Метод, обрабатывающий ошибку добавления или изменения альбома (в случае ошибки сам отвечает пользователю)
+func (a *API) handleAlbumWriteError(c *gin.Context, err error) bool {
+	switch err {
+	case nil:
+		return true
+	case storage.ErrAlreadyExists:
+		a.logger.Info("User provide album that conflicts with existed data")
+		c.JSON(http.StatusConflict, errorMessage{"Album with such title already exists or track numbers are repeated"})
+	case storage.ErrBrokenReference:
+		a.logger.Info("User provide album with non existed songs")
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: some songs from track list do not exist"})
+	default:
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table albums): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/models"
+	"mus_lib/storage"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель с информацией об исполнителе (для работы с request body)
+type requestBodyArtist struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName"`
+	Country     string   `json:"country"`
+	FormedYear  *int     `json:"formedYear"`
+	Members     []string `json:"members"`
+}
+
+// AddArtist godoc
+//	@Summary		AddArtist
+//	@Tags			artist
+//	@Description	Create artist on given info
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		requestBodyArtist	true	"Artist info"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		201		{object}	responceCreated
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		409		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/artists [post]
+
+// Хэндлер для добавления исполнителя
+func (a *API) AddArtist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: AddArtist api/artists'")
+
+	// Парсим request body
+	artist, ok := a.bindRequestBodyArtist(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: AddArtist")
+
+	// Добавляем исполнителя в БД
+	err := a.storage.Artist().AddArtist(artist)
+	if err == storage.ErrAlreadyExists {
+		a.logger.Info(fmt.Sprintf("User trying to add existed artist. Name: %s", artist.Name))
+		c.JSON(http.StatusConflict, errorMessage{"You trying to add existed artist"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table artists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusCreated, responceCreated{Message: fmt.Sprintf("Artist successfully add. Name: %s", artist.Name), ID: artist.ID})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: AddArtist api/artists' successfully done")
+}
+
+// Метод, считывающий и проверяющий request body с информацией об исполнителе (в случае ошибки сам отвечает пользователю)
+func (a *API) bindRequestBodyArtist(c *gin.Context) (*models.Artist, bool) {
+	var reqArtist requestBodyArtist
+	err := c.ShouldBindJSON(&reqArtist)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+		return nil, false
+	}
+	if reqArtist.Name == "" {
+		a.logger.Error("User provide uncorrected JSON: name is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: name value must be not empty"})
+		return nil, false
+	}
+	if reqArtist.FormedYear != nil && (*reqArtist.FormedYear < 1000 || *reqArtist.FormedYear > time.Now().Year()) {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: formedYear is %d", *reqArtist.FormedYear))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: formedYear value must be a year not later than the current one"})
+		return nil, false
+	}
+
+	return &models.Artist{
+		Name:        reqArtist.Name,
+		DisplayName: reqArtist.DisplayName,
+		Country:     reqArtist.Country,
+		FormedYear:  reqArtist.FormedYear,
+		Members:     reqArtist.Members,
+	}, true
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель с информацией о плейлисте (для работы с request body)
+type requestBodyPlaylist struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Public      bool   `json:"public"`
+}
+
+// AddPlaylist godoc
+//	@Summary		AddPlaylist
+//	@Tags			playlist
+//	@Description	Create empty playlist owned by user
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		requestBodyPlaylist	true	"Playlist info"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		201		{object}	responceCreated
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/playlists [post]
+
+// Хэндлер для добавления плейлиста
+func (a *API) AddPlaylist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: AddPlaylist api/playlists'")
+
+	// Парсим request body
+	playlist, ok := a.bindRequestBodyPlaylist(c)
+	if !ok {
+		return
+	}
+	playlist.Owner = a.requester(c)
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: AddPlaylist")
+
+	// Добавляем плейлист в БД
+	err := a.storage.Playlist().AddPlaylist(playlist)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table playlists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusCreated, responceCreated{Message: fmt.Sprintf("Playlist successfully add. Name: %s", playlist.Name), ID: playlist.ID})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: AddPlaylist api/playlists' successfully done")
+}
+
+// Метод, считывающий и проверяющий request body с информацией о плейлисте (в случае ошибки сам отвечает пользователю)
+func (a *API) bindRequestBodyPlaylist(c *gin.Context) (*models.Playlist, bool) {
+	var reqPlaylist requestBodyPlaylist
+	err := c.ShouldBindJSON(&reqPlaylist)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+		return nil, false
+	}
+	reqPlaylist.Name = strings.TrimSpace(reqPlaylist.Name)
+	if reqPlaylist.Name == "" {
+		a.logger.Error("User provide uncorrected JSON: name is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: name value must be not empty"})
+		return nil, false
+	}
+
+	return &models.Playlist{Name: reqPlaylist.Name, Description: strings.TrimSpace(reqPlaylist.Description), Public: reqPlaylist.Public}, true
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"mus_lib/internal/app/models"
+	"mus_lib/internal/app/songinfo"
+	"mus_lib/storage"
 	"net/http"
 	"os"
 	"strings"
@@ -21,6 +23,21 @@ type responceMessage struct {
 	Message string `json:"message"`
 }
 
+// Модель ответа пользователю в случае успешного создания записи (содержит ее идентификатор)
+type responceCreated struct {
+	Message string `json:"message"`
+	ID      int64  `json:"id"`
+}
+
+// Модель ответа пользователю в случае успешного добавления песни (с предупреждением о похожих песнях, если они есть; при асинхронном добавлении - с идентификатором задания)
+type responceAddSong struct {
+	Message string                   `json:"message"`
+	ID      int64                    `json:"id"`
+	JobID   int64                    `json:"jobId,omitempty"`
+	Warning string                   `json:"warning,omitempty"`
+	Similar []*models.SongSuggestion `json:"similar,omitempty"`
+}
+
 // Модель ответа пользователю для указания ошибки
 type errorMessage struct {
 	Message string `json:"message"`
@@ -42,13 +59,6 @@ type requestBodySong struct {
 	Song  string `json:"song"`
 }
 
-// Модель с детальной информацией о песне, принимаемой со стороннего API (для работы с responce body)
-type externalSong struct {
-	ReleaseDate string `json:"releaseDate"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
-}
-
 // AddSong godoc
 //	@Summary		AddSong
 //	@Tags			song
@@ -56,9 +66,18 @@ type externalSong struct {
 //	@Accept			json
 //	@Produce		json
 //	@Param			input	body		requestBodySong	true	"Song info"
-//	@Success		201		{object}	responceMessage
+//	@Param			force	query		boolean	false	"Add song even if similar songs exist (DUPLICATE_MODE=reject)"
+//	@Param			async	query		boolean	false	"Add song immediately, its info is filled in by enrichment job (see /jobs/{id})"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		201		{object}	responceAddSong
+//	@Success		202		{object}	responceAddSong
 //	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		409		{object}	errorDuplicateMessage
 //	@Failure		500		{object}	responceMessage
+//	@Failure		502		{object}	responceMessage
+//	@Failure		503		{object}	responceMessage
 //	@Router			/song [post]
 
 // Хэндлер для добавления песни
@@ -75,6 +94,8 @@ func (a *API) AddSong(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, serverError)
 		return
 	}
+	// Пробелы по краям названий не должны порождать отдельных исполнителей и песен ("Muse " и "Muse")
+	reqSong.Group, reqSong.Song = strings.TrimSpace(reqSong.Group), strings.TrimSpace(reqSong.Song)
 	if reqSong.Group == "" || reqSong.Song == "" {
 		a.logger.Error("User provide uncorrected JSON: group or song is empty")
 		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: group and song value must be not empty"})
@@ -94,68 +115,112 @@ func (a *API) AddSong(c *gin.Context) {
 	}
 	// Если песня найдена
 	if err == nil {
-		a.logger.Info(fmt.Sprintf("User trying to add existed song. Group: %s, song: %s", reqSong.Group, reqSong.Song))
-		c.JSON(http.StatusBadRequest, errorMessage{"You trying to add existed song"})
+		a.existedSong(c, reqSong.Group, reqSong.Song)
 		return
 	}
 
-	// Логируем обращение к стороннему API (mock обращение)
-	a.logger.Debug("Sending a request to external API. Method: Get, path: localhost:8080/info")
+	// Ищем похожие песни (опечатки в названиях, лишние пробелы и т.п.)
+	similar, ok := a.findSimilarSongs(c, reqSong.Group, reqSong.Song, c.Query("force") == "true")
+	if !ok {
+		return
+	}
 
-	// Это mock обращение, чтобы сымитировать сторонний API (осуществляется 3 раза в случае истечения таймаута)
-	var responce *http.Response
-	for i := 0; i < 3; i++ {
-		resp, err := a.client.Get(fmt.Sprintf("http://localhost:8080/api/info?group=%s&song=%s", strings.Replace(reqSong.Group, " ", "+", -1), strings.Replace(reqSong.Song, " ", "+", -1)))
-		if err == nil {
-			responce = resp
-			break
-		}
-		if i == 2 {
-			a.logger.Error(fmt.Sprintf("Failed to fetch song data: %s", err))
-			c.JSON(http.StatusInternalServerError, serverError)
-			return
-		}
+	// Если пользователь не хочет ждать ответа стороннего API, добавляем песню сразу, а ее информацию заполнит воркер
+	if c.Query("async") == "true" {
+		a.addSongAsync(c, reqSong, similar)
+		return
 	}
-	defer responce.Body.Close()
 
-	// Проверяем статус ответа со стороннего сервера (если он равен 400, то скорее всего пользователь предоставил данные несуществующей песни, если он равен 500, то на их стороне какая-то ошибка с сервером) (mock обращение может выдать такие статусы, но эта проверка так же будет действительна и для настоящего стороннего сервера)
-	if responce.StatusCode == http.StatusBadRequest {
+	// Логируем обращение к стороннему API
+	a.logger.Debug("Sending a request to song info provider: GetSongInfo")
+
+	// Получаем детальную информацию о песне из стороннего API
+	detail, err := a.songInfo.GetSongInfo(c.Request.Context(), reqSong.Group, reqSong.Song)
+	if errors.Is(err, songinfo.ErrSongNotFound) {
 		a.logger.Error("Failed to fetch song data: song does not exist")
 		c.JSON(http.StatusBadRequest, errorMessage{"Song does not exist. Check the correctnes of the provided data"})
 		return
 	}
-	if responce.StatusCode == http.StatusInternalServerError {
-		a.logger.Error("Failed to fetch song data: server error on the external API side")
-		c.JSON(http.StatusInternalServerError, serverError)
+	if err != nil {
+		a.songInfoFailed(c, err)
 		return
 	}
 
-	// Парсим responce body, полученный из стороннего сервиса в нашу песню
-	var extSong externalSong
-	err = json.NewDecoder(responce.Body).Decode(&extSong)
+	// Создаем песню, которую будем добавлять в БД, из полученных данных (неизвестный формат даты релиза не должен мешать добавлению песни, поэтому она сохраняется пустой)
+	song := models.Song{Group: reqSong.Group, Song: reqSong.Song}
+	err = detail.ApplyTo(&song)
 	if err != nil {
-		a.logger.Error(fmt.Sprintf("Failed to read response body: %s", err))
+		a.logger.Warn(fmt.Sprintf("External API returned uncorrected release date, it will be stored empty: %s", err))
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: AddSong")
+
+	// Добавляем песню в БД (одновременно такую же песню мог добавить другой запрос, тогда ее отклонит уникальный индекс)
+	err = a.songStore(c).AddSong(&song)
+	if err == storage.ErrAlreadyExists {
+		a.existedSong(c, song.Group, song.Song)
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
 		c.JSON(http.StatusInternalServerError, serverError)
 		return
 	}
 
-	// Создаем песню, которую будем добавлять в БД, из полученных данных
-	song := models.Song{Group: reqSong.Group, Song: reqSong.Song, ReleaseDate: extSong.ReleaseDate, Text: strings.Split(extSong.Text, "\n\n"), Link: extSong.Link}
+	// Возвращаем пользователю сообщение об успешно выполненной операции (с предупреждением, если нашлись похожие песни)
+	result := responceAddSong{Message: fmt.Sprintf("Song successfully add. Group: %s, song: %s", song.Group, song.Song), ID: song.ID, Similar: similar}
+	if len(similar) > 0 {
+		result.Warning = "Library already contains similar songs. Check that this is not a duplicate"
+	}
+	c.JSON(http.StatusCreated, result)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: AddSong api/song' successfully done")
+}
+
+// Метод, отвечающий пользователю, что такая песня уже есть (названия сравниваются по ключу, без учета регистра и лишних пробелов)
+func (a *API) existedSong(c *gin.Context, group, song string) {
+	a.logger.Info(fmt.Sprintf("User trying to add existed song. Group: %s, song: %s", group, song))
+	c.JSON(http.StatusBadRequest, errorMessage{"You trying to add existed song"})
+}
+
+// Метод, отвечающий пользователю, что сторонний API недоступен или вернул некорректные данные
+func (a *API) songInfoFailed(c *gin.Context, err error) {
+	a.logger.Error(fmt.Sprintf("Failed to fetch song data: %s", err))
 
+	if errors.Is(err, songinfo.ErrCircuitOpen) {
+		c.JSON(http.StatusServiceUnavailable, errorMessage{"Song info service is temporarily unavailable. Try later"})
+		return
+	}
+	c.JSON(http.StatusBadGateway, errorMessage{"Song info service is unavailable or returned uncorrected data. Try later"})
+}
+
+// Метод, добавляющий песню без информации из стороннего API вместе с заданием на ее заполнение
+func (a *API) addSongAsync(c *gin.Context, reqSong requestBodySong, similar []*models.SongSuggestion) {
 	// Логируем обращение к БД
-	a.logger.Debug("Sending a request to DB: AddSong")
+	a.logger.Debug("Sending a request to DB: EnqueueSong")
 
-	// Добавляем песню в БД
-	err = a.storage.Song().AddSong(&song)
+	// Добавляем песню и задание в БД
+	song := models.Song{Group: reqSong.Group, Song: reqSong.Song, Text: []string{}}
+	job, err := a.jobStore(c).EnqueueSong(&song)
+	if err == storage.ErrAlreadyExists {
+		a.existedSong(c, song.Group, song.Song)
+		return
+	}
 	if err != nil {
 		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
 		c.JSON(http.StatusInternalServerError, serverError)
 		return
 	}
 
-	// Возвращаем пользователю сообщение об успешно выполненной операции
-	c.JSON(http.StatusCreated, responceMessage{fmt.Sprintf("Song successfully add. Group: %s, song: %s", song.Group, song.Song)})
+	// Возвращаем пользователю идентификаторы песни и задания, по которому можно отследить заполнение информации
+	result := responceAddSong{Message: fmt.Sprintf("Song accepted, its info will be filled in asynchronously. Group: %s, song: %s", song.Group, song.Song), ID: song.ID, JobID: job.ID, Similar: similar}
+	if len(similar) > 0 {
+		result.Warning = "Library already contains similar songs. Check that this is not a duplicate"
+	}
+	c.JSON(http.StatusAccepted, result)
 
 	// Логируем окончание запроса
-	a.logger.Info("Request 'POST: AddSong api/song' successfully done")
+	a.logger.Info("Request 'POST: AddSong api/song?async=true' successfully done")
 }
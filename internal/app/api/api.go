@@ -3,9 +3,11 @@ package api
 import (
 	"log"
 	"log/slog"
+	"mus_lib/internal/app/auth"
+	"mus_lib/internal/app/songinfo"
 	"mus_lib/storage"
-	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,10 +15,16 @@ import (
 // Инстанс нашего сервера
 type API struct {
 	// Поля неэкспортируемые (конфендициальная информация)
-	logger  *slog.Logger     // логер который будет использоваться в процессе работы сервера
-	router  *gin.Engine      // роутер который будет использоваться в процессе работы сервера (в нашем случае используем фреймворк gin)
-	storage *storage.Storage // БД, которая будет использоваться в процессе работы сервера
-	client  *http.Client // клиент, через который будут осуществляться обращения к стороннему серверу
+	logger   *slog.Logger      // логер который будет использоваться в процессе работы сервера
+	router   *gin.Engine       // роутер который будет использоваться в процессе работы сервера (в нашем случае используем фреймворк gin)
+	storage  storage.Store     // хранилище (Postgres или память), которое будет использоваться в процессе работы сервера
+	songInfo songinfo.Provider // источник детальной информации о песнях (сторонний API или фейковый провайдер)
+	tokens   *auth.Tokens      // выдача и проверка токенов, которые пользователи получают при входе
+
+	duplicates       duplicateCheck // настройки проверки добавляемых песен на почти-дубликаты
+	refreshAutoApply bool           // сохранять ли изменения, полученные при обновлении информации о песнях, без параметра apply=true
+	requireIfMatch   bool           // требовать ли заголовок If-Match при изменении и удалении песен
+	trashRetention   time.Duration  // сколько песни лежат в корзине, прежде чем удаляются окончательно
 }
 
 // Конструктор, возвращающий инстанс нашего сервера
@@ -34,16 +42,68 @@ func (api *API) ConfigureServer() error {
 	api.configureRouterField()
 	api.logger.Info("Router succsessfully configured")
 
-	// Настройка поля клиент
-	api.configureClientField()
-	api.logger.Info("Client succsessfully configured")
+	// Настройка источника информации о песнях
+	err := api.configureSongInfoField()
+	if err != nil {
+		return err
+	}
+	api.logger.Info("Song info provider succsessfully configured")
+
+	// Настройка проверки на почти-дубликаты
+	err = api.configureDuplicatesField()
+	if err != nil {
+		return err
+	}
+	api.logger.Info("Duplicate check succsessfully configured")
+
+	// Настройка обновления информации о песнях
+	err = api.configureRefreshField()
+	if err != nil {
+		return err
+	}
+	api.logger.Info("Song refresh succsessfully configured")
+
+	// Настройка проверки версий песен при изменении
+	err = api.configureIfMatchField()
+	if err != nil {
+		return err
+	}
+	api.logger.Info("Song version check succsessfully configured")
+
+	// Настройка выдачи токенов пользователей
+	err = api.configureAuthField()
+	if err != nil {
+		return err
+	}
+	api.logger.Info("Authentication succsessfully configured")
 
 	// Настройка поля с хранилищем
-	err := api.configureStorageField()
+	err = api.configureStorageField()
+	if err != nil {
+		return err
+	}
+	api.logger.Info("Storage connection succsessfully installed")
+
+	// Настройка кэша ответов стороннего API (может храниться в хранилище, поэтому настраивается после него)
+	err = api.configureSongInfoCache()
+	if err != nil {
+		return err
+	}
+	api.logger.Info("Song info cache succsessfully configured")
+
+	// Настройка окончательного удаления песен из корзины (удаляются из хранилища, поэтому настраивается после него)
+	err = api.configureTrashField()
+	if err != nil {
+		return err
+	}
+	api.logger.Info("Trash purge succsessfully configured")
+
+	// Запуск воркеров, заполняющих информацию о песнях, добавленных асинхронно
+	err = api.startEnrichmentWorkers()
 	if err != nil {
 		return err
 	}
-	api.logger.Info("DB connection succsessfully installed")
+	api.logger.Info("Enrichment workers succsessfully started")
 
 	// Сигнал о том, что настройка прошла успешно
 	api.logger.Info("Ready to start on port:" + os.Getenv("BIND_ADDR"))
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/auth"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель с названием API-ключа (для работы с request body)
+type requestBodyAPIKey struct {
+	Name string `json:"name"`
+}
+
+// Модель ответа пользователю в случае успешного создания API-ключа (сам ключ больше нигде не возвращается)
+type responceAPIKey struct {
+	Message string `json:"message"`
+	ID      int64  `json:"id"`
+	Key     string `json:"key"`
+	Prefix  string `json:"prefix"`
+}
+
+// Модель ответа пользователю для возвращения API-ключей
+type responceAllAPIKeys struct {
+	Keys []*models.APIKey `json:"keys"`
+}
+
+// AddAPIKey godoc
+//	@Summary		AddAPIKey
+//	@Tags			user
+//	@Description	Create long-lived API key for service accounts and scripts (key is shown only once, pass it in X-API-Key header)
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		requestBodyAPIKey	true	"Key name"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		201		{object}	responceAPIKey
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/auth/keys [post]
+
+// Хэндлер для создания API-ключа
+func (a *API) AddAPIKey(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: AddAPIKey api/auth/keys'")
+
+	// Парсим request body
+	var reqKey requestBodyAPIKey
+	err := c.ShouldBindJSON(&reqKey)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+		return
+	}
+	reqKey.Name = strings.TrimSpace(reqKey.Name)
+	if reqKey.Name == "" {
+		a.logger.Error("User provide uncorrected JSON: name is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: name value must be not empty"})
+		return
+	}
+
+	// Генерируем ключ (в хранилище попадает только его хэш)
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with generating API key: %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	apiKey := &models.APIKey{UserID: currentUser(c).ID, Name: reqKey.Name, Prefix: prefix, KeyHash: hash}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: AddAPIKey")
+
+	// Добавляем ключ в БД
+	err = a.storage.User().AddAPIKey(apiKey)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table api_keys): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю ключ
+	c.JSON(http.StatusCreated, responceAPIKey{Message: fmt.Sprintf("API key successfully created. Name: %s", apiKey.Name), ID: apiKey.ID, Key: key, Prefix: prefix})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: AddAPIKey api/auth/keys' successfully done")
+}
+
+// GetAPIKeys godoc
+//	@Summary		GetAPIKeys
+//	@Tags			user
+//	@Description	Retrieve API keys of user (without keys themselves)
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	responceAllAPIKeys
+//	@Failure		401	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/auth/keys [get]
+
+// Хэндлер для получения API-ключей пользователя
+func (a *API) GetAPIKeys(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetAPIKeys api/auth/keys'")
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetAPIKeys")
+
+	// Получаем ключи пользователя из БД
+	keys, err := a.storage.User().GetAPIKeys(currentUser(c).ID)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table api_keys): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю ключи
+	c.JSON(http.StatusOK, responceAllAPIKeys{Keys: keys})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetAPIKeys api/auth/keys' successfully done")
+}
+
+// DeleteAPIKey godoc
+//	@Summary		DeleteAPIKey
+//	@Tags			user
+//	@Description	Revoke API key of user by id
+//	@Produce		json
+//	@Param			id	path		integer	true	"API key id"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	responceMessage
+//	@Failure		400	{object}	responceMessage
+//	@Failure		401	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/auth/keys/{id} [delete]
+
+// Хэндлер для отзыва API-ключа
+func (a *API) DeleteAPIKey(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'DELETE: DeleteAPIKey api/auth/keys/:id'")
+
+	// Считываем идентификатор ключа
+	id, ok := a.bindPathID(c, "id", "API key")
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: DeleteAPIKey")
+
+	// Отзываем ключ (чужие ключи не видны, поэтому для них тоже 404)
+	err := a.storage.User().DeleteAPIKey(currentUser(c).ID, id)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to delete non existed API key. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to delete non existed API key"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table api_keys): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("API key successfully revoked. ID: %d", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'DELETE: DeleteAPIKey api/auth/keys/:id' successfully done")
+}
@@ -0,0 +1,777 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mus_lib/internal/app/auth"
+	"mus_lib/internal/app/models"
+	"mus_lib/internal/app/songinfo"
+	"mus_lib/storage"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+
+	os.Exit(m.Run())
+}
+
+// Функция, возвращающая сервер с хранилищем в памяти и фейковым провайдером информации о песнях
+func newTestAPI(t *testing.T) *API {
+	t.Helper()
+
+	a := &API{
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		storage:    storage.NewMemory(),
+		songInfo:   songinfo.NewFake(),
+		tokens:     auth.NewTokens(auth.Config{Secret: []byte("0123456789abcdef0123456789abcdef"), TTL: time.Hour}),
+		duplicates: duplicateCheck{mode: duplicateWarn, threshold: defaultDuplicateThreshold},
+	}
+	a.configureRouterField()
+
+	return a
+}
+
+// Функция, выполняющая запрос к серверу (header - пары название и значение заголовка)
+func serve(a *API, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, req)
+	return w
+}
+
+// Функция, возвращающая значение заголовка Authorization с токеном
+func bearer(token string) string {
+	return "Bearer " + token
+}
+
+// Функция, декодирующая JSON ответа
+func decode(t *testing.T, w *httptest.ResponseRecorder, value any) {
+	t.Helper()
+
+	err := json.Unmarshal(w.Body.Bytes(), value)
+	if err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", w.Body, err)
+	}
+}
+
+// Функция, регистрирующая пользователя и возвращающая его токен
+func loginTestUser(t *testing.T, a *API, name string) string {
+	t.Helper()
+
+	credentials := fmt.Sprintf(`{"name":%q,"password":"secretpass"}`, name)
+	w := serve(a, http.MethodPost, "/api/auth/register", credentials)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("register status = %d: %s", w.Code, w.Body)
+	}
+	w = serve(a, http.MethodPost, "/api/auth/login", credentials)
+	if w.Code != http.StatusOK {
+		t.Fatalf("login status = %d: %s", w.Code, w.Body)
+	}
+
+	var token responceToken
+	decode(t, w, &token)
+	return token.Token
+}
+
+// Функция, добавляющая песню прямо в хранилище и возвращающая ее идентификатор
+func addTestSong(t *testing.T, a *API, group, song string, text ...string) int64 {
+	t.Helper()
+
+	stored := &models.Song{Group: group, Song: song, Text: text, Link: "https://example.com/" + song}
+	err := a.storage.Song().AddSong(stored)
+	if err != nil {
+		t.Fatalf("AddSong() error = %v", err)
+	}
+
+	return stored.ID
+}
+
+// Функция, возвращающая путь к песне
+func songPath(id int64) string {
+	return fmt.Sprintf("/api/songs/%d", id)
+}
+
+// Функция, возвращающая песню по идентификатору
+func getTestSong(t *testing.T, a *API, id int64) *models.Song {
+	t.Helper()
+
+	w := serve(a, http.MethodGet, songPath(id), "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get song status = %d: %s", w.Code, w.Body)
+	}
+
+	var song models.Song
+	decode(t, w, &song)
+	return &song
+}
+
+func TestAddSong(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+
+	w := serve(a, http.MethodPost, "/api/song", `{"group":"Muse","song":"Uprising"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var added responceAddSong
+	decode(t, w, &added)
+
+	// Информация о песне берется из фейкового провайдера
+	if song := getTestSong(t, a, added.ID); song.Song != "Uprising" || song.ReleaseDate != "01.01.1990" || len(song.Text) != 3 {
+		t.Fatalf("song = %+v, want added song with its info", song)
+	}
+
+	w = serve(a, http.MethodPost, "/api/song", `{"group":"MUSE","song":"uprising"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("add existed song status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+// Провайдер информации о песнях, который всегда возвращает заданную ошибку
+type failingSongInfo struct {
+	err error
+}
+
+func (f failingSongInfo) GetSongInfo(ctx context.Context, group, song string) (*songinfo.SongDetail, error) {
+	return nil, f.err
+}
+
+func TestAddSongInfoProvider(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	fake := songinfo.NewFake()
+	fake.Default = nil
+	fake.Add("Muse", "Instrumental", songinfo.SongDetail{Link: "https://example.com/instrumental"})
+	a.songInfo = fake
+
+	// Пустой текст из стороннего API сохраняется как песня без куплетов
+	w := serve(a, http.MethodPost, "/api/song", `{"group":"Muse","song":"Instrumental"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var added responceAddSong
+	decode(t, w, &added)
+	if song := getTestSong(t, a, added.ID); len(song.Text) != 0 || song.Link != "https://example.com/instrumental" {
+		t.Errorf("song = %+v, want song without verses", song)
+	}
+
+	if w := serve(a, http.MethodPost, "/api/song", `{"group":"Muse","song":"Unknown"}`, "Authorization", bearer(token)); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown song status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	a.songInfo = failingSongInfo{songinfo.ErrUnavailable}
+	if w := serve(a, http.MethodPost, "/api/song", `{"group":"Muse","song":"Uprising"}`, "Authorization", bearer(token)); w.Code != http.StatusBadGateway {
+		t.Errorf("unavailable provider status = %d, want %d", w.Code, http.StatusBadGateway)
+	}
+
+	// Открытый выключатель означает, что сторонний API временно недоступен
+	a.songInfo = failingSongInfo{fmt.Errorf("%w: %w", songinfo.ErrUnavailable, songinfo.ErrCircuitOpen)}
+	if w := serve(a, http.MethodPost, "/api/song", `{"group":"Muse","song":"Uprising"}`, "Authorization", bearer(token)); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("open breaker status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestAddSongAsync(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+
+	// Песня сохраняется сразу, а ее информацию заполнит воркер
+	w := serve(a, http.MethodPost, "/api/song?async=true", `{"group":"Muse","song":"Uprising"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body)
+	}
+	var added responceAddSong
+	decode(t, w, &added)
+	if added.JobID == 0 {
+		t.Fatalf("responce = %+v, want job id", added)
+	}
+	if song := getTestSong(t, a, added.ID); song.Link != "" || len(song.Text) != 0 {
+		t.Errorf("song = %+v, want song without info", song)
+	}
+
+	w = serve(a, http.MethodGet, fmt.Sprintf("/api/jobs/%d", added.JobID), "")
+	var job models.Job
+	decode(t, w, &job)
+	if w.Code != http.StatusOK || job.SongID != added.ID || job.Status != models.JobPending {
+		t.Errorf("status = %d, job = %+v; want pending job of added song", w.Code, job)
+	}
+
+	w = serve(a, http.MethodGet, "/api/jobs?status=pending&offset=0&limit=10", "")
+	var jobs responceAllJobs
+	decode(t, w, &jobs)
+	if w.Code != http.StatusOK || len(jobs.Jobs) != 1 {
+		t.Errorf("status = %d, jobs = %+v; want one pending job", w.Code, jobs)
+	}
+
+	// Повторить можно только dead задание
+	if w := serve(a, http.MethodPost, fmt.Sprintf("/api/jobs/%d/retry", added.JobID), "", "Authorization", bearer(token)); w.Code != http.StatusConflict {
+		t.Errorf("retry pending job status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	for path, want := range map[string]int{
+		"/api/jobs/100": http.StatusNotFound,
+		"/api/jobs/abc": http.StatusBadRequest,
+		"/api/jobs?status=lost&offset=0&limit=10": http.StatusBadRequest,
+	} {
+		if w := serve(a, http.MethodGet, path, ""); w.Code != want {
+			t.Errorf("GET %s status = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestGetSongs(t *testing.T) {
+	a := newTestAPI(t)
+	addTestSong(t, a, "Muse", "Uprising", "one")
+	addTestSong(t, a, "Muse", "Starlight", "two")
+	addTestSong(t, a, "Queen", "Bohemian Rhapsody", "three")
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantSongs  []string
+	}{
+		{"all songs", "offset=0&limit=10", http.StatusOK, []string{"Uprising", "Starlight", "Bohemian Rhapsody"}},
+		{"offset and limit", "offset=1&limit=1", http.StatusOK, []string{"Starlight"}},
+		{"group", "offset=0&limit=10&group=muse", http.StatusOK, []string{"Uprising", "Starlight"}},
+		{"text", "offset=0&limit=10&text=THREE", http.StatusOK, []string{"Bohemian Rhapsody"}},
+		{"group prefix", "offset=0&limit=10&group=MU&groupMatch=prefix", http.StatusOK, []string{"Uprising", "Starlight"}},
+		{"song contains", "offset=0&limit=10&song=RISING&songMatch=contains", http.StatusOK, []string{"Uprising"}},
+		{"unknown match mode", "offset=0&limit=10&group=mu&groupMatch=regexp", http.StatusBadRequest, nil},
+		{"nothing found", "offset=0&limit=10&group=abba", http.StatusNotFound, nil},
+		{"without limit", "offset=0", http.StatusBadRequest, nil},
+		{"limit is not a number", "offset=0&limit=ten", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, http.MethodGet, "/api/songs?"+tt.query, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var songs responceAllSongs
+			decode(t, w, &songs)
+			got := make([]string, 0, len(songs.Songs))
+			for _, song := range songs.Songs {
+				got = append(got, song.Song)
+			}
+			if !reflect.DeepEqual(got, tt.wantSongs) {
+				t.Errorf("songs = %v, want %v", got, tt.wantSongs)
+			}
+		})
+	}
+}
+
+func TestGetSongsByReleaseDate(t *testing.T) {
+	a := newTestAPI(t)
+	for _, song := range []*models.Song{
+		{Group: "Nirvana", Song: "Lithium", ReleaseDate: "13.07.1992"},
+		{Group: "Nirvana", Song: "Polly"},
+		{Group: "Muse", Song: "Uprising", ReleaseDate: "2009"},
+		{Group: "Queen", Song: "Bohemian Rhapsody", ReleaseDate: "10.1975"},
+	} {
+		if err := a.storage.Song().AddSong(song); err != nil {
+			t.Fatalf("AddSong() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantSongs  []string
+	}{
+		{"whole period of date", "releaseDate=1992", http.StatusOK, []string{"Lithium"}},
+		{"range includes end period", "releasedFrom=1975&releasedTo=07.1992", http.StatusOK, []string{"Lithium", "Bohemian Rhapsody"}},
+		{"year", "year=2009", http.StatusOK, []string{"Uprising"}},
+		{"decade", "decade=1990s", http.StatusOK, []string{"Lithium"}},
+		{"sort by release date", "sort=releaseDate", http.StatusOK, []string{"Bohemian Rhapsody", "Lithium", "Uprising", "Polly"}},
+		{"sort by release date desc", "sort=releaseDate&order=desc", http.StatusOK, []string{"Uprising", "Lithium", "Bohemian Rhapsody", "Polly"}},
+		{"invalid date", "releasedFrom=31.02.1992", http.StatusBadRequest, nil},
+		{"invalid decade", "decade=1995", http.StatusBadRequest, nil},
+		{"unknown sort", "sort=title", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, http.MethodGet, "/api/songs?offset=0&limit=10&"+tt.query, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var songs responceAllSongs
+			decode(t, w, &songs)
+			got := make([]string, 0, len(songs.Songs))
+			for _, song := range songs.Songs {
+				got = append(got, song.Song)
+			}
+			if !reflect.DeepEqual(got, tt.wantSongs) {
+				t.Errorf("songs = %v, want %v", got, tt.wantSongs)
+			}
+		})
+	}
+}
+
+func TestSearchSongs(t *testing.T) {
+	a := newTestAPI(t)
+	addTestSong(t, a, "AC/DC", "Back In Black", "Back in black", "I hit the sack")
+	addTestSong(t, a, "Muse", "Uprising", "They will not force us", "Black and white")
+	addTestSong(t, a, "Queen", "Bohemian Rhapsody", "Is this the real life")
+
+	w := serve(a, http.MethodGet, "/api/songs/search?q=black+-white&offset=0&limit=10", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var found responceSearchSongs
+	decode(t, w, &found)
+	if len(found.Results) != 1 || found.Results[0].Song.Song != "Back In Black" {
+		t.Fatalf("results = %+v, want only back in black", found.Results)
+	}
+	if matches := found.Results[0].Matches; len(matches) != 1 || matches[0].Verse != 0 || matches[0].Snippet != "Back in <b>black</b>" {
+		t.Errorf("matches = %+v, want highlighted first verse", matches)
+	}
+
+	// Песни упорядочены по релевантности
+	w = serve(a, http.MethodGet, `/api/songs/search?q=black+or+"real+life"&offset=0&limit=10`, "")
+	decode(t, w, &found)
+	if len(found.Results) != 3 || found.Results[0].Song.Song != "Back In Black" {
+		t.Errorf("results = %+v, want three songs with back in black first", found.Results)
+	}
+
+	for query, want := range map[string]int{
+		"q=&offset=0&limit=10":       http.StatusBadRequest,
+		"q=black":                    http.StatusBadRequest,
+		"q=yellow&offset=0&limit=10": http.StatusNotFound,
+	} {
+		if w := serve(a, http.MethodGet, "/api/songs/search?"+query, ""); w.Code != want {
+			t.Errorf("search %q status = %d, want %d", query, w.Code, want)
+		}
+	}
+}
+
+func TestSuggestSongs(t *testing.T) {
+	a := newTestAPI(t)
+	addTestSong(t, a, "Muse", "Supermassive Black Hole")
+	addTestSong(t, a, "Muse", "Uprising")
+	addTestSong(t, a, "Queen", "Bohemian Rhapsody")
+
+	w := serve(a, http.MethodGet, "/api/songs/suggest?q=supermasive+blak+hole&offset=0&limit=10", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var found responceSuggestSongs
+	decode(t, w, &found)
+	if len(found.Suggestions) != 1 || found.Suggestions[0].Song.Song != "Supermassive Black Hole" {
+		t.Errorf("suggestions = %+v, want supermassive black hole", found.Suggestions)
+	}
+
+	// Запрос сравнивается и с исполнителем, и с названием песни
+	w = serve(a, http.MethodGet, "/api/songs/suggest?q=muse&offset=0&limit=10", "")
+	decode(t, w, &found)
+	if len(found.Suggestions) != 2 {
+		t.Errorf("suggestions = %+v, want both muse songs", found.Suggestions)
+	}
+
+	if w := serve(a, http.MethodGet, "/api/songs/suggest?q=abba&offset=0&limit=10", ""); w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := serve(a, http.MethodGet, "/api/songs/suggest?offset=0&limit=10", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("empty query status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAddSongDuplicates(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	addTestSong(t, a, "Muse", "Supermassive Black Hole")
+
+	// По умолчанию почти-дубликат добавляется с предупреждением
+	w := serve(a, http.MethodPost, "/api/song", `{"group":"Muse ","song":"Supermasive Black Hole"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var added responceAddSong
+	decode(t, w, &added)
+	if added.Warning == "" || len(added.Similar) != 1 {
+		t.Errorf("responce = %+v, want warning with one similar song", added)
+	}
+
+	// В режиме reject почти-дубликат отклоняется, если не передан force=true
+	a.duplicates.mode = duplicateReject
+	w = serve(a, http.MethodPost, "/api/song", `{"group":"Muse","song":"Supermassive Black Holes"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("reject status = %d, want %d: %s", w.Code, http.StatusConflict, w.Body)
+	}
+	var rejected errorDuplicateMessage
+	decode(t, w, &rejected)
+	if len(rejected.Similar) == 0 {
+		t.Errorf("similar = %+v, want similar songs", rejected.Similar)
+	}
+	if w := serve(a, http.MethodPost, "/api/song?force=true", `{"group":"Muse","song":"Supermassive Black Holes"}`, "Authorization", bearer(token)); w.Code != http.StatusCreated {
+		t.Errorf("force status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+
+	// Непохожая песня добавляется без предупреждения
+	w = serve(a, http.MethodPost, "/api/song", `{"group":"Muse","song":"Uprising"}`, "Authorization", bearer(token))
+	var unique responceAddSong
+	decode(t, w, &unique)
+	if w.Code != http.StatusCreated || unique.Warning != "" || len(unique.Similar) != 0 {
+		t.Errorf("status = %d, responce = %+v, want song added without warning", w.Code, unique)
+	}
+}
+
+func TestGetSongText(t *testing.T) {
+	a := newTestAPI(t)
+	addTestSong(t, a, "Muse", "Uprising", "one", "two", "three")
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantVerses []string
+	}{
+		{"all verses", "group=muse&song=uprising&offset=0&limit=10", http.StatusOK, []string{"one", "two", "three"}},
+		{"page of verses", "group=Muse&song=Uprising&offset=1&limit=1", http.StatusOK, []string{"two"}},
+		{"unknown song", "group=muse&song=starlight&offset=0&limit=10", http.StatusNotFound, nil},
+		{"without song", "group=muse&offset=0&limit=10", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, http.MethodGet, "/api/song/text?"+tt.query, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var text responceTextSong
+			decode(t, w, &text)
+			if !reflect.DeepEqual(text.Verses, tt.wantVerses) {
+				t.Errorf("verses = %v, want %v", text.Verses, tt.wantVerses)
+			}
+		})
+	}
+}
+
+func TestUpdateAndDeleteSong(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	addTestSong(t, a, "Muse", "Uprising", "one")
+
+	w := serve(a, http.MethodPut, "/api/song?group=muse&song=starlight", `{"group":"Muse","song":"Uprising (Live)"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("update of non existed song status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w = serve(a, http.MethodPut, "/api/song?group=muse&song=uprising", `{"group":"Muse","song":""}`, "Authorization", bearer(token))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("update with empty song status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w = serve(a, http.MethodPut, "/api/song?group=muse&song=uprising", `{"group":"Muse","song":"Uprising (Live)"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("update status = %d: %s", w.Code, w.Body)
+	}
+
+	w = serve(a, http.MethodDelete, "/api/song?group=muse&song=uprising", "", "Authorization", bearer(token))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("delete of renamed song by old name status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w = serve(a, http.MethodDelete, "/api/song?group=muse&song=uprising+(live)", "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d: %s", w.Code, w.Body)
+	}
+	if w = serve(a, http.MethodGet, "/api/songs?offset=0&limit=10", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get songs after delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSongRoutesByID(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "one", "two", "three")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"get", http.MethodGet, songPath(id), "", http.StatusOK},
+		{"get unknown song", http.MethodGet, songPath(id + 1), "", http.StatusNotFound},
+		{"get with uncorrected id", http.MethodGet, "/api/songs/abc", "", http.StatusBadRequest},
+		{"get with negative id", http.MethodGet, "/api/songs/-1", "", http.StatusBadRequest},
+		{"text", http.MethodGet, songPath(id) + "/text?offset=1&limit=1", "", http.StatusOK},
+		{"text without limit", http.MethodGet, songPath(id) + "/text?offset=1", "", http.StatusBadRequest},
+		{"text of unknown song", http.MethodGet, songPath(id+1) + "/text?offset=0&limit=1", "", http.StatusNotFound},
+		{"put without song", http.MethodPut, songPath(id), `{"group":"Muse"}`, http.StatusBadRequest},
+		{"put unknown song", http.MethodPut, songPath(id + 1), `{"group":"Muse","song":"Starlight"}`, http.StatusNotFound},
+		{"patch with empty group", http.MethodPatch, songPath(id), `{"group":""}`, http.StatusBadRequest},
+		{"delete unknown song", http.MethodDelete, songPath(id + 1), "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, tt.method, tt.path, tt.body, "Authorization", bearer(token))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateAndDeleteSongByID(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "one")
+
+	w := serve(a, http.MethodPut, songPath(id), `{"group":"Queen","song":"Starlight"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("put status = %d: %s", w.Code, w.Body)
+	}
+	// PATCH меняет только переданные поля
+	w = serve(a, http.MethodPatch, songPath(id), `{"song":"Uprising"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch status = %d: %s", w.Code, w.Body)
+	}
+	if song := getTestSong(t, a, id); song.ID != id || song.Group != "Queen" || song.Song != "Uprising" {
+		t.Errorf("song = %+v, want Queen - Uprising with id %d", song, id)
+	}
+
+	// Старые маршруты работают как псевдонимы для той же песни
+	w = serve(a, http.MethodGet, "/api/song/text?group=queen&song=uprising&offset=0&limit=1", "")
+	if w.Code != http.StatusOK {
+		t.Errorf("legacy text status = %d: %s", w.Code, w.Body)
+	}
+
+	w = serve(a, http.MethodDelete, songPath(id), "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d: %s", w.Code, w.Body)
+	}
+	if w = serve(a, http.MethodGet, songPath(id), ""); w.Code != http.StatusNotFound {
+		t.Errorf("get deleted song status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "one", "two", "three")
+
+	w := serve(a, http.MethodDelete, songPath(id), "", "If-Match", `"2"`, "Authorization", bearer(token))
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("delete with stale version status = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+	w = serve(a, http.MethodDelete, songPath(id), "", "If-Match", `"1"`, "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d: %s", w.Code, w.Body)
+	}
+
+	// Удаленная песня пропадает из библиотеки, но остается в корзине
+	if w = serve(a, http.MethodGet, songPath(id), ""); w.Code != http.StatusNotFound {
+		t.Errorf("get deleted song status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w = serve(a, http.MethodGet, "/api/trash?offset=0&limit=10", "")
+	var trash responceAllSongs
+	decode(t, w, &trash)
+	if len(trash.Songs) != 1 || trash.Songs[0].ID != id {
+		t.Fatalf("trash = %+v, want deleted song", trash.Songs)
+	}
+
+	w = serve(a, http.MethodPost, songPath(id)+"/restore", "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("restore status = %d: %s", w.Code, w.Body)
+	}
+	if song := getTestSong(t, a, id); song.Song != "Uprising" || len(song.Text) != 3 {
+		t.Errorf("restored song = %v, want song with its info", song)
+	}
+
+	w = serve(a, http.MethodPost, songPath(id)+"/restore", "", "Authorization", bearer(token))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("restore of song not in trash status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestRestoreConflictsWithNewSong(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising")
+
+	w := serve(a, http.MethodDelete, songPath(id), "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d: %s", w.Code, w.Body)
+	}
+	addTestSong(t, a, "MUSE", "UPRISING")
+
+	w = serve(a, http.MethodPost, songPath(id)+"/restore", "", "Authorization", bearer(token))
+	if w.Code != http.StatusConflict {
+		t.Errorf("restore status = %d, want %d: %s", w.Code, http.StatusConflict, w.Body)
+	}
+}
+
+func TestArtists(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	songID := addTestSong(t, a, "Muse", "Uprising", "one")
+	song := getTestSong(t, a, songID)
+
+	// Исполнитель создается вместе с первой его песней
+	w := serve(a, http.MethodGet, fmt.Sprintf("/api/artists/%d", song.ArtistID), "")
+	var artist models.Artist
+	decode(t, w, &artist)
+	if artist.Name != "Muse" || artist.DisplayName != "Muse" {
+		t.Fatalf("artist = %+v, want Muse", artist)
+	}
+
+	// Переименование исполнителя меняет все его песни
+	w = serve(a, http.MethodPut, fmt.Sprintf("/api/artists/%d", artist.ID), `{"name":"MUSE","country":"UK","members":["Matt Bellamy"]}`, "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("update artist status = %d: %s", w.Code, w.Body)
+	}
+	w = serve(a, http.MethodGet, fmt.Sprintf("/api/artists/%d/songs?offset=0&limit=10", artist.ID), "")
+	var songs responceAllSongs
+	decode(t, w, &songs)
+	if len(songs.Songs) != 1 || songs.Songs[0].ID != songID || songs.Songs[0].Group != "MUSE" {
+		t.Errorf("artist songs = %+v, want song %d", songs.Songs, songID)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"add existed artist", http.MethodPost, "/api/artists", `{"name":"Muse"}`, http.StatusConflict},
+		{"add artist without name", http.MethodPost, "/api/artists", `{"country":"UK"}`, http.StatusBadRequest},
+		{"add artist formed in future", http.MethodPost, "/api/artists", `{"name":"Future","formedYear":3000}`, http.StatusBadRequest},
+		{"add artist", http.MethodPost, "/api/artists", `{"name":"Queen","formedYear":1970}`, http.StatusCreated},
+		{"rename to existed artist", http.MethodPut, fmt.Sprintf("/api/artists/%d", artist.ID), `{"name":"queen"}`, http.StatusConflict},
+		{"delete artist with songs", http.MethodDelete, fmt.Sprintf("/api/artists/%d", artist.ID), "", http.StatusConflict},
+		{"songs of unknown artist", http.MethodGet, "/api/artists/99/songs?offset=0&limit=10", "", http.StatusNotFound},
+		{"delete unknown artist", http.MethodDelete, "/api/artists/99", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, tt.method, tt.path, tt.body, "Authorization", bearer(token))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+
+	// Песня в корзине еще ссылается на исполнителя, а после очистки корзины исполнителя без песен можно удалить
+	w = serve(a, http.MethodDelete, songPath(songID), "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete song status = %d: %s", w.Code, w.Body)
+	}
+	w = serve(a, http.MethodDelete, fmt.Sprintf("/api/artists/%d", artist.ID), "", "Authorization", bearer(token))
+	if w.Code != http.StatusConflict {
+		t.Errorf("delete artist with trashed song status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if _, err := a.storage.Song().PurgeDeletedSongs(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("PurgeDeletedSongs() error = %v", err)
+	}
+	w = serve(a, http.MethodDelete, fmt.Sprintf("/api/artists/%d", artist.ID), "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Errorf("delete artist status = %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestAlbums(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	first := addTestSong(t, a, "Muse", "Uprising")
+	second := addTestSong(t, a, "Muse", "Resistance")
+	bonus := addTestSong(t, a, "Muse", "Exogenesis")
+
+	body := fmt.Sprintf(`{"group":"Muse","title":"The Resistance","tracks":[{"songId":%d,"discNumber":2,"trackNumber":1},{"songId":%d,"discNumber":1,"trackNumber":2},{"songId":%d,"discNumber":1,"trackNumber":1}]}`, bonus, second, first)
+	w := serve(a, http.MethodPost, "/api/albums", body, "Authorization", bearer(token))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add album status = %d: %s", w.Code, w.Body)
+	}
+	var created responceCreated
+	decode(t, w, &created)
+
+	// И альбом, и фильтр песен по альбому возвращают песни в порядке треков
+	w = serve(a, http.MethodGet, fmt.Sprintf("/api/albums/%d", created.ID), "")
+	var album models.Album
+	decode(t, w, &album)
+	var tracks []int64
+	for _, track := range album.Tracks {
+		tracks = append(tracks, track.SongID)
+	}
+	if want := []int64{first, second, bonus}; !reflect.DeepEqual(tracks, want) {
+		t.Errorf("album tracks = %v, want %v", tracks, want)
+	}
+
+	w = serve(a, http.MethodGet, fmt.Sprintf("/api/songs?offset=0&limit=10&albumId=%d", created.ID), "")
+	var songs responceAllSongs
+	decode(t, w, &songs)
+	var ids []int64
+	for _, song := range songs.Songs {
+		ids = append(ids, song.ID)
+	}
+	if want := []int64{first, second, bonus}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("album songs = %v, want %v", ids, want)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"repeated track number", http.MethodPost, "/api/albums", fmt.Sprintf(`{"group":"Muse","title":"Live","tracks":[{"songId":%d,"discNumber":1,"trackNumber":1},{"songId":%d,"discNumber":1,"trackNumber":1}]}`, first, second), http.StatusConflict},
+		{"unknown song", http.MethodPost, "/api/albums", `{"group":"Muse","title":"Live","tracks":[{"songId":99,"discNumber":1,"trackNumber":1}]}`, http.StatusBadRequest},
+		{"uncorrected album id filter", http.MethodGet, "/api/songs?offset=0&limit=10&albumId=abc", "", http.StatusBadRequest},
+		{"unknown album", http.MethodGet, "/api/albums/99", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, tt.method, tt.path, tt.body, "Authorization", bearer(token))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+
+	// После удаления альбома песни остаются в библиотеке без альбома
+	w = serve(a, http.MethodDelete, fmt.Sprintf("/api/albums/%d", created.ID), "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete album status = %d: %s", w.Code, w.Body)
+	}
+	if song := getTestSong(t, a, first); song.AlbumID != nil || song.TrackNumber != nil {
+		t.Errorf("song = %+v, want song without album", song)
+	}
+}
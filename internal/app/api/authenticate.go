@@ -0,0 +1,136 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/auth"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ключ, под которым пользователь, выполняющий запрос, хранится в контексте gin
+const userContextKey = "user"
+
+// Middleware, определяющее пользователя по заголовку Authorization: Bearer <токен или API-ключ> или X-API-Key
+// (запрос без этих заголовков выполняется анонимно, а с неверными учетными данными отклоняется, чтобы клиент не решил, что он вошел)
+func (a *API) authenticate(c *gin.Context) {
+	credentials := c.GetHeader("X-API-Key")
+	if credentials == "" {
+		authorization := c.GetHeader("Authorization")
+		if authorization == "" {
+			c.Next()
+			return
+		}
+
+		scheme, value, _ := strings.Cut(authorization, " ")
+		credentials = strings.TrimSpace(value)
+		if !strings.EqualFold(scheme, "Bearer") || credentials == "" {
+			a.logger.Info(fmt.Sprintf("User provide unsupported authorization scheme: %s", scheme))
+			a.abortUnauthorized(c, "Authorization header must use Bearer scheme with token or API key")
+			return
+		}
+	}
+
+	var user *models.User
+	var ok bool
+	if auth.IsAPIKey(credentials) {
+		user, ok = a.authenticateAPIKey(c, credentials)
+	} else {
+		user, ok = a.authenticateToken(c, credentials)
+	}
+	if !ok {
+		return
+	}
+
+	c.Set(userContextKey, user)
+	c.Next()
+}
+
+// Метод, определяющий пользователя по JWT (в случае ошибки сам отвечает пользователю)
+func (a *API) authenticateToken(c *gin.Context, token string) (*models.User, bool) {
+	claims, err := a.tokens.Parse(token)
+	if err == auth.ErrTokenExpired {
+		a.logger.Info("User provide expired token")
+		a.abortUnauthorized(c, "Token expired. Log in again")
+		return nil, false
+	}
+	var id int64
+	if err == nil {
+		id, err = claims.UserID()
+	}
+	if err != nil {
+		a.logger.Info(fmt.Sprintf("User provide invalid token: %s", err))
+		a.abortUnauthorized(c, "Invalid token")
+		return nil, false
+	}
+
+	// Подпись подтверждает, что токен выдан этим сервером, поэтому БД не нужна
+	return &models.User{ID: id, Name: claims.Name}, true
+}
+
+// Метод, определяющий пользователя по API-ключу (в случае ошибки сам отвечает пользователю)
+func (a *API) authenticateAPIKey(c *gin.Context, key string) (*models.User, bool) {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: FindUserByAPIKey")
+
+	// Ищем владельца ключа в БД
+	user, err := a.storage.User().FindUserByAPIKey(auth.HashAPIKey(key))
+	if err == sql.ErrNoRows {
+		a.logger.Info("User provide unknown or revoked API key")
+		a.abortUnauthorized(c, "Invalid API key")
+		return nil, false
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table api_keys): %s", err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, serverError)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// Middleware, отклоняющее изменяющие запросы (все, кроме GET, HEAD и OPTIONS) анонимных клиентов
+func (a *API) requireUserForWrites(c *gin.Context) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		c.Next()
+	default:
+		a.requireUser(c)
+	}
+}
+
+// Middleware, отклоняющее запросы анонимных клиентов
+func (a *API) requireUser(c *gin.Context) {
+	if currentUser(c) == nil {
+		a.logger.Info(fmt.Sprintf("Anonymous user trying to do '%s: %s'", c.Request.Method, c.FullPath()))
+		a.abortUnauthorized(c, "Authentication required: log in via POST /api/auth/login or provide API key")
+		return
+	}
+
+	c.Next()
+}
+
+// Метод, прерывающий запрос ответом 401 (заголовок WWW-Authenticate подсказывает клиенту, как пройти аутентификацию)
+func (a *API) abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="mus_lib"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, errorMessage{message})
+}
+
+// Функция, возвращающая пользователя, выполняющего запрос (nil для анонимного клиента)
+func currentUser(c *gin.Context) *models.User {
+	user, _ := c.Get(userContextKey)
+	found, _ := user.(*models.User)
+	return found
+}
+
+// Метод, возвращающий имя пользователя, от имени которого выполняется запрос (пустое для анонимного клиента)
+func (a *API) requester(c *gin.Context) string {
+	if user := currentUser(c); user != nil {
+		return user.Name
+	}
+
+	return ""
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/auth"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAuthentication(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	expired := auth.NewTokens(auth.Config{Secret: []byte("0123456789abcdef0123456789abcdef"), TTL: -time.Minute})
+	expiredToken, _ := expired.Issue(1, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     []string
+		wantStatus int
+	}{
+		{"anonymous read", http.MethodGet, songPath(id), nil, http.StatusOK},
+		{"anonymous write", http.MethodPost, "/api/song", nil, http.StatusUnauthorized},
+		{"anonymous current user", http.MethodGet, "/api/auth/me", nil, http.StatusUnauthorized},
+		{"token", http.MethodGet, "/api/auth/me", []string{"Authorization", bearer(token)}, http.StatusOK},
+		{"invalid token on read", http.MethodGet, songPath(id), []string{"Authorization", bearer(token + "x")}, http.StatusUnauthorized},
+		{"expired token", http.MethodGet, "/api/auth/me", []string{"Authorization", bearer(expiredToken)}, http.StatusUnauthorized},
+		{"basic scheme", http.MethodGet, "/api/auth/me", []string{"Authorization", "Basic YWxpY2U6c2VjcmV0"}, http.StatusUnauthorized},
+		{"unknown api key", http.MethodGet, "/api/auth/me", []string{"X-API-Key", "ml_unknown"}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, tt.method, tt.path, `{"group":"Muse","song":"Starlight"}`, tt.header...)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestLoginWrongPassword(t *testing.T) {
+	a := newTestAPI(t)
+	loginTestUser(t, a, "alice")
+
+	for _, credentials := range []string{`{"name":"alice","password":"wrongpass"}`, `{"name":"bob","password":"secretpass"}`} {
+		w := serve(a, http.MethodPost, "/api/auth/login", credentials)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("login with %s status = %d, want %d", credentials, w.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+
+	w := serve(a, http.MethodPost, "/api/auth/keys", `{"name":"ci"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add api key status = %d: %s", w.Code, w.Body)
+	}
+	var key responceAPIKey
+	decode(t, w, &key)
+
+	// Ключ принимается и в X-API-Key, и в Authorization, а изменения записываются в историю от имени владельца ключа
+	w = serve(a, http.MethodPost, "/api/song", `{"group":"Muse","song":"Uprising"}`, "X-API-Key", key.Key)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add song with api key status = %d: %s", w.Code, w.Body)
+	}
+	w = serve(a, http.MethodGet, "/api/songs/1/history?offset=0&limit=10", "", "Authorization", bearer(key.Key))
+	if w.Code != http.StatusOK {
+		t.Fatalf("history status = %d: %s", w.Code, w.Body)
+	}
+	var history responceSongHistory
+	decode(t, w, &history)
+	if len(history.Revisions) != 1 || history.Revisions[0].Author != "alice" {
+		t.Errorf("revisions = %+v, want one revision by alice", history.Revisions)
+	}
+
+	// Отозванный ключ больше не действует
+	w = serve(a, http.MethodDelete, fmt.Sprintf("/api/auth/keys/%d", key.ID), "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("revoke api key status = %d: %s", w.Code, w.Body)
+	}
+	w = serve(a, http.MethodGet, "/api/auth/me", "", "X-API-Key", key.Key)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("revoked api key status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
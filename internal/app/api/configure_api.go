@@ -1,10 +1,16 @@
 package api
 
 import (
+	"context"
+	"expvar"
+	"fmt"
 	"log/slog"
+	"mus_lib/internal/app/auth"
+	"mus_lib/internal/app/enrichment"
+	"mus_lib/internal/app/songinfo"
 	"mus_lib/storage"
-	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	_ "mus_lib/docs"
@@ -19,42 +25,287 @@ func (api *API) configureLoggerField() {
 	api.logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
 }
 
-// Конфигурируем клиент сервера, через который будем обращаться к стороннему API
-func (api *API) configureClientField() {
-	http.DefaultClient = &http.Client{Timeout: 5 * time.Second}
-	api.client = http.DefaultClient
+// Конфигурируем источник информации о песнях (если адрес стороннего API не задан, используется фейковый провайдер)
+func (api *API) configureSongInfoField() error {
+	config, err := songinfo.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	if config.BaseURL == "" {
+		api.logger.Warn("SONG_INFO_URL is not set, fake song info provider will be used")
+		api.songInfo = songinfo.NewFake()
+		return nil
+	}
+
+	client, err := songinfo.NewClient(config)
+	if err != nil {
+		return err
+	}
+
+	api.songInfo = client
+	return nil
+}
+
+// Как часто из хранилища удаляются истекшие ответы стороннего API
+const songInfoCachePurgeInterval = time.Hour
+
+// Конфигурируем кэш перед источником информации о песнях
+func (api *API) configureSongInfoCache() error {
+	config, err := songinfo.CacheConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	switch config.Backend {
+	case songinfo.CacheOff:
+		return nil
+	case songinfo.CacheLRU:
+		api.songInfo = songinfo.NewCache(api.songInfo, songinfo.NewLRU(config.Size), config)
+	case songinfo.CacheStorage:
+		api.songInfo = songinfo.NewCache(api.songInfo, api.storage.SongInfoCache(), config)
+		go api.purgeSongInfoCache()
+	}
+
+	return nil
+}
+
+// Периодически удаляем истекшие ответы стороннего API из хранилища (работает до завершения процесса)
+func (api *API) purgeSongInfoCache() {
+	ticker := time.NewTicker(songInfoCachePurgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := api.storage.SongInfoCache().DeleteExpiredSongInfo()
+		if err != nil {
+			api.logger.Error(fmt.Sprintf("Trouble with purging song info cache: %s", err))
+			continue
+		}
+		api.logger.Debug(fmt.Sprintf("Expired song info cache entries purged: %d", deleted))
+	}
+}
+
+// Конфигурируем проверку добавляемых песен на почти-дубликаты
+func (api *API) configureDuplicatesField() error {
+	api.duplicates = duplicateCheck{mode: duplicateWarn, threshold: defaultDuplicateThreshold}
+
+	switch mode := duplicateMode(os.Getenv("DUPLICATE_MODE")); mode {
+	case "":
+	case duplicateOff, duplicateWarn, duplicateReject:
+		api.duplicates.mode = mode
+	default:
+		return fmt.Errorf("unknown duplicate mode: %s", mode)
+	}
+
+	if value := os.Getenv("DUPLICATE_SIMILARITY_THRESHOLD"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			return fmt.Errorf("uncorrected duplicate similarity threshold %q: must be a number in (0, 1]", value)
+		}
+		api.duplicates.threshold = threshold
+	}
+
+	return nil
+}
+
+// Конфигурируем обновление информации о песнях (по умолчанию изменения только показываются)
+func (api *API) configureRefreshField() error {
+	value := os.Getenv("REFRESH_AUTO_APPLY")
+	if value == "" {
+		return nil
+	}
+
+	autoApply, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("uncorrected refresh auto apply value %q: must be true or false", value)
+	}
+	api.refreshAutoApply = autoApply
+
+	return nil
+}
+
+// Конфигурируем проверку версий песен (по умолчанию If-Match учитывается, но не обязателен)
+func (api *API) configureIfMatchField() error {
+	value := os.Getenv("SONG_REQUIRE_IF_MATCH")
+	if value == "" {
+		return nil
+	}
+
+	require, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("uncorrected require If-Match value %q: must be true or false", value)
+	}
+	api.requireIfMatch = require
+
+	return nil
+}
+
+// Конфигурируем выдачу токенов (если секрет не задан, он генерируется и токены перестают действовать после перезапуска)
+func (api *API) configureAuthField() error {
+	config, err := auth.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	if len(config.Secret) == 0 {
+		api.logger.Warn("JWT_SECRET is not set, random secret will be used and tokens will not survive restart")
+		config.Secret, err = auth.RandomSecret()
+		if err != nil {
+			return err
+		}
+	}
+
+	api.tokens = auth.NewTokens(config)
+	return nil
+}
+
+// Сколько песни лежат в корзине по умолчанию и как часто из нее удаляются песни, срок хранения которых истек
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	trashPurgeInterval    = time.Hour
+)
+
+// Конфигурируем корзину и запускаем окончательное удаление песен, которые лежат в ней дольше срока хранения
+func (api *API) configureTrashField() error {
+	api.trashRetention = defaultTrashRetention
+
+	if value := os.Getenv("TRASH_RETENTION"); value != "" {
+		retention, err := time.ParseDuration(value)
+		if err != nil || retention <= 0 {
+			return fmt.Errorf("uncorrected trash retention %q: must be a positive duration (e.g. 720h)", value)
+		}
+		api.trashRetention = retention
+	}
+
+	go api.purgeTrash()
+
+	return nil
+}
+
+// Периодически удаляем из корзины песни, срок хранения которых истек (работает до завершения процесса)
+func (api *API) purgeTrash() {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := api.storage.Song().PurgeDeletedSongs(time.Now().Add(-api.trashRetention))
+		if err != nil {
+			api.logger.Error(fmt.Sprintf("Trouble with purging trash: %s", err))
+			continue
+		}
+		api.logger.Debug(fmt.Sprintf("Songs purged from trash: %d", deleted))
+	}
 }
 
 // Конфигурируем роутер сервера
 func (api *API) configureRouterField() {
 	router := gin.Default()
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggoFiles.Handler))
+	// Метрики приложения (в том числе обращений к стороннему API) в формате expvar
+	router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+
+	// Пользователь определяется для всех запросов, а анонимным клиентам доступно только чтение
+	// (регистрация и вход добавлены до requireUserForWrites, поэтому доступны без аутентификации)
+	apiGroup := router.Group("/api", api.authenticate)
+	apiGroup.POST("/auth/register", api.Register)
+	apiGroup.POST("/auth/login", api.Login)
+	apiGroup.Use(api.requireUserForWrites)
+	apiGroup.GET("/auth/me", api.requireUser, api.GetCurrentUser)
+	apiGroup.GET("/auth/keys", api.requireUser, api.GetAPIKeys)
+	apiGroup.POST("/auth/keys", api.AddAPIKey)
+	apiGroup.DELETE("/auth/keys/:id", api.DeleteAPIKey)
 
-	apiGroup := router.Group("/api")
 	apiGroup.GET("/songs", api.GetSongs)
+	apiGroup.GET("/songs/search", api.SearchSongs)
+	apiGroup.GET("/songs/suggest", api.SuggestSongs)
+	apiGroup.POST("/songs/refresh", api.RefreshSongs)
+	apiGroup.POST("/songs/import", api.ImportSongs)
+	apiGroup.GET("/songs/export", api.ExportSongs)
+	apiGroup.GET("/songs/:id", api.GetSong)
+	apiGroup.PUT("/songs/:id", api.UpdateSongByID)
+	apiGroup.PATCH("/songs/:id", api.PatchSong)
+	apiGroup.DELETE("/songs/:id", api.DeleteSongByID)
+	apiGroup.GET("/songs/:id/text", api.GetSongTextByID)
+	apiGroup.POST("/songs/:id/text", api.InsertVerse)
+	apiGroup.PUT("/songs/:id/text/order", api.ReorderVerses)
+	apiGroup.PUT("/songs/:id/text/:verse", api.ReplaceVerse)
+	apiGroup.DELETE("/songs/:id/text/:verse", api.DeleteVerse)
+	apiGroup.POST("/songs/:id/refresh", api.RefreshSong)
+	apiGroup.GET("/songs/:id/history", api.GetSongHistory)
+	apiGroup.GET("/songs/:id/history/:rev", api.GetSongRevision)
+	apiGroup.POST("/songs/:id/restore/:rev", api.RestoreSongRevision)
+	apiGroup.POST("/songs/:id/restore", api.UndeleteSong)
+	apiGroup.GET("/trash", api.GetTrash)
 	apiGroup.GET("/song/text", api.GetSongText)
 	apiGroup.DELETE("/song", api.DeleteSong)
 	apiGroup.PUT("/song", api.UpdateSong)
 	apiGroup.POST("/song", api.AddSong)
-	apiGroup.GET("/info", api.MockInfo)
+
+	apiGroup.GET("/artists", api.GetArtists)
+	apiGroup.POST("/artists", api.AddArtist)
+	apiGroup.GET("/artists/:id", api.GetArtist)
+	apiGroup.PUT("/artists/:id", api.UpdateArtist)
+	apiGroup.DELETE("/artists/:id", api.DeleteArtist)
+	apiGroup.GET("/artists/:id/songs", api.GetArtistSongs)
+
+	apiGroup.GET("/jobs", api.GetJobs)
+	apiGroup.GET("/jobs/:id", api.GetJob)
+	apiGroup.POST("/jobs/:id/retry", api.RetryJob)
+
+	apiGroup.GET("/albums", api.GetAlbums)
+	apiGroup.POST("/albums", api.AddAlbum)
+	apiGroup.GET("/albums/:id", api.GetAlbum)
+	apiGroup.PUT("/albums/:id", api.UpdateAlbum)
+	apiGroup.DELETE("/albums/:id", api.DeleteAlbum)
+
+	apiGroup.GET("/playlists", api.GetPlaylists)
+	apiGroup.POST("/playlists", api.AddPlaylist)
+	apiGroup.GET("/playlists/:id", api.GetPlaylist)
+	apiGroup.PUT("/playlists/:id", api.UpdatePlaylist)
+	apiGroup.DELETE("/playlists/:id", api.DeletePlaylist)
+	apiGroup.GET("/playlists/:id/export", api.ExportPlaylist)
+	apiGroup.POST("/playlists/:id/entries", api.AddPlaylistEntry)
+	apiGroup.DELETE("/playlists/:id/entries/:position", api.DeletePlaylistEntry)
+	apiGroup.POST("/playlists/:id/entries/:position/move", api.MovePlaylistEntry)
 
 	api.router = router
 }
 
-// Конфигурируем хранилище сервера и создаем в нем таблицу
+// Конфигурируем хранилище сервера и проверяем, что его схема актуальна
 func (api *API) configureStorageField() error {
-	storage := storage.New()
+	// Выбираем вид хранилища (по умолчанию Postgres)
+	var store storage.Store
+	switch storageType := os.Getenv("STORAGE_TYPE"); storageType {
+	case "", "postgres":
+		store = storage.New()
+	case "memory":
+		store = storage.NewMemory()
+	default:
+		return fmt.Errorf("unknown storage type: %s", storageType)
+	}
+
+	err := store.Open()
+	if err != nil {
+		return err
+	}
 
-	err := storage.Open()
+	err = store.CheckMigrations()
 	if err != nil {
+		store.Close()
 		return err
 	}
 
-	err = storage.CreateTable()
+	api.storage = store
+	return nil
+}
+
+// Запускаем пул воркеров, заполняющих информацию о песнях, добавленных асинхронно (работает до завершения процесса)
+func (api *API) startEnrichmentWorkers() error {
+	config, err := enrichment.ConfigFromEnv()
 	if err != nil {
 		return err
 	}
 
-	api.storage = storage
+	enrichment.NewPool(api.storage, api.songInfo, api.logger, config).Start(context.Background())
 	return nil
 }
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteAlbum godoc
+//	@Summary		DeleteAlbum
+//	@Tags			album
+//	@Description	Delete album by id (songs of album are kept)
+//	@Produce		json
+//	@Param			id	path		integer	true	"Album id"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	responceMessage
+//	@Failure		400	{object}	responceMessage
+//	@Failure		401	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/albums/{id} [delete]
+
+// Хэндлер для удаления альбома
+func (a *API) DeleteAlbum(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'DELETE: DeleteAlbum api/albums/:id'")
+
+	// Считываем идентификатор альбома
+	id, ok := a.bindPathID(c, "id", "album")
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: DeleteAlbum")
+
+	// Удаляем альбом
+	err := a.storage.Album().DeleteAlbum(id)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to delete non existed album. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to delete non existed album"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table albums): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Album successfully delete. ID: %d", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'DELETE: DeleteAlbum api/albums/:id' successfully done")
+}
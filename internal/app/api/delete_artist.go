@@ -0,0 +1,65 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/storage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteArtist godoc
+//	@Summary		DeleteArtist
+//	@Tags			artist
+//	@Description	Delete artist without songs by id
+//	@Produce		json
+//	@Param			id	path		integer	true	"Artist id"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	responceMessage
+//	@Failure		400	{object}	responceMessage
+//	@Failure		401	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		409	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/artists/{id} [delete]
+
+// Хэндлер для удаления исполнителя
+func (a *API) DeleteArtist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'DELETE: DeleteArtist api/artists/:id'")
+
+	// Считываем идентификатор исполнителя
+	id, ok := a.bindPathID(c, "id", "artist")
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: DeleteArtist")
+
+	// Удаляем исполнителя
+	err := a.storage.Artist().DeleteArtist(id)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to delete non existed artist. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to delete non existed artist"})
+		return
+	}
+	if err == storage.ErrInUse {
+		a.logger.Info(fmt.Sprintf("User trying to delete artist with songs. ID: %d", id))
+		c.JSON(http.StatusConflict, errorMessage{"You trying to delete artist that still has songs"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table artists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Artist successfully delete. ID: %d", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'DELETE: DeleteArtist api/artists/:id' successfully done")
+}
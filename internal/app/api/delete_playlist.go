@@ -0,0 +1,59 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeletePlaylist godoc
+//	@Summary		DeletePlaylist
+//	@Tags			playlist
+//	@Description	Delete playlist by id (songs are kept, only owner can delete playlist)
+//	@Produce		json
+//	@Param			id	path		integer	true	"Playlist id"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	responceMessage
+//	@Failure		400	{object}	responceMessage
+//	@Failure		401	{object}	responceMessage
+//	@Failure		403	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/playlists/{id} [delete]
+
+// Хэндлер для удаления плейлиста
+func (a *API) DeletePlaylist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'DELETE: DeletePlaylist api/playlists/:id'")
+
+	// Получаем плейлист, если клиенту можно его изменять
+	playlist, ok := a.findPlaylist(c, true)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: DeletePlaylist")
+
+	// Удаляем плейлист
+	err := a.storage.Playlist().DeletePlaylist(playlist.ID)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to delete non existed playlist. ID: %d", playlist.ID))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to delete non existed playlist"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table playlists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Playlist successfully delete. ID: %d", playlist.ID)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'DELETE: DeletePlaylist api/playlists/:id' successfully done")
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"database/sql"
 	"fmt"
+	"mus_lib/storage"
 	"net/http"
 	"os"
 
@@ -18,17 +19,23 @@ type queryStringSong struct {
 // DeleteSong godoc
 //	@Summary		DeleteSong
 //	@Tags			song
-//	@Description	Delete song on given info
+//	@Description	Move song on given info to trash (it is purged after TRASH_RETENTION)
 //	@Produce		json
 //	@Param			group	path		string	true	"Name of group"
-//	@Param			song	path		string	true	"Name of song"
-//	@Success		200		{object}	responceMessage
-//	@Failure		400		{object}	responceMessage
-//	@Failure		404		{object}	responceMessage
-//	@Failure		500		{object}	responceMessage
+//	@Param			song		path		string	true	"Name of song"
+//	@Param			If-Match	header		string	false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
 //	@Router			/song [delete]
 
-// Хэндлер для удаления песни
+// Хэндлер для удаления песни (псевдоним DeleteSongByID, песня ищется по названиям исполнителя и песни)
 func (a *API) DeleteSong(c *gin.Context) {
 	// Логируем начало выполнение запроса
 	a.logger.Info("User do 'DELETE: DeleteSong api/song'")
@@ -48,31 +55,20 @@ func (a *API) DeleteSong(c *gin.Context) {
 		return
 	}
 
-	// Логируем обращение к БД
-	a.logger.Debug("Sending a request to DB: CheckSong")
-
-	// Ищем песню в БД
-	err = a.storage.Song().CheckSong(qSong.Group, qSong.Song)
-	// Если песня не найдена
-	if err != nil && err == sql.ErrNoRows {
-		a.logger.Info(fmt.Sprintf("User trying to delete non existed song. Group: %s, song: %s", qSong.Group, qSong.Song))
-		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to delete non existed song"})
+	// Ищем идентификатор песни в БД
+	id, ok := a.findSongID(c, qSong.Group, qSong.Song, "delete")
+	if !ok {
 		return
 	}
-	if err != nil {
-		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
-		c.JSON(http.StatusInternalServerError, serverError)
+
+	// Считываем версию песни, которую пользователь собирается удалить
+	version, ok := a.expectedVersion(c, id)
+	if !ok {
 		return
 	}
 
-	// Логируем обращение к БД
-	a.logger.Debug("Sending a request to DB: DeleteSong")
-
 	// Если песня найдена, то удаляем ее
-	err = a.storage.Song().DeleteSong(qSong.Group, qSong.Song)
-	if err != nil {
-		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
-		c.JSON(http.StatusInternalServerError, serverError)
+	if !a.deleteSong(c, id, version) {
 		return
 	}
 
@@ -82,3 +78,76 @@ func (a *API) DeleteSong(c *gin.Context) {
 	// Логируем окончание запроса
 	a.logger.Info("Request 'DELETE: DeleteSong api/song' successfully done")
 }
+
+// DeleteSongByID godoc
+//	@Summary		DeleteSongByID
+//	@Tags			song
+//	@Description	Move song to trash by id (it is purged after TRASH_RETENTION)
+//	@Produce		json
+//	@Param			id			path		integer	true	"Song id"
+//	@Param			If-Match	header		string	false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/songs/{id} [delete]
+
+// Хэндлер для удаления песни по идентификатору
+func (a *API) DeleteSongByID(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'DELETE: DeleteSongByID api/songs/:id'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Считываем версию песни, которую пользователь собирается удалить
+	version, ok := a.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	// Удаляем песню
+	if !a.deleteSong(c, id, version) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Song successfully delete. ID: %d", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'DELETE: DeleteSongByID api/songs/:id' successfully done")
+}
+
+// Метод, удаляющий песню в корзину, если ее версия равна version (0 - без проверки; в случае ошибки сам отвечает пользователю)
+func (a *API) deleteSong(c *gin.Context, id int64, version int64) bool {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: DeleteSong")
+
+	err := a.songStore(c).DeleteSong(id, version)
+	// Если песня не найдена
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to delete non existed song. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to delete non existed song"})
+		return false
+	}
+	// Если песню успели изменить
+	if err == storage.ErrStaleVersion {
+		a.preconditionFailed(c, id)
+		return false
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return false
+	}
+
+	return true
+}
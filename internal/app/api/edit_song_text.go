@@ -0,0 +1,344 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/storage"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель с текстом куплета (для работы с request body)
+type requestBodyVerse struct {
+	Verse    string `json:"verse"`
+	Position *int   `json:"position"` // Номер, который получит вставляемый куплет (если не задан, куплет добавляется в конец)
+}
+
+// Модель с новым порядком куплетов (для работы с request body)
+type requestBodyVerseOrder struct {
+	Order []int `json:"order"` // Прежние номера куплетов в новом порядке
+}
+
+// Модель ответа пользователю в случае успешной вставки куплета (содержит его номер)
+type responceVerse struct {
+	Message string `json:"message"`
+	Verse   int    `json:"verse"`
+}
+
+// InsertVerse godoc
+//	@Summary		InsertVerse
+//	@Tags			song
+//	@Description	Insert verse into song's text at given position (verses are numbered from 0, without position verse is appended)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		integer				true	"Song id"
+//	@Param			If-Match	header		string				false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Param			input		body		requestBodyVerse	true	"Verse and its position"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		201			{object}	responceVerse
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/songs/{id}/text [post]
+
+// Хэндлер для вставки куплета в текст песни
+func (a *API) InsertVerse(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: InsertVerse api/songs/:id/text'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	reqVerse, ok := a.bindRequestBodyVerse(c)
+	if !ok {
+		return
+	}
+	position := -1
+	if reqVerse.Position != nil {
+		if *reqVerse.Position < 0 {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: position %d", *reqVerse.Position))
+			c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: position value must be a non-negative number"})
+			return
+		}
+		position = *reqVerse.Position
+	}
+
+	// Считываем версию песни, текст которой пользователь собирается изменить
+	expected, ok := a.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: InsertVerse")
+
+	// Вставляем куплет
+	position, version, err := a.songStore(c).InsertVerse(id, expected, position, reqVerse.Verse)
+	if err == storage.ErrOutOfRange {
+		a.logger.Info(fmt.Sprintf("User trying to insert verse after the end of text. ID: %d, position: %d", id, position))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: position value must not exceed the number of verses"})
+		return
+	}
+	if !a.handleVerseWriteError(c, id, version, err) {
+		return
+	}
+
+	// Возвращаем пользователю номер вставленного куплета
+	c.JSON(http.StatusCreated, responceVerse{Message: fmt.Sprintf("Verse successfully add. ID: %d", id), Verse: position})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: InsertVerse api/songs/:id/text' successfully done")
+}
+
+// ReplaceVerse godoc
+//	@Summary		ReplaceVerse
+//	@Tags			song
+//	@Description	Replace verse of song's text (verses are numbered from 0)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		integer				true	"Song id"
+//	@Param			verse		path		integer				true	"Verse number"
+//	@Param			If-Match	header		string				false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Param			input		body		requestBodyVerse	true	"New verse"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/songs/{id}/text/{verse} [put]
+
+// Хэндлер для замены куплета песни
+func (a *API) ReplaceVerse(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'PUT: ReplaceVerse api/songs/:id/text/:verse'")
+
+	// Считываем идентификатор песни и номер куплета
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+	position, ok := a.bindVerseNumber(c)
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	reqVerse, ok := a.bindRequestBodyVerse(c)
+	if !ok {
+		return
+	}
+
+	// Считываем версию песни, текст которой пользователь собирается изменить
+	expected, ok := a.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: ReplaceVerse")
+
+	// Заменяем куплет
+	version, err := a.songStore(c).ReplaceVerse(id, expected, position, reqVerse.Verse)
+	if !a.handleVerseWriteError(c, id, version, err) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Verse successfully update. ID: %d, verse: %d", id, position)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'PUT: ReplaceVerse api/songs/:id/text/:verse' successfully done")
+}
+
+// DeleteVerse godoc
+//	@Summary		DeleteVerse
+//	@Tags			song
+//	@Description	Delete verse of song's text (verses are numbered from 0, following verses are shifted)
+//	@Produce		json
+//	@Param			id			path		integer	true	"Song id"
+//	@Param			verse		path		integer	true	"Verse number"
+//	@Param			If-Match	header		string	false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/songs/{id}/text/{verse} [delete]
+
+// Хэндлер для удаления куплета песни
+func (a *API) DeleteVerse(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'DELETE: DeleteVerse api/songs/:id/text/:verse'")
+
+	// Считываем идентификатор песни и номер куплета
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+	position, ok := a.bindVerseNumber(c)
+	if !ok {
+		return
+	}
+
+	// Считываем версию песни, текст которой пользователь собирается изменить
+	expected, ok := a.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: DeleteVerse")
+
+	// Удаляем куплет
+	version, err := a.songStore(c).DeleteVerse(id, expected, position)
+	if !a.handleVerseWriteError(c, id, version, err) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Verse successfully delete. ID: %d, verse: %d", id, position)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'DELETE: DeleteVerse api/songs/:id/text/:verse' successfully done")
+}
+
+// ReorderVerses godoc
+//	@Summary		ReorderVerses
+//	@Tags			song
+//	@Description	Reorder verses of song's text (order lists every current verse number exactly once)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		integer					true	"Song id"
+//	@Param			If-Match	header		string					false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Param			input		body		requestBodyVerseOrder	true	"Current verse numbers in new order"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/songs/{id}/text/order [put]
+
+// Хэндлер для перестановки куплетов песни
+func (a *API) ReorderVerses(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'PUT: ReorderVerses api/songs/:id/text/order'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	var reqOrder requestBodyVerseOrder
+	err := c.ShouldBindJSON(&reqOrder)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil || reqOrder.Order == nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %v", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: order value must be an array of verse numbers"})
+		return
+	}
+
+	// Считываем версию песни, текст которой пользователь собирается изменить
+	expected, ok := a.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: ReorderVerses")
+
+	// Переставляем куплеты
+	version, err := a.songStore(c).ReorderVerses(id, expected, reqOrder.Order)
+	if err == storage.ErrOutOfRange {
+		a.logger.Info(fmt.Sprintf("User provide order that is not a permutation of verses. ID: %d, order: %v", id, reqOrder.Order))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: order must contain each verse number exactly once"})
+		return
+	}
+	if !a.handleVerseWriteError(c, id, version, err) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Verses successfully reorder. ID: %d", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'PUT: ReorderVerses api/songs/:id/text/order' successfully done")
+}
+
+// Метод, считывающий номер куплета из пути запроса (в случае ошибки сам отвечает пользователю)
+func (a *API) bindVerseNumber(c *gin.Context) (int, bool) {
+	position, err := strconv.Atoi(c.Param("verse"))
+	if err != nil || position < 0 {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected verse number in url: %s", c.Param("verse")))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected verse number: verse value must be a non-negative number"})
+		return 0, false
+	}
+
+	return position, true
+}
+
+// Метод, считывающий и проверяющий request body с куплетом (в случае ошибки сам отвечает пользователю)
+func (a *API) bindRequestBodyVerse(c *gin.Context) (requestBodyVerse, bool) {
+	var reqVerse requestBodyVerse
+	err := c.ShouldBindJSON(&reqVerse)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+		return reqVerse, false
+	}
+	if strings.TrimSpace(reqVerse.Verse) == "" {
+		a.logger.Error("User provide uncorrected JSON: verse is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: verse value must be not empty"})
+		return reqVerse, false
+	}
+
+	return reqVerse, true
+}
+
+// Метод, обрабатывающий результат изменения текста песни: при успехе выставляет новый ETag (в случае ошибки сам отвечает пользователю)
+func (a *API) handleVerseWriteError(c *gin.Context, id, version int64, err error) bool {
+	switch err {
+	case nil:
+		c.Header("ETag", songETag(version))
+		return true
+	case sql.ErrNoRows:
+		a.logger.Info(fmt.Sprintf("User trying to edit text of non existed song. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Song not found"})
+	case storage.ErrStaleVersion:
+		a.preconditionFailed(c, id)
+	case storage.ErrOutOfRange:
+		a.logger.Info(fmt.Sprintf("User trying to edit non existed verse. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Verse not found: song has fewer verses"})
+	default:
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestVerseEdits(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "one", "two")
+
+	w := serve(a, http.MethodPost, songPath(id)+"/text", `{"verse":"zero","position":0}`, "Authorization", bearer(token))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("insert status = %d: %s", w.Code, w.Body)
+	}
+	w = serve(a, http.MethodPost, songPath(id)+"/text", `{"verse":"three"}`, "Authorization", bearer(token))
+	var inserted responceVerse
+	decode(t, w, &inserted)
+	if inserted.Verse != 3 {
+		t.Errorf("inserted verse = %d, want 3", inserted.Verse)
+	}
+
+	if w := serve(a, http.MethodPut, songPath(id)+"/text/1", `{"verse":"ONE"}`, "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("replace status = %d: %s", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodDelete, songPath(id)+"/text/2", "", "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d: %s", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodPut, songPath(id)+"/text/order", `{"order":[2,0,1]}`, "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("reorder status = %d: %s", w.Code, w.Body)
+	}
+	if song := getTestSong(t, a, id); !reflect.DeepEqual(song.Text, []string{"three", "zero", "ONE"}) {
+		t.Errorf("text = %v, want [three zero ONE]", song.Text)
+	}
+}
+
+func TestVerseEditErrors(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "one", "two")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"empty verse", http.MethodPost, songPath(id) + "/text", `{"verse":""}`, http.StatusBadRequest},
+		{"negative position", http.MethodPost, songPath(id) + "/text", `{"verse":"x","position":-1}`, http.StatusBadRequest},
+		{"position after the end", http.MethodPost, songPath(id) + "/text", `{"verse":"x","position":3}`, http.StatusBadRequest},
+		{"uncorrected verse number", http.MethodPut, songPath(id) + "/text/first", `{"verse":"x"}`, http.StatusBadRequest},
+		{"missing verse", http.MethodDelete, songPath(id) + "/text/2", "", http.StatusNotFound},
+		{"missing song", http.MethodPut, songPath(id+1) + "/text/0", `{"verse":"x"}`, http.StatusNotFound},
+		{"partial order", http.MethodPut, songPath(id) + "/text/order", `{"order":[0]}`, http.StatusBadRequest},
+		{"repeated order", http.MethodPut, songPath(id) + "/text/order", `{"order":[0,0]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(a, tt.method, tt.path, tt.body, "Authorization", bearer(token)); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+
+	if song := getTestSong(t, a, id); !reflect.DeepEqual(song.Text, []string{"one", "two"}) {
+		t.Errorf("text after failed edits = %v, want [one two]", song.Text)
+	}
+}
+
+func TestVerseEditsIfMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		ifMatch    string
+		wantStatus int
+		wantETag   string
+	}{
+		{"insert", http.MethodPost, "/text", `{"verse":"new"}`, `"1"`, http.StatusCreated, `"2"`},
+		{"insert with stale version", http.MethodPost, "/text", `{"verse":"new"}`, `"5"`, http.StatusPreconditionFailed, ""},
+		{"replace", http.MethodPut, "/text/0", `{"verse":"new"}`, `"1"`, http.StatusOK, `"2"`},
+		{"replace with stale version", http.MethodPut, "/text/0", `{"verse":"new"}`, `"5"`, http.StatusPreconditionFailed, ""},
+		{"replace missing verse", http.MethodPut, "/text/9", `{"verse":"new"}`, "", http.StatusNotFound, ""},
+		{"delete", http.MethodDelete, "/text/2", "", `"1"`, http.StatusOK, `"2"`},
+		{"delete with stale version", http.MethodDelete, "/text/2", "", `"5"`, http.StatusPreconditionFailed, ""},
+		{"reorder", http.MethodPut, "/text/order", `{"order":[2,1,0]}`, "", http.StatusOK, `"2"`},
+		{"reorder with stale version", http.MethodPut, "/text/order", `{"order":[2,1,0]}`, `"5"`, http.StatusPreconditionFailed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAPI(t)
+			token := loginTestUser(t, a, "alice")
+			id := addTestSong(t, a, "Muse", "Uprising", "one", "two", "three")
+
+			w := serve(a, tt.method, songPath(id)+tt.path, tt.body, "If-Match", tt.ifMatch, "Authorization", bearer(token))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if etag := w.Header().Get("ETag"); etag != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", etag, tt.wantETag)
+			}
+		})
+	}
+}
+
+func TestVerseEditRequiresIfMatch(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	a.requireIfMatch = true
+	id := addTestSong(t, a, "Muse", "Uprising", "one", "two", "three")
+
+	w := serve(a, http.MethodDelete, songPath(id)+"/text/0", "", "Authorization", bearer(token))
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("status = %d, want %d: %s", w.Code, http.StatusPreconditionRequired, w.Body)
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Функция, возвращающая ETag песни по номеру ее версии
+func songETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// Функция, разбирающая список ETag из заголовка If-Match или If-None-Match (возвращает теги без кавычек, признаки слабых тегов и признак "*")
+func parseETags(header string) (tags []string, weak []bool, all bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if tag == "*" {
+			all = true
+			continue
+		}
+		isWeak := strings.HasPrefix(tag, "W/")
+		tags = append(tags, strings.Trim(strings.TrimPrefix(tag, "W/"), `"`))
+		weak = append(weak, isWeak)
+	}
+
+	return tags, weak, all
+}
+
+// Метод, отвечающий 304, если у клиента уже есть актуальная версия песни (If-None-Match сравнивается слабо), и выставляющий ETag
+func (a *API) notModified(c *gin.Context, version int64) bool {
+	c.Header("ETag", songETag(version))
+
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	tags, _, all := parseETags(header)
+	if !all && !slices.Contains(tags, strconv.FormatInt(version, 10)) {
+		return false
+	}
+
+	a.logger.Info(fmt.Sprintf("User already has actual version of song: %d", version))
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// Метод, возвращающий версию песни, которую ожидает клиент по заголовку If-Match (0 - без проверки; в случае ошибки сам отвечает пользователю)
+func (a *API) expectedVersion(c *gin.Context, id int64) (int64, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		if a.requireIfMatch {
+			a.logger.Info(fmt.Sprintf("User trying to change song without If-Match header. ID: %d", id))
+			c.JSON(http.StatusPreconditionRequired, errorMessage{"This request requires If-Match header with ETag of song"})
+			return 0, false
+		}
+		return 0, true
+	}
+
+	tags, weak, all := parseETags(header)
+	if all {
+		return 0, true
+	}
+
+	// If-Match сравнивается строго, поэтому слабые и не числовые теги не совпадают ни с одной версией
+	versions := make([]int64, 0, len(tags))
+	for i, tag := range tags {
+		version, err := strconv.ParseInt(tag, 10, 64)
+		if err == nil && version > 0 && !weak[i] {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 1 {
+		return versions[0], true
+	}
+
+	// Если клиент прислал несколько тегов, сверяем их с текущей версией песни и дальше требуем именно ее
+	if len(versions) > 1 {
+		song, ok := a.getSong(c, id)
+		if !ok {
+			return 0, false
+		}
+		if slices.Contains(versions, song.Version) {
+			return song.Version, true
+		}
+	}
+
+	a.preconditionFailed(c, id)
+	return 0, false
+}
+
+// Метод, отвечающий пользователю, что песню успели изменить после получения им ETag
+func (a *API) preconditionFailed(c *gin.Context, id int64) {
+	a.logger.Info(fmt.Sprintf("User trying to change song with stale ETag. ID: %d", id))
+	c.JSON(http.StatusPreconditionFailed, errorMessage{"Song was changed by another request: get it again and retry with actual ETag"})
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseETags(t *testing.T) {
+	tests := []struct {
+		header   string
+		wantTags []string
+		wantWeak []bool
+		wantAll  bool
+	}{
+		{``, nil, nil, false},
+		{`"3"`, []string{"3"}, []bool{false}, false},
+		{`W/"3"`, []string{"3"}, []bool{true}, false},
+		{` "1" , W/"2",, "3"`, []string{"1", "2", "3"}, []bool{false, true, false}, false},
+		{`*`, nil, nil, true},
+		{`"1", *`, []string{"1"}, []bool{false}, true},
+	}
+
+	for _, tt := range tests {
+		tags, weak, all := parseETags(tt.header)
+		if !reflect.DeepEqual(tags, tt.wantTags) || !reflect.DeepEqual(weak, tt.wantWeak) || all != tt.wantAll {
+			t.Errorf("parseETags(%q) = %v, %v, %v; want %v, %v, %v", tt.header, tags, weak, all, tt.wantTags, tt.wantWeak, tt.wantAll)
+		}
+	}
+}
+
+// Функция, возвращающая контекст gin для запроса с заголовком header: value
+func newTestContext(header, value string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if value != "" {
+		c.Request.Header.Set(header, value)
+	}
+
+	return c, recorder
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{``, false},
+		{`"2"`, false},
+		{`"3"`, true},
+		{`W/"3"`, true},
+		{`"1", "3"`, true},
+		{`*`, true},
+	}
+
+	a := newTestAPI(t)
+	for _, tt := range tests {
+		c, recorder := newTestContext("If-None-Match", tt.header)
+
+		got := a.notModified(c, 3)
+		c.Writer.WriteHeaderNow()
+		if got != tt.want {
+			t.Errorf("notModified() with If-None-Match %q = %v, want %v", tt.header, got, tt.want)
+		}
+		if etag := recorder.Header().Get("ETag"); etag != `"3"` {
+			t.Errorf("ETag = %q, want %q", etag, `"3"`)
+		}
+		if tt.want && recorder.Code != http.StatusNotModified {
+			t.Errorf("status = %d, want %d", recorder.Code, http.StatusNotModified)
+		}
+	}
+}
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		requireIfMatch bool
+		want           int64
+		wantOk         bool
+		wantStatus     int
+	}{
+		{"no header", ``, false, 0, true, 0},
+		{"no required header", ``, true, 0, false, http.StatusPreconditionRequired},
+		{"strong tag", `"3"`, false, 3, true, 0},
+		{"any version", `*`, true, 0, true, 0},
+		{"weak tag never matches", `W/"3"`, false, 0, false, http.StatusPreconditionFailed},
+		{"not a version", `"abc"`, false, 0, false, http.StatusPreconditionFailed},
+		{"several tags with actual version", `"5", "1"`, false, 1, true, 0},
+		{"several tags without actual version", `"5", "6"`, false, 0, false, http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAPI(t)
+			a.requireIfMatch = tt.requireIfMatch
+			id := addTestSong(t, a, "Muse", "Uprising")
+
+			c, recorder := newTestContext("If-Match", tt.header)
+			got, ok := a.expectedVersion(c, id)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("expectedVersion() = %d, %v; want %d, %v", got, ok, tt.want, tt.wantOk)
+			}
+			if !ok && recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetSongETag(t *testing.T) {
+	a := newTestAPI(t)
+	id := addTestSong(t, a, "Muse", "Uprising", "verse")
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{"without If-None-Match", "", http.StatusOK},
+		{"actual version", `"1"`, http.StatusNotModified},
+		{"weak actual version", `W/"1"`, http.StatusNotModified},
+		{"other version", `"2"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, http.MethodGet, songPath(id), "", "If-None-Match", tt.ifNoneMatch)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if etag := w.Header().Get("ETag"); etag != `"1"` {
+				t.Errorf("ETag = %q, want %q", etag, `"1"`)
+			}
+		})
+	}
+}
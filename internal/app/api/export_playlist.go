@@ -0,0 +1,66 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportPlaylist godoc
+//	@Summary		ExportPlaylist
+//	@Tags			playlist
+//	@Description	Export playlist for players in M3U8 or XSPF with artist, title and link of songs in order (songs in trash are skipped, m3u8 also skips songs without link). Response is gzipped if client accepts it
+//	@Produce		vnd.apple.mpegurl,xspf+xml
+//	@Param			id				path		integer	true	"Playlist id"
+//	@Param			format			query		string	false	"Format of export (default is m3u8)"	Enums(m3u8, xspf)
+//	@Param			Accept-Encoding	header		string	false	"gzip to compress response"
+//	@Success		200				{string}	string
+//	@Failure		400				{object}	responceMessage
+//	@Failure		404				{object}	responceMessage
+//	@Failure		500				{object}	responceMessage
+//	@Router			/playlists/{id}/export [get]
+
+// Хэндлер для выгрузки плейлиста в формате, который понимают проигрыватели
+func (a *API) ExportPlaylist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: ExportPlaylist api/playlists/:id/export'")
+
+	// Получаем плейлист, если клиенту можно его видеть
+	playlist, ok := a.findPlaylist(c, false)
+	if !ok {
+		return
+	}
+
+	// Считываем формат выгрузки (плейлист выгружается только в форматы плейлистов)
+	formatName := c.DefaultQuery("format", "m3u8")
+	if formatName != "m3u8" && formatName != "xspf" {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected playlist export format: %s", formatName))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: format must be m3u8 or xspf"})
+		return
+	}
+
+	// Записываем песни плейлиста по порядку (песни из корзины проигрывателю не нужны)
+	export := &songExport{c: c, format: exportFormats[formatName], formatName: formatName, fileName: fmt.Sprintf("playlist-%d", playlist.ID), title: playlist.Name,
+		gzip: acceptsGzip(c.GetHeader("Accept-Encoding"))}
+	for _, entry := range playlist.Entries {
+		if entry.Deleted {
+			continue
+		}
+
+		err := export.write(&models.Song{ID: entry.SongID, Group: entry.Group, Song: entry.Song, Link: entry.Link})
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("Export of playlist interrupted after %d songs: %s", export.count, err))
+			return
+		}
+	}
+	err := export.finish()
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Export of playlist interrupted after %d songs: %s", export.count, err))
+		return
+	}
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: ExportPlaylist api/playlists/:id/export' successfully done")
+}
@@ -0,0 +1,372 @@
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Размер буфера, через который песни пишутся в тело ответа выгрузки
+const exportBufferSize = 32 << 10
+
+// Формат выгрузки песен вместе с типом содержимого и расширением файла
+type exportFormat struct {
+	contentType string
+	extension   string
+}
+
+// Поддерживаемые форматы выгрузки (по умолчанию ndjson)
+var exportFormats = map[string]exportFormat{
+	"ndjson": {"application/x-ndjson", "ndjson"},
+	"csv":    {"text/csv; charset=utf-8", "csv"},
+	"json":   {"application/json; charset=utf-8", "json"},
+	"m3u8":   {"application/vnd.apple.mpegurl", "m3u8"},
+	"xspf":   {"application/xspf+xml", "xspf"},
+}
+
+// Интерфейс записи песен в тело ответа в одном из форматов выгрузки
+type songExportWriter interface {
+	begin() error                  // Пишет начало выгрузки (заголовок CSV, начало массива JSON)
+	write(song *models.Song) error // Пишет одну песню
+	end() error                    // Пишет конец выгрузки
+}
+
+// ExportSongs godoc
+//	@Summary		ExportSongs
+//	@Tags			song
+//	@Description	Stream all songs on given filter (filters of GET /songs are supported, offset and limit are not needed). Response is gzipped if client accepts it
+//	@Produce		x-ndjson,csv,json,vnd.apple.mpegurl,xspf+xml
+//	@Param			format			query		string	false	"Format of export (m3u8 and xspf are playlists for players, m3u8 skips songs without link)"	Enums(ndjson, csv, json, m3u8, xspf)
+//	@Param			Accept-Encoding	header		string	false	"gzip to compress response"
+//	@Param			group			query		string	false	"Name of group"
+//	@Param			song			query		string	false	"Name of song"
+//	@Success		200				{array}		models.Song
+//	@Failure		400				{object}	responceMessage
+//	@Failure		500				{object}	responceMessage
+//	@Router			/songs/export [get]
+
+// Хэндлер для потоковой выгрузки всех песен
+func (a *API) ExportSongs(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: ExportSongs api/songs/export'")
+
+	// Считываем формат выгрузки
+	formatName := c.DefaultQuery("format", "ndjson")
+	format, ok := exportFormats[formatName]
+	if !ok {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected export format: %s", formatName))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: format must be ndjson, csv, json, m3u8 or xspf"})
+		return
+	}
+
+	// Считываем фильтр из query string (выгружаются все подходящие песни, без пагинации)
+	filter, ok := a.bindSongFilter(c, false)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: ExportSongs")
+
+	// Песни пишутся в ответ по мере чтения из БД, поэтому в памяти не держится вся библиотека
+	export := &songExport{c: c, format: format, formatName: formatName, fileName: "songs", gzip: acceptsGzip(c.GetHeader("Accept-Encoding"))}
+	err := a.storage.Song().ExportSongs(filter, export.write)
+	if err == nil {
+		err = export.finish()
+	}
+	// Ошибка записи ответа (кодирования, сжатия или соединения с клиентом) не связана с БД
+	if export.writeErr != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with writing export response after %d songs: %s", export.count, export.writeErr))
+		return
+	}
+	if err != nil && !export.started {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	// После начала выгрузки статус ответа уже не изменить, поэтому ответ обрывается (у gzip и json не будет корректного конца)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s), export interrupted after %d songs: %s", os.Getenv("TABLE_NAME"), export.count, err))
+		return
+	}
+
+	// Логируем окончание запроса
+	a.logger.Info(fmt.Sprintf("Request 'GET: ExportSongs api/songs/export' successfully done. Songs: %d", export.count))
+}
+
+// Выгрузка песен в тело ответа
+type songExport struct {
+	c          *gin.Context
+	format     exportFormat
+	formatName string
+	fileName   string // Имя файла выгрузки без расширения
+	title      string // Название плейлиста в m3u8 и xspf (пустое - без названия)
+	gzip       bool   // Сжимать ли ответ (клиент прислал Accept-Encoding: gzip)
+	started    bool   // Заголовки ответа уже отправлены, ответить ошибкой больше нельзя
+	count      int    // Сколько песен уже записано
+	writeErr   error  // Ошибка записи ответа (отличает ее от ошибки чтения из БД)
+
+	buffer *bufio.Writer
+	zipper *gzip.Writer
+	writer songExportWriter
+}
+
+// Метод, задающий заголовки ответа и пишущий начало выгрузки (вызывается перед первой песней, чтобы до нее на ошибку БД можно было ответить 500)
+func (e *songExport) start() error {
+	e.started = true
+
+	header := e.c.Writer.Header()
+	header.Set("Content-Type", e.format.contentType)
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, e.fileName, e.format.extension))
+	header.Add("Vary", "Accept-Encoding")
+
+	var body io.Writer = e.c.Writer
+	if e.gzip {
+		header.Set("Content-Encoding", "gzip")
+		e.zipper = gzip.NewWriter(body)
+		body = e.zipper
+	}
+	e.buffer = bufio.NewWriterSize(body, exportBufferSize)
+	e.c.Status(http.StatusOK)
+
+	switch e.formatName {
+	case "csv":
+		e.writer = &csvExportWriter{writer: csv.NewWriter(e.buffer)}
+	case "json":
+		e.writer = &jsonExportWriter{body: e.buffer}
+	case "m3u8":
+		e.writer = &m3uExportWriter{body: e.buffer, title: e.title}
+	case "xspf":
+		e.writer = &xspfExportWriter{body: e.buffer, encoder: xml.NewEncoder(e.buffer), title: e.title}
+	default:
+		e.writer = &ndjsonExportWriter{encoder: json.NewEncoder(e.buffer)}
+	}
+
+	return e.failed(e.writer.begin())
+}
+
+// Метод, пишущий одну песню в ответ
+func (e *songExport) write(song *models.Song) error {
+	if !e.started {
+		err := e.start()
+		if err != nil {
+			return err
+		}
+	}
+	e.count++
+
+	return e.failed(e.writer.write(song))
+}
+
+// Метод, завершающий выгрузку и отправляющий остаток буфера (пустая выгрузка тоже отвечает 200)
+func (e *songExport) finish() error {
+	if !e.started {
+		err := e.start()
+		if err != nil {
+			return err
+		}
+	}
+
+	err := e.writer.end()
+	if err == nil {
+		err = e.buffer.Flush()
+	}
+	if err == nil && e.zipper != nil {
+		err = e.zipper.Close()
+	}
+
+	return e.failed(err)
+}
+
+// Метод, запоминающий ошибку записи ответа и возвращающий ее (чтобы прервать чтение песен из БД)
+func (e *songExport) failed(err error) error {
+	if err != nil {
+		e.writeErr = err
+	}
+
+	return err
+}
+
+// Функция, проверяющая, принимает ли клиент ответ, сжатый gzip (по заголовку Accept-Encoding)
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		if strings.TrimSpace(coding) != "gzip" {
+			continue
+		}
+
+		// gzip;q=0 означает, что сжатый ответ клиенту не нужен
+		name, value, _ := strings.Cut(strings.TrimSpace(params), "=")
+		if strings.TrimSpace(name) == "q" {
+			quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			return err == nil && quality > 0
+		}
+		return true
+	}
+
+	return false
+}
+
+// Запись песен в NDJSON (по одной песне в строке)
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonExportWriter) begin() error {
+	return nil
+}
+
+func (w *ndjsonExportWriter) write(song *models.Song) error {
+	return w.encoder.Encode(song)
+}
+
+func (w *ndjsonExportWriter) end() error {
+	return nil
+}
+
+// Запись песен в JSON (в том же виде, что и ответ GET /songs)
+type jsonExportWriter struct {
+	body    io.Writer
+	written bool
+}
+
+func (w *jsonExportWriter) begin() error {
+	_, err := io.WriteString(w.body, `{"songs":[`)
+	return err
+}
+
+func (w *jsonExportWriter) write(song *models.Song) error {
+	if w.written {
+		_, err := io.WriteString(w.body, ",")
+		if err != nil {
+			return err
+		}
+	}
+	w.written = true
+
+	data, err := json.Marshal(song)
+	if err != nil {
+		return err
+	}
+	_, err = w.body.Write(data)
+	return err
+}
+
+func (w *jsonExportWriter) end() error {
+	_, err := io.WriteString(w.body, "]}\n")
+	return err
+}
+
+// Запись песен в CSV с теми же колонками, что и у импорта (выгрузку можно загрузить обратно через POST /songs/import)
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvExportWriter) begin() error {
+	return w.writer.Write(songCSVColumns)
+}
+
+func (w *csvExportWriter) write(song *models.Song) error {
+	return w.writer.Write([]string{song.Group, song.Song, song.ReleaseDate, strings.Join(song.Text, csvVerseSeparator), song.Link})
+}
+
+func (w *csvExportWriter) end() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// Запись песен в плейлист M3U8 (#EXTINF с исполнителем и названием, затем ссылка; песни без ссылки проигрыватель не сможет открыть, поэтому они пропускаются)
+type m3uExportWriter struct {
+	body  io.Writer
+	title string
+}
+
+func (w *m3uExportWriter) begin() error {
+	header := "#EXTM3U\n"
+	if w.title != "" {
+		header += fmt.Sprintf("#PLAYLIST:%s\n", m3uLine(w.title))
+	}
+
+	_, err := io.WriteString(w.body, header)
+	return err
+}
+
+func (w *m3uExportWriter) write(song *models.Song) error {
+	if song.Link == "" {
+		return nil
+	}
+
+	// Длительность песни неизвестна, поэтому указывается -1
+	_, err := fmt.Fprintf(w.body, "#EXTINF:-1,%s - %s\n%s\n", m3uLine(song.Group), m3uLine(song.Song), m3uLine(song.Link))
+	return err
+}
+
+func (w *m3uExportWriter) end() error {
+	return nil
+}
+
+// Функция, заменяющая переводы строк пробелами (в M3U каждая запись занимает одну строку)
+func m3uLine(value string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(value)
+}
+
+// Модель трека в плейлисте XSPF
+type xspfTrack struct {
+	XMLName  xml.Name `xml:"track"`
+	Location string   `xml:"location,omitempty"`
+	Creator  string   `xml:"creator"`
+	Title    string   `xml:"title"`
+}
+
+// Запись песен в плейлист XSPF (песни без ссылки записываются без location, проигрыватель может найти их по исполнителю и названию)
+type xspfExportWriter struct {
+	body    io.Writer
+	encoder *xml.Encoder
+	title   string
+}
+
+func (w *xspfExportWriter) begin() error {
+	_, err := io.WriteString(w.body, xml.Header+`<playlist version="1" xmlns="http://xspf.org/ns/0/">`)
+	if err != nil {
+		return err
+	}
+	if w.title != "" {
+		err = w.encoder.EncodeElement(w.title, xml.StartElement{Name: xml.Name{Local: "title"}})
+		if err != nil {
+			return err
+		}
+	}
+	// Encoder буферизует вывод, поэтому перед прямой записью в body его нужно сбросить
+	err = w.encoder.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w.body, "<trackList>")
+	return err
+}
+
+func (w *xspfExportWriter) write(song *models.Song) error {
+	return w.encoder.Encode(xspfTrack{Location: song.Link, Creator: song.Group, Title: song.Song})
+}
+
+func (w *xspfExportWriter) end() error {
+	err := w.encoder.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w.body, "</trackList></playlist>\n")
+	return err
+}
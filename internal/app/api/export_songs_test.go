@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"log/slog"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"br", false},
+	}
+
+	for _, tt := range tests {
+		if got := acceptsGzip(tt.header); got != tt.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestExportSongs(t *testing.T) {
+	a := newTestAPI(t)
+	addTestSong(t, a, "Muse", "Uprising", "one", "two")
+	addTestSong(t, a, "Queen", "Bohemian Rhapsody", "three")
+
+	// NDJSON: по одной песне в строке, фильтры как у списка песен
+	w := serve(a, http.MethodGet, "/api/songs/export?group=muse", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("ndjson status = %d, content type = %q: %s", w.Code, w.Header().Get("Content-Type"), w.Body)
+	}
+	var lines []models.Song
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var song models.Song
+		if err := json.Unmarshal(scanner.Bytes(), &song); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error = %v", scanner.Bytes(), err)
+		}
+		lines = append(lines, song)
+	}
+	if len(lines) != 1 || lines[0].Song != "Uprising" || len(lines[0].Text) != 2 {
+		t.Errorf("ndjson songs = %+v, want Uprising", lines)
+	}
+
+	// JSON: массив песен в одном объекте
+	w = serve(a, http.MethodGet, "/api/songs/export?format=json", "")
+	var all responceAllSongs
+	decode(t, w, &all)
+	if len(all.Songs) != 2 {
+		t.Errorf("json songs = %+v, want 2 songs", all.Songs)
+	}
+
+	// CSV со сжатием gzip
+	w = serve(a, http.MethodGet, "/api/songs/export?format=csv", "", "Accept-Encoding", "gzip")
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", w.Header().Get("Content-Encoding"))
+	}
+	zipped, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	records, err := csv.NewReader(zipped).ReadAll()
+	if err != nil || len(records) != 3 || records[1][1] != "Uprising" {
+		t.Errorf("csv records = %q, %v; want header and 2 songs", records, err)
+	}
+
+	// Пустая выгрузка тоже успешна
+	if w := serve(a, http.MethodGet, "/api/songs/export?group=nirvana", ""); w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("empty export status = %d, body = %q", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodGet, "/api/songs/export?format=xml", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("uncorrected format status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+// Ответ, запись в который всегда завершается ошибкой (клиент закрыл соединение)
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w failingResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func (w failingResponseWriter) WriteString(string) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func TestExportSongsWriteError(t *testing.T) {
+	a := newTestAPI(t)
+	var logs bytes.Buffer
+	a.logger = slog.New(slog.NewTextHandler(&logs, nil))
+	for _, song := range []string{"Uprising", "Starlight", "Hysteria"} {
+		addTestSong(t, a, "Muse", song, strings.Repeat("verse ", exportBufferSize/4))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/songs/export", nil)
+	a.router.ServeHTTP(failingResponseWriter{httptest.NewRecorder()}, req)
+
+	// Ошибка записи ответа не выдается за ошибку БД
+	if !strings.Contains(logs.String(), "Trouble with writing export response") || strings.Contains(logs.String(), "Trouble with connecting to DB") {
+		t.Errorf("logs = %s, want only response write error", logs.String())
+	}
+}
+
+func TestExportSongsPlaylists(t *testing.T) {
+	a := newTestAPI(t)
+	addTestSong(t, a, "Muse", "Uprising")
+	addTestSong(t, a, "Queen", "Bohemian Rhapsody")
+	err := a.storage.Song().AddSong(&models.Song{Group: "Nirvana", Song: "Lithium"})
+	if err != nil {
+		t.Fatalf("AddSong() error = %v", err)
+	}
+
+	// M3U8: песни без ссылки пропускаются
+	w := serve(a, http.MethodGet, "/api/songs/export?format=m3u8", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/vnd.apple.mpegurl" {
+		t.Fatalf("m3u8 status = %d, content type = %q: %s", w.Code, w.Header().Get("Content-Type"), w.Body)
+	}
+	body := w.Body.String()
+	if !strings.HasPrefix(body, "#EXTM3U\n") || !strings.Contains(body, "#EXTINF:-1,Muse - Uprising\nhttps://example.com/Uprising\n") || strings.Contains(body, "Lithium") {
+		t.Errorf("m3u8 body = %q", body)
+	}
+
+	// XSPF: песни без ссылки записываются без location
+	w = serve(a, http.MethodGet, "/api/songs/export?format=xspf", "")
+	var playlist struct {
+		Tracks []xspfTrack `xml:"trackList>track"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &playlist); err != nil {
+		t.Fatalf("xml.Unmarshal(%s) error = %v", w.Body, err)
+	}
+	if len(playlist.Tracks) != 3 {
+		t.Fatalf("xspf tracks = %+v, want 3 tracks", playlist.Tracks)
+	}
+	for _, track := range playlist.Tracks {
+		if (track.Title == "Lithium") != (track.Location == "") {
+			t.Errorf("xspf track = %+v, want location only for songs with link", track)
+		}
+	}
+}
+
+func TestM3ULine(t *testing.T) {
+	if got := m3uLine("one\r\ntwo\nthree"); got != "one two three" {
+		t.Errorf("m3uLine() = %q, want %q", got, "one two three")
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю для возвращения альбомов
+type responceAllAlbums struct {
+	Albums []*models.Album `json:"albums"`
+}
+
+// GetAlbums godoc
+//	@Summary		GetAlbums
+//	@Tags			album
+//	@Description	Retrieve albums (without track lists)
+//	@Produce		json
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list extracted albums"
+//	@Param			limit	query		integer	true	"Limit of quantity extracted albums"
+//	@Success		200		{object}	responceAllAlbums
+//	@Failure		400		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/albums [get]
+
+// Хэндлер для получения альбомов
+func (a *API) GetAlbums(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetAlbums api/albums'")
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetAlbums")
+
+	// Выполняем запрос в БД
+	albums, err := a.storage.Album().GetAlbums(offsetVal, limitVal)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table albums): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю альбомы
+	c.JSON(http.StatusOK, responceAllAlbums{Albums: albums})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetAlbums api/albums' successfully done")
+}
+
+// GetAlbum godoc
+//	@Summary		GetAlbum
+//	@Tags			album
+//	@Description	Retrieve album with track list by id
+//	@Produce		json
+//	@Param			id	path		integer	true	"Album id"
+//	@Success		200	{object}	models.Album
+//	@Failure		400	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/albums/{id} [get]
+
+// Хэндлер для получения альбома вместе со списком треков
+func (a *API) GetAlbum(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetAlbum api/albums/:id'")
+
+	// Считываем идентификатор альбома
+	id, ok := a.bindPathID(c, "id", "album")
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetAlbum")
+
+	// Получаем альбом из БД
+	album, err := a.storage.Album().GetAlbum(id)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to get non existed album. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Album not found"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table albums): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю альбом
+	c.JSON(http.StatusOK, album)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetAlbum api/albums/:id' successfully done")
+}
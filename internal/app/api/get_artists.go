@@ -0,0 +1,169 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"mus_lib/storage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю для возвращения исполнителей
+type responceAllArtists struct {
+	Artists []*models.Artist `json:"artists"`
+}
+
+// GetArtists godoc
+//	@Summary		GetArtists
+//	@Tags			artist
+//	@Description	Retrieve artists
+//	@Produce		json
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list extracted artists"
+//	@Param			limit	query		integer	true	"Limit of quantity extracted artists"
+//	@Success		200		{object}	responceAllArtists
+//	@Failure		400		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/artists [get]
+
+// Хэндлер для получения исполнителей
+func (a *API) GetArtists(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetArtists api/artists'")
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetArtists")
+
+	// Выполняем запрос в БД
+	artists, err := a.storage.Artist().GetArtists(offsetVal, limitVal)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table artists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю исполнителей
+	c.JSON(http.StatusOK, responceAllArtists{Artists: artists})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetArtists api/artists' successfully done")
+}
+
+// GetArtist godoc
+//	@Summary		GetArtist
+//	@Tags			artist
+//	@Description	Retrieve artist by id
+//	@Produce		json
+//	@Param			id	path		integer	true	"Artist id"
+//	@Success		200	{object}	models.Artist
+//	@Failure		400	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/artists/{id} [get]
+
+// Хэндлер для получения исполнителя по идентификатору
+func (a *API) GetArtist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetArtist api/artists/:id'")
+
+	// Считываем идентификатор исполнителя
+	id, ok := a.bindPathID(c, "id", "artist")
+	if !ok {
+		return
+	}
+
+	// Получаем исполнителя из БД
+	artist, ok := a.getArtist(c, id)
+	if !ok {
+		return
+	}
+
+	// Возвращаем пользователю исполнителя
+	c.JSON(http.StatusOK, artist)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetArtist api/artists/:id' successfully done")
+}
+
+// GetArtistSongs godoc
+//	@Summary		GetArtistSongs
+//	@Tags			artist
+//	@Description	Retrieve songs of artist
+//	@Produce		json
+//	@Param			id		path		integer	true	"Artist id"
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list extracted songs"
+//	@Param			limit	query		integer	true	"Limit of quantity extracted songs"
+//	@Success		200		{object}	responceAllSongs
+//	@Failure		400		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/artists/{id}/songs [get]
+
+// Хэндлер для получения песен исполнителя
+func (a *API) GetArtistSongs(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetArtistSongs api/artists/:id/songs'")
+
+	// Считываем идентификатор исполнителя
+	id, ok := a.bindPathID(c, "id", "artist")
+	if !ok {
+		return
+	}
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Проверяем, что исполнитель существует (чтобы отличать его отсутствие от отсутствия песен)
+	_, ok = a.getArtist(c, id)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetSongs")
+
+	// Выполняем запрос в БД
+	songs, err := a.storage.Song().GetSongs(storage.SongFilter{ArtistID: id, Offset: offsetVal, Limit: limitVal})
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table artists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю песни исполнителя
+	c.JSON(http.StatusOK, responceAllSongs{Songs: songs})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetArtistSongs api/artists/:id/songs' successfully done")
+}
+
+// Метод, получающий исполнителя из БД по идентификатору (в случае ошибки сам отвечает пользователю)
+func (a *API) getArtist(c *gin.Context, id int64) (*models.Artist, bool) {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetArtist")
+
+	artist, err := a.storage.Artist().GetArtist(id)
+	// Если исполнитель не найден
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to get non existed artist. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Artist not found"})
+		return nil, false
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table artists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return nil, false
+	}
+
+	return artist, true
+}
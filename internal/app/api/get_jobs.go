@@ -0,0 +1,113 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю для возвращения заданий
+type responceAllJobs struct {
+	Jobs []*models.Job `json:"jobs"`
+}
+
+// GetJobs godoc
+//	@Summary		GetJobs
+//	@Tags			job
+//	@Description	Retrieve enrichment jobs (newest first)
+//	@Produce		json
+//	@Param			status	query		string	false	"Job status: pending, running, done or dead"
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list extracted jobs"
+//	@Param			limit	query		integer	true	"Limit of quantity extracted jobs"
+//	@Success		200		{object}	responceAllJobs
+//	@Failure		400		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/jobs [get]
+
+// Хэндлер для получения заданий на заполнение информации о песнях
+func (a *API) GetJobs(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetJobs api/jobs'")
+
+	// Считываем статус заданий
+	status := models.JobStatus(c.Query("status"))
+	switch status {
+	case "", models.JobPending, models.JobRunning, models.JobDone, models.JobDead:
+	default:
+		a.logger.Error(fmt.Sprintf("User provide uncorrected status value in url: %s", status))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: status value must be pending, running, done or dead"})
+		return
+	}
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetJobs")
+
+	// Получаем задания из БД
+	jobs, err := a.storage.Job().GetJobs(status, offsetVal, limitVal)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table enrichment_jobs): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю задания
+	c.JSON(http.StatusOK, responceAllJobs{Jobs: jobs})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetJobs api/jobs' successfully done")
+}
+
+// GetJob godoc
+//	@Summary		GetJob
+//	@Tags			job
+//	@Description	Retrieve enrichment job by id
+//	@Produce		json
+//	@Param			id	path		integer	true	"Job id"
+//	@Success		200	{object}	models.Job
+//	@Failure		400	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/jobs/{id} [get]
+
+// Хэндлер для получения задания на заполнение информации о песне
+func (a *API) GetJob(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetJob api/jobs/:id'")
+
+	// Считываем идентификатор задания
+	id, ok := a.bindPathID(c, "id", "job")
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetJob")
+
+	// Получаем задание из БД
+	job, err := a.storage.Job().GetJob(id)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to get non existed job. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Job not found"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table enrichment_jobs): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю задание
+	c.JSON(http.StatusOK, job)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetJob api/jobs/:id' successfully done")
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю для возвращения плейлистов
+type responceAllPlaylists struct {
+	Playlists []*models.Playlist `json:"playlists"`
+}
+
+// GetPlaylists godoc
+//	@Summary		GetPlaylists
+//	@Tags			playlist
+//	@Description	Retrieve public playlists and playlists of user (without entries)
+//	@Produce		json
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list extracted playlists"
+//	@Param			limit	query		integer	true	"Limit of quantity extracted playlists"
+//	@Success		200		{object}	responceAllPlaylists
+//	@Failure		400		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/playlists [get]
+
+// Хэндлер для получения плейлистов
+func (a *API) GetPlaylists(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetPlaylists api/playlists'")
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetPlaylists")
+
+	// Выполняем запрос в БД (чужие непубличные плейлисты не возвращаются)
+	playlists, err := a.storage.Playlist().GetPlaylists(a.requester(c), offsetVal, limitVal)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table playlists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю плейлисты
+	c.JSON(http.StatusOK, responceAllPlaylists{Playlists: playlists})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetPlaylists api/playlists' successfully done")
+}
+
+// GetPlaylist godoc
+//	@Summary		GetPlaylist
+//	@Tags			playlist
+//	@Description	Retrieve playlist with songs in order and their verse counts (songs in trash are marked as deleted)
+//	@Produce		json
+//	@Param			id	path		integer	true	"Playlist id"
+//	@Success		200	{object}	models.Playlist
+//	@Failure		400	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/playlists/{id} [get]
+
+// Хэндлер для получения плейлиста вместе с его песнями
+func (a *API) GetPlaylist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetPlaylist api/playlists/:id'")
+
+	// Получаем плейлист, если клиенту можно его видеть
+	playlist, ok := a.findPlaylist(c, false)
+	if !ok {
+		return
+	}
+
+	// Возвращаем пользователю плейлист
+	c.JSON(http.StatusOK, playlist)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetPlaylist api/playlists/:id' successfully done")
+}
+
+// Метод, считывающий идентификатор плейлиста из пути и получающий плейлист из БД (в случае ошибки сам отвечает пользователю)
+// Чужой непубличный плейлист выглядит как несуществующий, а изменять (modify) можно только свой плейлист
+func (a *API) findPlaylist(c *gin.Context, modify bool) (*models.Playlist, bool) {
+	// Считываем идентификатор плейлиста
+	id, ok := a.bindPathID(c, "id", "playlist")
+	if !ok {
+		return nil, false
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetPlaylist")
+
+	// Получаем плейлист из БД
+	playlist, err := a.storage.Playlist().GetPlaylist(id)
+	if err != nil && err != sql.ErrNoRows {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table playlists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return nil, false
+	}
+	requester := a.requester(c)
+	if err == sql.ErrNoRows || (!playlist.Public && playlist.Owner != requester) {
+		a.logger.Info(fmt.Sprintf("User trying to get non existed or private playlist. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Playlist not found"})
+		return nil, false
+	}
+	if modify && playlist.Owner != requester {
+		a.logger.Info(fmt.Sprintf("User trying to change playlist of other user. ID: %d, owner: %s", id, playlist.Owner))
+		c.JSON(http.StatusForbidden, errorMessage{"Only owner can change playlist"})
+		return nil, false
+	}
+
+	return playlist, true
+}
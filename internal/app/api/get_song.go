@@ -0,0 +1,75 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSong godoc
+//	@Summary		GetSong
+//	@Tags			song
+//	@Description	Retrieve song by id
+//	@Produce		json
+//	@Param			id				path		integer	true	"Song id"
+//	@Param			If-None-Match	header		string	false	"ETag of song known to client"
+//	@Success		200				{object}	models.Song
+//	@Success		304
+//	@Failure		400	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/songs/{id} [get]
+
+// Хэндлер для получения песни по идентификатору
+func (a *API) GetSong(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetSong api/songs/:id'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Получаем песню из БД
+	song, ok := a.getSong(c, id)
+	if !ok {
+		return
+	}
+
+	// Если у пользователя уже есть эта версия песни, повторно ее не отправляем
+	if a.notModified(c, song.Version) {
+		return
+	}
+
+	// Возвращаем пользователю песню
+	c.JSON(http.StatusOK, song)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetSong api/songs/:id' successfully done")
+}
+
+// Метод, получающий песню из БД по идентификатору (в случае ошибки сам отвечает пользователю)
+func (a *API) getSong(c *gin.Context, id int64) (*models.Song, bool) {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetSong")
+
+	song, err := a.storage.Song().GetSong(id)
+	// Если песня не найдена
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to get non existed song. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Song not found"})
+		return nil, false
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return nil, false
+	}
+
+	return song, true
+}
@@ -23,20 +23,28 @@ type queryStringSongText struct {
 	Limit  string `form:"limit"`
 }
 
+// Модель с данными о смещении и лимите (для работы с query string)
+type queryStringPagination struct {
+	Offset string `form:"offset"`
+	Limit  string `form:"limit"`
+}
+
 // GetSongText godoc
 //	@Summary		GetSongText
 //	@Tags			songs
 //	@Description	Retrieve song's text in verses on given info
 //	@Produce		json
 //	@Param			group	path		string	true	"Name of group"
-//	@Param			song	path		string	true	"Name of song"
-//	@Success		200		{object}	responceTextSong
+//	@Param			song			path		string	true	"Name of song"
+//	@Param			If-None-Match	header		string	false	"ETag of song known to client"
+//	@Success		200				{object}	responceTextSong
+//	@Success		304
 //	@Failure		400		{object}	responceMessage
 //	@Failure		404		{object}	responceMessage
 //	@Failure		500		{object}	responceMessage
 //	@Router			/song/text [get]
 
-// Хэндлер для получения текста песни
+// Хэндлер для получения текста песни (псевдоним GetSongTextByID, песня ищется по названиям исполнителя и песни)
 func (a *API) GetSongText(c *gin.Context) {
 	// Логируем начало выполнение запроса
 	a.logger.Info("User do 'GET: GetSongText api/song/text'")
@@ -56,54 +64,141 @@ func (a *API) GetSongText(c *gin.Context) {
 		return
 	}
 
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.parsePagination(c, song.Offset, song.Limit)
+	if !ok {
+		return
+	}
+
+	// Ищем идентификатор песни в БД
+	id, ok := a.findSongID(c, song.Group, song.Song, "get text of")
+	if !ok {
+		return
+	}
+
+	// Если песня найдена извлекаем ее текст из БД
+	verses, version, ok := a.getSongText(c, id, offsetVal, limitVal)
+	if !ok {
+		return
+	}
+	if a.notModified(c, version) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции (предполагается, что текст песни будет преобразован в читаемый вид на стороне фронта)
+	c.JSON(http.StatusOK, responceTextSong{Verses: verses})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetSongText api/song/text' successfully done")
+}
+
+// GetSongTextByID godoc
+//	@Summary		GetSongTextByID
+//	@Tags			songs
+//	@Description	Retrieve song's text in verses by song id
+//	@Produce		json
+//	@Param			id		path		integer	true	"Song id"
+//	@Param			offset	query		integer	true	"Offset on verses from the beginning of the song"
+//	@Param			limit			query		integer	true	"Limit of verses"
+//	@Param			If-None-Match	header		string	false	"ETag of song known to client"
+//	@Success		200				{object}	responceTextSong
+//	@Success		304
+//	@Failure		400		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/songs/{id}/text [get]
+
+// Хэндлер для получения текста песни по идентификатору
+func (a *API) GetSongTextByID(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetSongTextByID api/songs/:id/text'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Извлекаем текст песни из БД
+	verses, version, ok := a.getSongText(c, id, offsetVal, limitVal)
+	if !ok {
+		return
+	}
+	if a.notModified(c, version) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceTextSong{Verses: verses})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetSongTextByID api/songs/:id/text' successfully done")
+}
+
+// Метод, считывающий обязательные значения смещения и лимита из query string (в случае ошибки сам отвечает пользователю)
+func (a *API) bindPagination(c *gin.Context) (int, int, bool) {
+	// Парсим query string
+	var pagination queryStringPagination
+	err := c.ShouldBindQuery(&pagination)
+	// Проверка query string (удовлетворяет ли она условиям данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind query string: %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return 0, 0, false
+	}
+	if pagination.Offset == "" || pagination.Limit == "" {
+		a.logger.Error("User provide uncorrected query string in url: offset or limit is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: offset and limit value must be not empty"})
+		return 0, 0, false
+	}
+
+	return a.parsePagination(c, pagination.Offset, pagination.Limit)
+}
+
+// Метод, считывающий значения смещения и лимита (в случае ошибки сам отвечает пользователю)
+func (a *API) parsePagination(c *gin.Context, offset, limit string) (int, int, bool) {
 	// Считываем значения смещения
-	offsetVal, err := strconv.Atoi(song.Offset)
+	offsetVal, err := strconv.Atoi(offset)
 	if err != nil {
 		a.logger.Error(fmt.Sprintf("User provide uncorrected offset value in url: %s", err))
 		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: offset value must be a number"})
-		return
+		return 0, 0, false
 	}
 
 	// Считываем значения лимита
-	limitVal, err := strconv.Atoi(song.Limit)
+	limitVal, err := strconv.Atoi(limit)
 	if err != nil {
 		a.logger.Error(fmt.Sprintf("User provide uncorrected limit value in url: %s", err))
 		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: limit value must be a number"})
-		return
+		return 0, 0, false
 	}
 
+	return offsetVal, limitVal, true
+}
+
+// Метод, извлекающий куплеты песни и ее версию из БД (в случае ошибки сам отвечает пользователю)
+func (a *API) getSongText(c *gin.Context, id int64, offset, limit int) ([]string, int64, bool) {
 	// Логируем обращение к БД
-	a.logger.Debug("Sending a request to DB: CheckSong")
+	a.logger.Debug("Sending a request to DB: GetSongText")
 
-	// Ищем песню в БД
-	err = a.storage.Song().CheckSong(song.Group, song.Song)
+	verses, version, err := a.storage.Song().GetSongText(id, offset, limit)
 	// Если песня не найдена
-	if err != nil && err == sql.ErrNoRows {
-		a.logger.Info(fmt.Sprintf("User trying to get text of non existed song. Group: %s, song: %s", song.Group, song.Song))
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to get text of non existed song. ID: %d", id))
 		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to get text of non existed song"})
-		return
+		return nil, 0, false
 	}
 	if err != nil {
 		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
 		c.JSON(http.StatusInternalServerError, serverError)
-		return
+		return nil, 0, false
 	}
 
-	// Логируем обращение к БД
-	a.logger.Debug("Sending a request to DB: GetSongText")
-
-	// Если песня найдена извлекаем ее текст из БД
-	verses, err := a.storage.Song().GetSongText(song.Group, song.Song, offsetVal, limitVal)
-	if err != nil {
-		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
-		c.JSON(http.StatusInternalServerError, serverError)
-		return
-	}
-
-	// Возвращаем пользователю сообщение об успешно выполненной операции (предполагается, что текст песни будет преобразован в читаемый вид на стороне фронта)
-	c.JSON(http.StatusOK, responceTextSong{Verses: verses})
-
-	fmt.Println(verses)
-	// Логируем окончание запроса
-	a.logger.Info("Request 'GET: GetSongText api/song/text' successfully done")
+	return verses, version, true
 }
@@ -3,10 +3,12 @@ package api
 import (
 	"fmt"
 	"mus_lib/internal/app/models"
+	"mus_lib/storage"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,13 +20,37 @@ type responceAllSongs struct {
 
 // Модель со всей информацией о песне, включая смещение и лимит количества возвращаемых результатов из БД (для работы с query string)
 type queryStringAllSongs struct {
-	Group       string `form:"group"`
-	Song        string `form:"song"`
-	ReleaseDate string `form:"releaseDate"`
-	Text        string `form:"text"`
-	Link        string `form:"link"`
-	Offset      string `form:"offset"`
-	Limit       string `form:"limit"`
+	Group        string `form:"group"`
+	GroupMatch   string `form:"groupMatch"`
+	Song         string `form:"song"`
+	SongMatch    string `form:"songMatch"`
+	ReleaseDate  string `form:"releaseDate"`
+	ReleasedFrom string `form:"releasedFrom"`
+	ReleasedTo   string `form:"releasedTo"`
+	Year         string `form:"year"`
+	Decade       string `form:"decade"`
+	Text         string `form:"text"`
+	Link         string `form:"link"`
+	AlbumID      string `form:"albumId"`
+	Sort         string `form:"sort"`
+	Order        string `form:"order"`
+	Offset       string `form:"offset"`
+	Limit        string `form:"limit"`
+}
+
+// Диапазон дат релиза [from, to), пустые границы означают отсутствие ограничения
+type releaseRange struct {
+	from, to time.Time
+}
+
+// Метод, сужающий диапазон до пересечения с периодом [from, to)
+func (r *releaseRange) intersect(from, to time.Time) {
+	if !from.IsZero() && (r.from.IsZero() || from.After(r.from)) {
+		r.from = from
+	}
+	if !to.IsZero() && (r.to.IsZero() || to.Before(r.to)) {
+		r.to = to
+	}
 }
 
 // GetSongs godoc
@@ -35,10 +61,19 @@ type queryStringAllSongs struct {
 //	@Param			offset		path		integer	true	"Offset from the beginning of the list extracted songs"
 //	@Param			limit		path		integer	true	"Limit of quantity extracted songs"
 //	@Param			group		path		string	"Name of group"
+//	@Param			groupMatch	path		string	"Match mode for group: exact, prefix or contains"
 //	@Param			song		path		string	"Name of song"
-//	@Param			releaseDate	path		string	"Release date of song"
+//	@Param			songMatch	path		string	"Match mode for song: exact, prefix or contains"
+//	@Param			releaseDate	path		string	"Release date of song (dd.mm.yyyy, mm.yyyy or yyyy; matches the whole period)"
+//	@Param			releasedFrom	path		string	"Songs released on or after this date (dd.mm.yyyy, mm.yyyy or yyyy)"
+//	@Param			releasedTo	path		string	"Songs released on or before this date (dd.mm.yyyy, mm.yyyy or yyyy)"
+//	@Param			year		path		integer	"Year of release"
+//	@Param			decade		path		string	"Decade of release: 1990 or 1990s"
+//	@Param			sort		path		string	"Sort field: releaseDate"
+//	@Param			order		path		string	"Sort order: asc or desc"
 //	@Param			text		path		string	"Words that will be used to search for songs"
 //	@Param			link		path		string	"Link of song on youtube"
+//	@Param			albumId		path		integer	"Album id (songs are returned in track order)"
 //	@Success		200			{array}		models.Song
 //	@Failure		400			{object}	responceMessage
 //	@Failure		500			{object}	responceMessage
@@ -49,51 +84,17 @@ func (a *API) GetSongs(c *gin.Context) {
 	// Логируем начало выполнение запроса
 	a.logger.Info("User do 'Get: GetSongs api/songs'")
 
-	// Парсим query string
-	var aSongs queryStringAllSongs
-	err := c.ShouldBindQuery(&aSongs)
-	// Проверка query string (удовлетворяет ли она условиям данного хэндлера)
-	if err != nil {
-		a.logger.Error(fmt.Sprintf("Trouble with bind query string: %s", err))
-		c.JSON(http.StatusInternalServerError, serverError)
-		return
-	}
-	if aSongs.Offset == "" || aSongs.Limit == "" {
-		a.logger.Error("User provide uncorrected query string in url: offset or limit is empty")
-		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: offset and limit value must be not empty"})
+	// Считываем фильтр из query string
+	filter, ok := a.bindSongFilter(c, true)
+	if !ok {
 		return
 	}
 
-	// Считываем значения смещения
-	offsetVal, err := strconv.Atoi(aSongs.Offset)
-	if err != nil {
-		a.logger.Error(fmt.Sprintf("User provide uncorrected offset value in url: %s", err))
-		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: offset value must be a number"})
-		return
-	}
-
-	// Считываем значения лимита
-	limitVal, err := strconv.Atoi(aSongs.Limit)
-	if err != nil {
-		a.logger.Error(fmt.Sprintf("User provide uncorrected limit value in url: %s", err))
-		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: limit value must be a number"})
-		return
-	}
-
-	// Формируем запрос в БД
-	query, err := createQueryDB(aSongs, offsetVal, limitVal)
-	if err != nil {
-		a.logger.Error(fmt.Sprintf("Failed to generate a query for the DB: %s", err))
-		c.JSON(http.StatusInternalServerError, serverError)
-		return
-	}
-
-	// Логируем получившийся запрос (может пригодиться) и обращение к БД
-	a.logger.Debug(query)
+	// Логируем обращение к БД
 	a.logger.Debug("Sending a request to DB: GetSongs")
 
 	// Выполняем запрос в БД
-	songs, err := a.storage.Song().GetSongs(query)
+	songs, err := a.storage.Song().GetSongs(filter)
 	if err != nil {
 		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
 		c.JSON(http.StatusInternalServerError, serverError)
@@ -112,58 +113,161 @@ func (a *API) GetSongs(c *gin.Context) {
 	a.logger.Info("Request 'Get: GetSongs api/songs' successfully done")
 }
 
-// Функция для формирования запроса в БД на получение данных библиотеки в зависимости от входящих параметров фильтрации и пагинации
-func createQueryDB(song queryStringAllSongs, offset, limit int) (string, error) {
-	// Подготавливаем необходимые переменные
-	var (
-		queryDB          strings.Builder
-		err              error
-		whereExpressions []string
-	)
-
-	// Формируем основу запроса для БД
-	_, err = queryDB.WriteString(fmt.Sprintf(`SELECT "group", song, releaseDate, string_to_array(array_to_string(text, E'\n\n'), E'\n\n') as text, link FROM %s `, os.Getenv("TABLE_NAME")))
+// Метод, считывающий из query string фильтр песен вместе со смещением и лимитом, если paginated (в случае ошибки сам отвечает пользователю)
+func (a *API) bindSongFilter(c *gin.Context, paginated bool) (storage.SongFilter, bool) {
+	var filter storage.SongFilter
+
+	// Парсим query string
+	var aSongs queryStringAllSongs
+	err := c.ShouldBindQuery(&aSongs)
+	// Проверка query string (удовлетворяет ли она условиям данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind query string: %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return filter, false
+	}
+
+	// Считываем значения смещения и лимита (выгрузка песен возвращает все песни без пагинации)
+	var offsetVal, limitVal int
+	if paginated {
+		if aSongs.Offset == "" || aSongs.Limit == "" {
+			a.logger.Error("User provide uncorrected query string in url: offset or limit is empty")
+			c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: offset and limit value must be not empty"})
+			return filter, false
+		}
+
+		var ok bool
+		offsetVal, limitVal, ok = a.parsePagination(c, aSongs.Offset, aSongs.Limit)
+		if !ok {
+			return filter, false
+		}
+	}
+
+	// Считываем способы сравнения названий исполнителя и песни
+	groupMatch, err := storage.ParseMatchMode(aSongs.GroupMatch)
 	if err != nil {
-		return "", err
+		a.logger.Error(fmt.Sprintf("User provide uncorrected groupMatch value in url: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: groupMatch value must be exact, prefix or contains"})
+		return filter, false
+	}
+	songMatch, err := storage.ParseMatchMode(aSongs.SongMatch)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected songMatch value in url: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: songMatch value must be exact, prefix or contains"})
+		return filter, false
 	}
 
-	// Считываем параметры, присутствующие в query string
-	if song.Group != "" {
-		whereExpressions = append(whereExpressions, `"group"=`+song.Group)
+	// Считываем идентификатор альбома (если задан, песни возвращаются в порядке треков)
+	var albumID int64
+	if aSongs.AlbumID != "" {
+		albumID, err = strconv.ParseInt(aSongs.AlbumID, 10, 64)
+		if err != nil || albumID <= 0 {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected albumId value in url: %s", aSongs.AlbumID))
+			c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: albumId value must be a positive number"})
+			return filter, false
+		}
 	}
-	if song.Song != "" {
-		whereExpressions = append(whereExpressions, "song="+song.Song)
+
+	// Считываем ограничения на дату релиза
+	released, ok := a.parseReleaseRange(c, aSongs)
+	if !ok {
+		return filter, false
 	}
-	if song.ReleaseDate != "" {
-		whereExpressions = append(whereExpressions, "releaseDate="+song.ReleaseDate)
+
+	// Считываем поле и направление сортировки
+	sort, err := storage.ParseSongSort(aSongs.Sort)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected sort value in url: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: sort value must be releaseDate"})
+		return filter, false
 	}
-	if song.Text != "" {
-		whereExpressions = append(whereExpressions, fmt.Sprintf("array_to_string(text, '') LIKE '%%%s%%' ", song.Text))
+	if aSongs.Order != "" && aSongs.Order != "asc" && aSongs.Order != "desc" {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected order value in url: %s", aSongs.Order))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: order value must be asc or desc"})
+		return filter, false
 	}
-	if song.Link != "" {
-		whereExpressions = append(whereExpressions, "releaseDate="+song.Link)
+
+	// Формируем фильтр для запроса в БД
+	filter = storage.SongFilter{
+		AlbumID:      albumID,
+		Group:        aSongs.Group,
+		GroupMatch:   groupMatch,
+		Song:         aSongs.Song,
+		SongMatch:    songMatch,
+		ReleasedFrom: released.from,
+		ReleasedTo:   released.to,
+		Text:         aSongs.Text,
+		Link:         aSongs.Link,
+		Sort:         sort,
+		Desc:         aSongs.Order == "desc",
+		Offset:       offsetVal,
+		Limit:        limitVal,
 	}
 
-	// Проходимся по этим параметрам, чтобы правильно сформировать запрос
-	for i := range whereExpressions {
-		whereExpression := whereExpressions[i]
-		if i == 0 {
-			whereExpression = "WHERE " + whereExpression
-		} else {
-			whereExpression = "AND  " + whereExpression
+	return filter, true
+}
+
+// Функция, считывающая из query string ограничения на дату релиза и объединяющая их в один диапазон (в случае ошибки пишет ответ пользователю)
+func (a *API) parseReleaseRange(c *gin.Context, aSongs queryStringAllSongs) (releaseRange, bool) {
+	var released releaseRange
+
+	// Конкретная дата релиза совпадает со всем своим периодом (например, "1991" — с любой датой 1991 года)
+	if aSongs.ReleaseDate != "" {
+		from, to, ok := a.parseReleasePeriod(c, "releaseDate", aSongs.ReleaseDate)
+		if !ok {
+			return released, false
 		}
-		_, err = queryDB.WriteString(whereExpression)
-		if err != nil {
-			return "", err
+		released.intersect(from, to)
+	}
+	// Нижняя граница включает весь свой период, поэтому берем его начало
+	if aSongs.ReleasedFrom != "" {
+		from, _, ok := a.parseReleasePeriod(c, "releasedFrom", aSongs.ReleasedFrom)
+		if !ok {
+			return released, false
 		}
+		released.intersect(from, time.Time{})
 	}
+	// Верхняя граница тоже включает весь свой период, поэтому берем его конец
+	if aSongs.ReleasedTo != "" {
+		_, to, ok := a.parseReleasePeriod(c, "releasedTo", aSongs.ReleasedTo)
+		if !ok {
+			return released, false
+		}
+		released.intersect(time.Time{}, to)
+	}
+	if aSongs.Year != "" {
+		year, err := strconv.Atoi(aSongs.Year)
+		if err != nil || year < 1 || year > 9999 {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected year value in url: %s", aSongs.Year))
+			c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: year value must be a number between 1 and 9999"})
+			return released, false
+		}
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		released.intersect(from, from.AddDate(1, 0, 0))
+	}
+	if aSongs.Decade != "" {
+		decade, err := strconv.Atoi(strings.TrimSuffix(aSongs.Decade, "s"))
+		if err != nil || decade < 0 || decade > 9990 || decade%10 != 0 {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected decade value in url: %s", aSongs.Decade))
+			c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: decade value must be a year divisible by 10, for example 1990 or 1990s"})
+			return released, false
+		}
+		from := time.Date(decade, time.January, 1, 0, 0, 0, 0, time.UTC)
+		released.intersect(from, from.AddDate(10, 0, 0))
+	}
+
+	return released, true
+}
 
-	// Заканчиваем формирование запроса
-	_, err = queryDB.WriteString(fmt.Sprintf("OFFSET %v LIMIT %v", offset, limit))
+// Функция, разбирающая дату из query string и возвращающая покрываемый ею период (в случае ошибки пишет ответ пользователю)
+func (a *API) parseReleasePeriod(c *gin.Context, param, value string) (time.Time, time.Time, bool) {
+	date, precision, err := models.ParseReleaseDate(value)
 	if err != nil {
-		return "", err
+		a.logger.Error(fmt.Sprintf("User provide uncorrected %s value in url: %s", param, err))
+		c.JSON(http.StatusBadRequest, errorMessage{fmt.Sprintf("URL have uncorrected parameters in the query string: %s value must be a date in dd.mm.yyyy, mm.yyyy or yyyy format", param)})
+		return time.Time{}, time.Time{}, false
 	}
 
-	// Возвращаем запрос
-	return queryDB.String(), nil
+	from, to := models.ReleasePeriod(date, precision)
+	return from, to, true
 }
@@ -0,0 +1,395 @@
+package api
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	importBatchSize   = 500     // Сколько песен добавляется в БД одним запросом
+	maxImportLineSize = 1 << 20 // Максимальная длина строки NDJSON (1 МиБ)
+)
+
+// Формат тела запроса импорта
+type importFormat string
+
+const (
+	importNDJSON importFormat = "ndjson"
+	importCSV    importFormat = "csv"
+)
+
+// Статус импорта одной строки
+type importStatus string
+
+const (
+	importCreated importStatus = "created" // Песня добавлена
+	importSkipped importStatus = "skipped" // Такая песня уже есть в библиотеке (или раньше в этом же импорте)
+	importFailed  importStatus = "failed"  // Строку не удалось прочитать или добавить
+)
+
+// Колонки CSV для импорта и выгрузки песен (при импорте обязательны только group и song)
+var songCSVColumns = []string{"group", "song", "releaseDate", "text", "link"}
+
+// Разделитель куплетов в колонке text CSV
+const csvVerseSeparator = "\n\n"
+
+// Модель одной песни в теле запроса импорта
+type requestImportSong struct {
+	Group       string   `json:"group"`
+	Song        string   `json:"song"`
+	ReleaseDate string   `json:"releaseDate"`
+	Text        []string `json:"text"`
+	Link        string   `json:"link"`
+}
+
+// Модель отчета об импорте одной строки
+type importRowReport struct {
+	Line   int               `json:"line"` // Номер строки в теле запроса (для CSV - строка, с которой начинается запись)
+	Status importStatus      `json:"status"`
+	ID     int64             `json:"id,omitempty"`    // Идентификатор добавленной песни
+	JobID  int64             `json:"jobId,omitempty"` // Идентификатор задания на заполнение недостающей информации
+	Error  string            `json:"error,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"` // Описание ошибки по каждому некорректному полю
+}
+
+// Модель ответа пользователю с отчетом об импорте
+type responceImportSongs struct {
+	Created int                `json:"created"`
+	Skipped int                `json:"skipped"`
+	Failed  int                `json:"failed"`
+	Rows    []*importRowReport `json:"rows"`
+}
+
+// Ошибка в одной строке импорта (остальные строки продолжают читаться)
+type importRowError struct {
+	message string
+	fields  map[string]string
+}
+
+func (e *importRowError) Error() string {
+	return e.message
+}
+
+// Интерфейс для построчного чтения тела запроса импорта
+type importReader interface {
+	// Возвращает номер строки и песню; *importRowError - ошибка в строке, io.EOF - конец тела, другая ошибка - тело нельзя читать дальше
+	next() (int, *requestImportSong, error)
+	// Возвращает номер последней прочитанной строки
+	line() int
+}
+
+// ImportSongs godoc
+//	@Summary		ImportSongs
+//	@Tags			song
+//	@Description	Import songs from NDJSON or CSV (header: group,song,releaseDate,text,link; verses in text are separated by empty line). Existing songs are skipped, near-duplicates are not checked
+//	@Accept			x-ndjson,csv
+//	@Produce		json
+//	@Param			format	query		string	false	"Body format, overrides Content-Type"	Enums(ndjson, csv)
+//	@Param			enrich	query		boolean	false	"Create enrichment jobs for created songs with missing releaseDate, text or link"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200		{object}	responceImportSongs
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		415		{object}	responceMessage
+//	@Router			/songs/import [post]
+
+// Хэндлер для массового добавления песен из NDJSON или CSV
+func (a *API) ImportSongs(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: ImportSongs api/songs/import'")
+
+	// Определяем формат тела запроса (параметр format важнее Content-Type)
+	format, ok := a.bindImportFormat(c)
+	if !ok {
+		return
+	}
+
+	// Считываем, нужно ли создавать задания на заполнение информации
+	enrich, ok := a.bindEnrich(c)
+	if !ok {
+		return
+	}
+
+	// Тело читается потоком, поэтому в памяти держится только текущая пачка песен
+	var reader importReader
+	if format == importCSV {
+		reader, ok = a.newCSVImportReader(c)
+		if !ok {
+			return
+		}
+	} else {
+		reader = newNDJSONImportReader(c.Request.Body)
+	}
+
+	result := responceImportSongs{Rows: make([]*importRowReport, 0)}
+	var (
+		batch []*importRowReport
+		songs []*models.Song
+	)
+	for {
+		line, reqSong, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+
+		// Ошибка в строке не мешает импорту остальных строк
+		var rowErr *importRowError
+		if errors.As(err, &rowErr) {
+			result.Rows = append(result.Rows, &importRowReport{Line: line, Status: importFailed, Error: rowErr.message, Fields: rowErr.fields})
+			continue
+		}
+		// Если тело нельзя читать дальше, добавляем уже прочитанные песни и останавливаемся
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("Trouble with reading request body: %s", err))
+			result.Rows = append(result.Rows, &importRowReport{Line: reader.line() + 1, Status: importFailed, Error: fmt.Sprintf("request body could not be read further: %s", err)})
+			break
+		}
+
+		// Проверяем поля песни так же, как при ее замене
+		song, fields := validateSong(requestPutSong{Group: reqSong.Group, Song: reqSong.Song, ReleaseDate: reqSong.ReleaseDate, Text: reqSong.Text, Link: reqSong.Link})
+		if len(fields) > 0 {
+			result.Rows = append(result.Rows, &importRowReport{Line: line, Status: importFailed, Error: "song fields are not valid", Fields: fields})
+			continue
+		}
+
+		row := &importRowReport{Line: line}
+		result.Rows = append(result.Rows, row)
+		batch, songs = append(batch, row), append(songs, song)
+		if len(songs) == importBatchSize {
+			a.importBatch(c, batch, songs, enrich)
+			batch, songs = batch[:0], songs[:0]
+		}
+	}
+	a.importBatch(c, batch, songs, enrich)
+
+	// Считаем итоги импорта
+	for _, row := range result.Rows {
+		switch row.Status {
+		case importCreated:
+			result.Created++
+		case importSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+	}
+
+	// Возвращаем пользователю отчет по каждой строке
+	c.JSON(http.StatusOK, result)
+
+	// Логируем окончание запроса
+	a.logger.Info(fmt.Sprintf("Request 'POST: ImportSongs api/songs/import' successfully done. Created: %d, skipped: %d, failed: %d", result.Created, result.Skipped, result.Failed))
+}
+
+// Метод, определяющий формат тела запроса импорта (пишет ответ пользователю, если формат не поддерживается)
+func (a *API) bindImportFormat(c *gin.Context) (importFormat, bool) {
+	switch c.Query("format") {
+	case string(importNDJSON):
+		return importNDJSON, true
+	case string(importCSV):
+		return importCSV, true
+	case "":
+	default:
+		a.logger.Error(fmt.Sprintf("User provide uncorrected import format: %s", c.Query("format")))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: format must be ndjson or csv"})
+		return "", false
+	}
+
+	switch c.ContentType() {
+	case "application/x-ndjson", "application/jsonl":
+		return importNDJSON, true
+	case "text/csv":
+		return importCSV, true
+	}
+
+	a.logger.Error(fmt.Sprintf("User provide unsupported content type: %s", c.ContentType()))
+	c.JSON(http.StatusUnsupportedMediaType, errorMessage{"Request body must be application/x-ndjson or text/csv (or set format query parameter)"})
+	return "", false
+}
+
+// Метод, считывающий параметр enrich (по умолчанию задания не создаются; в случае ошибки сам отвечает пользователю)
+func (a *API) bindEnrich(c *gin.Context) (bool, bool) {
+	value := c.Query("enrich")
+	if value == "" {
+		return false, true
+	}
+
+	enrich, err := strconv.ParseBool(value)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected enrich value in url: %s", value))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: enrich value must be true or false"})
+		return false, false
+	}
+
+	return enrich, true
+}
+
+// Метод, добавляющий пачку песен в БД и записывающий результат в отчеты по строкам
+func (a *API) importBatch(c *gin.Context, batch []*importRowReport, songs []*models.Song, enrich bool) {
+	if len(songs) == 0 {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug(fmt.Sprintf("Sending a request to DB: ImportSongs (%d songs)", len(songs)))
+
+	// Добавляем пачку песен (уже существующие песни пропускаются)
+	imported, err := a.songStore(c).ImportSongs(songs, enrich)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		for _, row := range batch {
+			row.Status, row.Error = importFailed, serverError.Message
+		}
+		return
+	}
+
+	for i, row := range batch {
+		if !imported[i].Created {
+			row.Status, row.Error = importSkipped, "song already exists"
+			continue
+		}
+		row.Status, row.ID, row.JobID = importCreated, songs[i].ID, imported[i].JobID
+	}
+}
+
+// Построчное чтение NDJSON (одна песня - один JSON объект в строке, пустые строки пропускаются)
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+	current int
+}
+
+// Функция, создающая чтение NDJSON из тела запроса
+func newNDJSONImportReader(body io.Reader) *ndjsonImportReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	return &ndjsonImportReader{scanner: scanner}
+}
+
+func (r *ndjsonImportReader) next() (int, *requestImportSong, error) {
+	for r.scanner.Scan() {
+		r.current++
+		data := strings.TrimSpace(r.scanner.Text())
+		if data == "" {
+			continue
+		}
+
+		var reqSong requestImportSong
+		decoder := json.NewDecoder(strings.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&reqSong)
+		if err != nil {
+			// Ошибку типа или неизвестное поле показываем как ошибку конкретного поля
+			if fields := jsonFieldErrors(err); fields != nil {
+				return r.current, nil, &importRowError{"song fields are not valid", fields}
+			}
+			return r.current, nil, &importRowError{message: "line is not a JSON object"}
+		}
+		if decoder.More() {
+			return r.current, nil, &importRowError{message: "line must contain exactly one JSON object"}
+		}
+
+		return r.current, &reqSong, nil
+	}
+
+	if errors.Is(r.scanner.Err(), bufio.ErrTooLong) {
+		return r.current, nil, fmt.Errorf("line is longer than %d bytes", maxImportLineSize)
+	}
+	if r.scanner.Err() != nil {
+		return r.current, nil, r.scanner.Err()
+	}
+	return r.current, nil, io.EOF
+}
+
+func (r *ndjsonImportReader) line() int {
+	return r.current
+}
+
+// Построчное чтение CSV с заголовком (куплеты в колонке text разделяются пустой строкой)
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	current int
+}
+
+// Метод, создающий чтение CSV и проверяющий заголовок (пишет ответ пользователю, если заголовок некорректный)
+func (a *API) newCSVImportReader(c *gin.Context) (*csvImportReader, bool) {
+	reader := csv.NewReader(c.Request.Body)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with reading CSV header: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected CSV: first line must be a header"})
+		return nil, false
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if _, ok := columns[column]; ok || !slices.Contains(songCSVColumns, column) {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected CSV header: column %q is unknown or repeated", column))
+			c.JSON(http.StatusBadRequest, errorMessage{fmt.Sprintf("You provide uncorrected CSV header: column %q is unknown or repeated (allowed: %s)", column, strings.Join(songCSVColumns, ", "))})
+			return nil, false
+		}
+		columns[column] = i
+	}
+	for _, column := range songCSVColumns[:2] {
+		if _, ok := columns[column]; !ok {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected CSV header: column %q is missing", column))
+			c.JSON(http.StatusBadRequest, errorMessage{fmt.Sprintf("You provide uncorrected CSV header: column %q is required", column)})
+			return nil, false
+		}
+	}
+
+	line, _ := reader.FieldPos(0)
+	return &csvImportReader{reader: reader, columns: columns, current: line}, true
+}
+
+func (r *csvImportReader) next() (int, *requestImportSong, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return r.current, nil, io.EOF
+	}
+	// Ошибка разбора относится к одной записи, следующие записи читаются дальше
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		r.current = parseErr.Line
+		return parseErr.StartLine, nil, &importRowError{message: fmt.Sprintf("record is not valid CSV: %s", parseErr.Err)}
+	}
+	if err != nil {
+		return r.current, nil, err
+	}
+	line, _ := r.reader.FieldPos(0)
+	r.current = line
+
+	field := func(column string) string {
+		if i, ok := r.columns[column]; ok {
+			return record[i]
+		}
+		return ""
+	}
+	reqSong := &requestImportSong{Group: field("group"), Song: field("song"), ReleaseDate: field("releaseDate"), Link: field("link")}
+	if text := strings.TrimSpace(field("text")); text != "" {
+		reqSong.Text = strings.Split(text, csvVerseSeparator)
+	}
+
+	return line, reqSong, nil
+}
+
+func (r *csvImportReader) line() int {
+	return r.current
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Функция, импортирующая песни и возвращающая отчет об импорте
+func importTestSongs(t *testing.T, a *API, token, query, contentType, body string) responceImportSongs {
+	t.Helper()
+
+	w := serve(a, http.MethodPost, "/api/songs/import"+query, body, "Content-Type", contentType, "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("import status = %d: %s", w.Code, w.Body)
+	}
+
+	var result responceImportSongs
+	decode(t, w, &result)
+	return result
+}
+
+func TestImportSongsNDJSON(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	addTestSong(t, a, "Muse", "Uprising")
+
+	body := `{"group":"Queen","song":"Bohemian Rhapsody","releaseDate":"31.10.1975","text":["Is this the real life?"]}
+{"group":"MUSE","song":"uprising"}
+not json
+{"group":"Queen","song":"Bohemian Rhapsody"}
+{"group":"Nirvana","song":"Lithium","releaseDate":"July 1992"}
+`
+	result := importTestSongs(t, a, token, "", "application/x-ndjson", body)
+	if result.Created != 1 || result.Skipped != 2 || result.Failed != 2 {
+		t.Fatalf("result = %+v, want 1 created, 2 skipped and 2 failed", result)
+	}
+
+	wantStatuses := []importStatus{importCreated, importSkipped, importFailed, importSkipped, importFailed}
+	for i, row := range result.Rows {
+		if row.Line != i+1 || row.Status != wantStatuses[i] {
+			t.Errorf("row %d = line %d %s, want line %d %s", i, row.Line, row.Status, i+1, wantStatuses[i])
+		}
+	}
+	if fields := result.Rows[4].Fields; fields["releaseDate"] == "" {
+		t.Errorf("fields = %v, want releaseDate error", fields)
+	}
+	if song := getTestSong(t, a, result.Rows[0].ID); song.ReleaseDate != "31.10.1975" || len(song.Text) != 1 {
+		t.Errorf("song = %+v, want imported info", song)
+	}
+}
+
+func TestImportSongsCSV(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+
+	body := "group,song,text\nMuse,Uprising,\"one\n\ntwo\"\n,Starlight,\n"
+	result := importTestSongs(t, a, token, "?format=csv", "text/plain", body)
+	if result.Created != 1 || result.Failed != 1 {
+		t.Fatalf("result = %+v, want 1 created and 1 failed", result)
+	}
+	// Номер строки CSV - строка, с которой начинается запись
+	if failed := result.Rows[1]; failed.Line != 5 {
+		t.Errorf("failed row line = %d, want 5", failed.Line)
+	}
+	if song := getTestSong(t, a, result.Rows[0].ID); len(song.Text) != 2 {
+		t.Errorf("text = %v, want two verses", song.Text)
+	}
+}
+
+func TestImportSongsEnrich(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+
+	body := `{"group":"Muse","song":"Uprising"}
+{"group":"Queen","song":"Bohemian Rhapsody","releaseDate":"1975","text":["one"],"link":"https://example.com"}
+`
+	result := importTestSongs(t, a, token, "?enrich=1", "application/x-ndjson", body)
+	if result.Created != 2 {
+		t.Fatalf("result = %+v, want 2 created", result)
+	}
+	// Задание создается только для песни, у которой не хватает информации
+	if result.Rows[0].JobID == 0 || result.Rows[1].JobID != 0 {
+		t.Errorf("rows = %+v, %+v; want job only for first song", result.Rows[0], result.Rows[1])
+	}
+}
+
+func TestImportSongsBadRequest(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		wantStatus  int
+	}{
+		{"uncorrected enrich", "?enrich=maybe", "application/x-ndjson", http.StatusBadRequest},
+		{"uncorrected format", "?format=xml", "application/x-ndjson", http.StatusBadRequest},
+		{"unsupported content type", "", "application/json", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(a, http.MethodPost, "/api/songs/import"+tt.query, `{"group":"Muse","song":"Uprising"}`, "Content-Type", tt.contentType, "Authorization", bearer(token))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
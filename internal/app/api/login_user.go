@@ -0,0 +1,109 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/auth"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю с токеном, выданным при входе
+type responceToken struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"tokenType"` // Схема заголовка Authorization, в котором передается токен
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Модель ответа пользователю с информацией о нем
+type responceCurrentUser struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Login godoc
+//	@Summary		Login
+//	@Tags			user
+//	@Description	Check name and password and issue signed token (JWT) to pass in Authorization: Bearer header
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		requestBodyCredentials	true	"Name and password"
+//	@Success		200		{object}	responceToken
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/auth/login [post]
+
+// Хэндлер для входа пользователя
+func (a *API) Login(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: Login api/auth/login'")
+
+	// Парсим request body
+	credentials, ok := a.bindRequestBodyCredentials(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetUserByName")
+
+	// Получаем пользователя из БД
+	user, err := a.storage.User().GetUserByName(credentials.Name)
+	if err != nil && err != sql.ErrNoRows {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table users): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	// Пароль проверяется и для несуществующего пользователя, чтобы по ответу нельзя было узнать, есть ли такой пользователь
+	if err == sql.ErrNoRows {
+		user = &models.User{}
+	}
+	match, err := auth.CheckPassword(user.PasswordHash, credentials.Password)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with checking password: %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	if !match {
+		a.logger.Info(fmt.Sprintf("User provide wrong name or password. Name: %s", credentials.Name))
+		c.JSON(http.StatusUnauthorized, errorMessage{"Wrong name or password"})
+		return
+	}
+
+	// Выдаем токен
+	token, expiresAt := a.tokens.Issue(user.ID, user.Name)
+
+	// Возвращаем пользователю токен
+	c.JSON(http.StatusOK, responceToken{Token: token, TokenType: "Bearer", ExpiresAt: expiresAt})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: Login api/auth/login' successfully done")
+}
+
+// GetCurrentUser godoc
+//	@Summary		GetCurrentUser
+//	@Tags			user
+//	@Description	Retrieve user authenticated by token or API key
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	responceCurrentUser
+//	@Failure		401	{object}	responceMessage
+//	@Router			/auth/me [get]
+
+// Хэндлер для получения пользователя, выполняющего запрос
+func (a *API) GetCurrentUser(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetCurrentUser api/auth/me'")
+
+	// Возвращаем пользователю информацию о нем
+	user := currentUser(c)
+	c.JSON(http.StatusOK, responceCurrentUser{ID: user.ID, Name: user.Name})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetCurrentUser api/auth/me' successfully done")
+}
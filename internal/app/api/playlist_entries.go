@@ -0,0 +1,251 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель с песней, добавляемой в плейлист (для работы с request body)
+type requestBodyPlaylistEntry struct {
+	SongID   int64 `json:"songId"`
+	Position *int  `json:"position"` // Номер, который получит запись (если не задан, песня добавляется в конец)
+}
+
+// Модель с новым номером записи плейлиста (для работы с request body)
+type requestBodyEntryMove struct {
+	Position *int `json:"position"`
+}
+
+// Модель ответа пользователю в случае успешного добавления записи в плейлист (содержит ее номер)
+type responceEntry struct {
+	Message  string `json:"message"`
+	Position int    `json:"position"`
+}
+
+// AddPlaylistEntry godoc
+//	@Summary		AddPlaylistEntry
+//	@Tags			playlist
+//	@Description	Insert song into playlist at given position (entries are numbered from 0, without position song is appended; song may be added several times)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		integer						true	"Playlist id"
+//	@Param			input	body		requestBodyPlaylistEntry	true	"Song and its position"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		201		{object}	responceEntry
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		403		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/playlists/{id}/entries [post]
+
+// Хэндлер для добавления песни в плейлист
+func (a *API) AddPlaylistEntry(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: AddPlaylistEntry api/playlists/:id/entries'")
+
+	// Получаем плейлист, если клиенту можно его изменять
+	playlist, ok := a.findPlaylist(c, true)
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	var reqEntry requestBodyPlaylistEntry
+	err := c.ShouldBindJSON(&reqEntry)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+		return
+	}
+	if reqEntry.SongID <= 0 {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: songId %d", reqEntry.SongID))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: songId value must be a positive number"})
+		return
+	}
+	position := -1
+	if reqEntry.Position != nil {
+		if *reqEntry.Position < 0 {
+			a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: position %d", *reqEntry.Position))
+			c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: position value must be a non-negative number"})
+			return
+		}
+		position = *reqEntry.Position
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: AddEntry")
+
+	// Вставляем песню в плейлист
+	position, err = a.storage.Playlist().AddEntry(playlist.ID, reqEntry.SongID, position)
+	if err == storage.ErrOutOfRange {
+		a.logger.Info(fmt.Sprintf("User trying to insert entry after the end of playlist. ID: %d, position: %d", playlist.ID, position))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: position value must not exceed the number of entries"})
+		return
+	}
+	if err == storage.ErrBrokenReference {
+		a.logger.Info(fmt.Sprintf("User trying to add non existed song to playlist. ID: %d, song ID: %d", playlist.ID, reqEntry.SongID))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: song does not exist"})
+		return
+	}
+	if !a.handlePlaylistEntryWriteError(c, playlist.ID, err) {
+		return
+	}
+
+	// Возвращаем пользователю номер добавленной записи
+	c.JSON(http.StatusCreated, responceEntry{Message: fmt.Sprintf("Song successfully add to playlist. ID: %d", playlist.ID), Position: position})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: AddPlaylistEntry api/playlists/:id/entries' successfully done")
+}
+
+// DeletePlaylistEntry godoc
+//	@Summary		DeletePlaylistEntry
+//	@Tags			playlist
+//	@Description	Remove entry from playlist (entries are numbered from 0, following entries are shifted)
+//	@Produce		json
+//	@Param			id			path		integer	true	"Playlist id"
+//	@Param			position	path		integer	true	"Entry number"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		403			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/playlists/{id}/entries/{position} [delete]
+
+// Хэндлер для удаления записи из плейлиста
+func (a *API) DeletePlaylistEntry(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'DELETE: DeletePlaylistEntry api/playlists/:id/entries/:position'")
+
+	// Получаем плейлист, если клиенту можно его изменять, и считываем номер записи
+	playlist, ok := a.findPlaylist(c, true)
+	if !ok {
+		return
+	}
+	position, ok := a.bindEntryPosition(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: DeleteEntry")
+
+	// Удаляем запись
+	err := a.storage.Playlist().DeleteEntry(playlist.ID, position)
+	if !a.handlePlaylistEntryWriteError(c, playlist.ID, err) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Entry successfully delete. ID: %d, position: %d", playlist.ID, position)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'DELETE: DeletePlaylistEntry api/playlists/:id/entries/:position' successfully done")
+}
+
+// MovePlaylistEntry godoc
+//	@Summary		MovePlaylistEntry
+//	@Tags			playlist
+//	@Description	Move entry of playlist so that it gets given number (entries are numbered from 0)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		integer					true	"Playlist id"
+//	@Param			position	path		integer					true	"Entry number"
+//	@Param			input		body		requestBodyEntryMove	true	"New entry number"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		403			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/playlists/{id}/entries/{position}/move [post]
+
+// Хэндлер для перемещения записи плейлиста
+func (a *API) MovePlaylistEntry(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: MovePlaylistEntry api/playlists/:id/entries/:position/move'")
+
+	// Получаем плейлист, если клиенту можно его изменять, и считываем номер записи
+	playlist, ok := a.findPlaylist(c, true)
+	if !ok {
+		return
+	}
+	from, ok := a.bindEntryPosition(c)
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	var reqMove requestBodyEntryMove
+	err := c.ShouldBindJSON(&reqMove)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+		return
+	}
+	if reqMove.Position == nil || *reqMove.Position < 0 {
+		a.logger.Error("User provide uncorrected JSON: position is empty or negative")
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: position value must be a non-negative number"})
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: MoveEntry")
+
+	// Перемещаем запись
+	err = a.storage.Playlist().MoveEntry(playlist.ID, from, *reqMove.Position)
+	if !a.handlePlaylistEntryWriteError(c, playlist.ID, err) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Entry successfully move. ID: %d, position: %d -> %d", playlist.ID, from, *reqMove.Position)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: MovePlaylistEntry api/playlists/:id/entries/:position/move' successfully done")
+}
+
+// Метод, считывающий номер записи плейлиста из пути запроса (в случае ошибки сам отвечает пользователю)
+func (a *API) bindEntryPosition(c *gin.Context) (int, bool) {
+	position, err := strconv.Atoi(c.Param("position"))
+	if err != nil || position < 0 {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected entry number in url: %s", c.Param("position")))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected entry number: position value must be a non-negative number"})
+		return 0, false
+	}
+
+	return position, true
+}
+
+// Метод, обрабатывающий ошибку изменения записей плейлиста (в случае ошибки сам отвечает пользователю)
+func (a *API) handlePlaylistEntryWriteError(c *gin.Context, id int64, err error) bool {
+	switch err {
+	case nil:
+		return true
+	case sql.ErrNoRows:
+		a.logger.Info(fmt.Sprintf("User trying to change non existed playlist. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to change non existed playlist"})
+	case storage.ErrOutOfRange:
+		a.logger.Info(fmt.Sprintf("User trying to change non existed entry of playlist. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Playlist has no entry with such number"})
+	default:
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table playlists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+	}
+
+	return false
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Функция, создающая плейлист и возвращающая путь к нему
+func addTestPlaylist(t *testing.T, a *API, token, body string) string {
+	t.Helper()
+
+	w := serve(a, http.MethodPost, "/api/playlists", body, "Authorization", bearer(token))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add playlist status = %d: %s", w.Code, w.Body)
+	}
+
+	var created responceCreated
+	decode(t, w, &created)
+	return fmt.Sprintf("/api/playlists/%d", created.ID)
+}
+
+// Функция, возвращающая номера песен плейлиста по порядку
+func getTestPlaylistSongs(t *testing.T, a *API, path string) []int64 {
+	t.Helper()
+
+	w := serve(a, http.MethodGet, path, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get playlist status = %d: %s", w.Code, w.Body)
+	}
+
+	var playlist models.Playlist
+	decode(t, w, &playlist)
+	songIDs := make([]int64, 0, len(playlist.Entries))
+	for i, entry := range playlist.Entries {
+		if entry.Position != i {
+			t.Errorf("entry %d position = %d", i, entry.Position)
+		}
+		songIDs = append(songIDs, entry.SongID)
+	}
+	return songIDs
+}
+
+func TestPlaylistEntries(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	first := addTestSong(t, a, "Muse", "Uprising", "one", "two")
+	second := addTestSong(t, a, "Queen", "Bohemian Rhapsody")
+	path := addTestPlaylist(t, a, token, `{"name":" Morning ","public":true}`)
+
+	// Песня добавляется в конец или на заданное место, одна песня может входить в плейлист несколько раз
+	for _, body := range []string{
+		fmt.Sprintf(`{"songId":%d}`, first),
+		fmt.Sprintf(`{"songId":%d}`, first),
+		fmt.Sprintf(`{"songId":%d,"position":1}`, second),
+	} {
+		if w := serve(a, http.MethodPost, path+"/entries", body, "Authorization", bearer(token)); w.Code != http.StatusCreated {
+			t.Fatalf("add entry %s status = %d: %s", body, w.Code, w.Body)
+		}
+	}
+	if got := getTestPlaylistSongs(t, a, path); fmt.Sprint(got) != fmt.Sprint([]int64{first, second, first}) {
+		t.Fatalf("songs = %v, want [%d %d %d]", got, first, second, first)
+	}
+
+	if w := serve(a, http.MethodPost, path+"/entries/0/move", `{"position":2}`, "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("move status = %d: %s", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodDelete, path+"/entries/1", "", "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("delete entry status = %d: %s", w.Code, w.Body)
+	}
+	if got := getTestPlaylistSongs(t, a, path); fmt.Sprint(got) != fmt.Sprint([]int64{second, first}) {
+		t.Errorf("songs = %v, want [%d %d]", got, second, first)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"missing song", http.MethodPost, path + "/entries", `{"songId":100}`, http.StatusBadRequest},
+		{"position after end", http.MethodPost, path + "/entries", fmt.Sprintf(`{"songId":%d,"position":3}`, first), http.StatusBadRequest},
+		{"move missing entry", http.MethodPost, path + "/entries/5/move", `{"position":0}`, http.StatusNotFound},
+		{"move without position", http.MethodPost, path + "/entries/0/move", `{}`, http.StatusBadRequest},
+		{"delete missing entry", http.MethodDelete, path + "/entries/2", "", http.StatusNotFound},
+		{"missing playlist", http.MethodGet, "/api/playlists/100", "", http.StatusNotFound},
+		{"empty name", http.MethodPost, "/api/playlists", `{"name":" "}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(a, tt.method, tt.path, tt.body, "Authorization", bearer(token)); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestPlaylistAccess(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	private := addTestPlaylist(t, a, token, `{"name":"Private"}`)
+	public := addTestPlaylist(t, a, token, `{"name":"Public","public":true}`)
+	other := loginTestUser(t, a, "bob")
+
+	// Чужой непубличный плейлист выглядит как несуществующий, а публичный можно только смотреть
+	if w := serve(a, http.MethodGet, private, "", "Authorization", bearer(other)); w.Code != http.StatusNotFound {
+		t.Errorf("get private playlist of other user status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := serve(a, http.MethodGet, private, ""); w.Code != http.StatusNotFound {
+		t.Errorf("get private playlist of anonymous client status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := serve(a, http.MethodGet, public, ""); w.Code != http.StatusOK {
+		t.Errorf("get public playlist of anonymous client status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := serve(a, http.MethodPut, public, `{"name":"Mine"}`, "Authorization", bearer(other)); w.Code != http.StatusForbidden {
+		t.Errorf("update playlist of other user status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := serve(a, http.MethodDelete, public, "", "Authorization", bearer(other)); w.Code != http.StatusForbidden {
+		t.Errorf("delete playlist of other user status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	w := serve(a, http.MethodGet, "/api/playlists?offset=0&limit=10", "", "Authorization", bearer(other))
+	var playlists responceAllPlaylists
+	decode(t, w, &playlists)
+	if len(playlists.Playlists) != 1 || playlists.Playlists[0].Name != "Public" {
+		t.Errorf("playlists of other user = %+v, want only public", playlists.Playlists)
+	}
+
+	// Владелец может изменить и удалить свой плейлист
+	if w := serve(a, http.MethodPut, private, `{"name":"Renamed"}`, "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Errorf("update status = %d: %s", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodDelete, private, "", "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Errorf("delete status = %d: %s", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodGet, private, "", "Authorization", bearer(token)); w.Code != http.StatusNotFound {
+		t.Errorf("get deleted playlist status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestExportPlaylist(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	first := addTestSong(t, a, "Muse", "Uprising")
+	second := addTestSong(t, a, "Queen", "Bohemian Rhapsody")
+	path := addTestPlaylist(t, a, token, `{"name":"Road trip"}`)
+	for _, id := range []int64{second, first} {
+		if w := serve(a, http.MethodPost, path+"/entries", fmt.Sprintf(`{"songId":%d}`, id), "Authorization", bearer(token)); w.Code != http.StatusCreated {
+			t.Fatalf("add entry status = %d: %s", w.Code, w.Body)
+		}
+	}
+
+	w := serve(a, http.MethodGet, path+"/export", "", "Authorization", bearer(token))
+	want := "#EXTM3U\n#PLAYLIST:Road trip\n" +
+		"#EXTINF:-1,Queen - Bohemian Rhapsody\nhttps://example.com/Bohemian Rhapsody\n" +
+		"#EXTINF:-1,Muse - Uprising\nhttps://example.com/Uprising\n"
+	if w.Code != http.StatusOK || w.Body.String() != want {
+		t.Fatalf("m3u8 export = %d %q, want %q", w.Code, w.Body, want)
+	}
+
+	// Песни из корзины остаются в плейлисте, но не выгружаются
+	if w := serve(a, http.MethodDelete, songPath(second), "", "If-Match", `"1"`, "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("delete song status = %d: %s", w.Code, w.Body)
+	}
+	w = serve(a, http.MethodGet, path, "", "Authorization", bearer(token))
+	var playlist models.Playlist
+	decode(t, w, &playlist)
+	if len(playlist.Entries) != 2 || !playlist.Entries[0].Deleted {
+		t.Errorf("entries = %+v, want deleted first entry", playlist.Entries)
+	}
+	w = serve(a, http.MethodGet, path+"/export?format=xspf", "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "Bohemian") || !strings.Contains(w.Body.String(), "<title>Road trip</title>") {
+		t.Errorf("xspf export = %d %s", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodGet, path+"/export?format=csv", "", "Authorization", bearer(token)); w.Code != http.StatusBadRequest {
+		t.Errorf("csv export status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
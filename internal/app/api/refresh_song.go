@@ -0,0 +1,265 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"mus_lib/internal/app/songinfo"
+	"mus_lib/storage"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю в случае массового обновления информации о песнях
+type responceRefreshSongs struct {
+	Songs   []*models.SongRefresh `json:"songs"`
+	Changed int                   `json:"changed"` // Количество песен, у которых сторонний API вернул отличающуюся информацию
+	Applied int                   `json:"applied"` // Количество песен, изменения которых сохранены
+	Failed  int                   `json:"failed"`  // Количество песен, информацию о которых получить или сохранить не удалось
+}
+
+// RefreshSong godoc
+//	@Summary		RefreshSong
+//	@Tags			song
+//	@Description	Re-query song info provider for release date, text and link of song and show what changed. Changes are saved only with apply=true (or REFRESH_AUTO_APPLY=true)
+//	@Produce		json
+//	@Param			id		path		integer	true	"Song id"
+//	@Param			apply	query		boolean	false	"Save changes (default is REFRESH_AUTO_APPLY)"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200		{object}	models.SongRefresh
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		409		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Failure		502		{object}	responceMessage
+//	@Failure		503		{object}	responceMessage
+//	@Router			/songs/{id}/refresh [post]
+
+// Хэндлер для обновления информации о песне из стороннего API
+func (a *API) RefreshSong(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: RefreshSong api/songs/:id/refresh'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Считываем, нужно ли сохранять изменения
+	apply, ok := a.bindApply(c)
+	if !ok {
+		return
+	}
+
+	// Получаем текущие данные песни
+	song, ok := a.getSong(c, id)
+	if !ok {
+		return
+	}
+
+	// Запрашиваем информацию о песне у стороннего API и сравниваем ее с сохраненной
+	refresh, updated, err := a.compareSongInfo(c.Request.Context(), song)
+	if errors.Is(err, songinfo.ErrSongNotFound) {
+		a.logger.Info(fmt.Sprintf("Song info provider does not know song. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Song info service has no data about this song"})
+		return
+	}
+	if err != nil {
+		a.songInfoFailed(c, err)
+		return
+	}
+
+	// Сохраняем изменения, если они есть и пользователь этого хочет
+	if apply && len(refresh.Changes) > 0 {
+		err = a.applySongInfo(c, refresh, updated)
+		if err == sql.ErrNoRows {
+			a.logger.Info(fmt.Sprintf("Song was deleted while its info was refreshed. ID: %d", id))
+			c.JSON(http.StatusNotFound, errorNotFoundMessage{"Song not found"})
+			return
+		}
+		if err == storage.ErrStaleVersion {
+			a.logger.Info(fmt.Sprintf("Song was changed while its info was refreshed. ID: %d", id))
+			c.JSON(http.StatusConflict, errorMessage{"Song was changed by another request while its info was refreshed: retry refresh"})
+			return
+		}
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+			c.JSON(http.StatusInternalServerError, serverError)
+			return
+		}
+	}
+
+	// Возвращаем пользователю изменения
+	c.JSON(http.StatusOK, refresh)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: RefreshSong api/songs/:id/refresh' successfully done")
+}
+
+// RefreshSongs godoc
+//	@Summary		RefreshSongs
+//	@Tags			song
+//	@Description	Re-query song info provider for songs on given filter (same parameters as GET /songs) and show what changed. Changes are saved only with apply=true (or REFRESH_AUTO_APPLY=true)
+//	@Produce		json
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list refreshed songs"
+//	@Param			limit	query		integer	true	"Limit of quantity refreshed songs"
+//	@Param			apply	query		boolean	false	"Save changes (default is REFRESH_AUTO_APPLY)"
+//	@Param			group	query		string	false	"Name of group"
+//	@Param			song	query		string	false	"Name of song"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200		{object}	responceRefreshSongs
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/songs/refresh [post]
+
+// Хэндлер для массового обновления информации о песнях из стороннего API
+func (a *API) RefreshSongs(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: RefreshSongs api/songs/refresh'")
+
+	// Считываем фильтр из query string (такой же, как у GetSongs)
+	filter, ok := a.bindSongFilter(c, true)
+	if !ok {
+		return
+	}
+
+	// Считываем, нужно ли сохранять изменения
+	apply, ok := a.bindApply(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetSongs")
+
+	// Получаем песни, которые нужно обновить
+	songs, err := a.storage.Song().GetSongs(filter)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	if len(songs) == 0 {
+		a.logger.Info(fmt.Sprintf("No found songs in DB (table %s)", os.Getenv("TABLE_NAME")))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"No found songs"})
+		return
+	}
+
+	// Обновляем песни по очереди (ошибка стороннего API или сохранения по одной песне не мешает обновлению остальных)
+	result := responceRefreshSongs{Songs: make([]*models.SongRefresh, 0, len(songs))}
+	for _, song := range songs {
+		refresh, updated, err := a.compareSongInfo(c.Request.Context(), song)
+		if err != nil {
+			a.logger.Warn(fmt.Sprintf("Failed to fetch song data. ID: %d: %s", song.ID, err))
+			refresh.Error = err.Error()
+			result.Failed++
+			result.Songs = append(result.Songs, refresh)
+			continue
+		}
+
+		if len(refresh.Changes) > 0 {
+			result.Changed++
+			if apply {
+				// Песню могли изменить или удалить, пока запрашивалась ее информация
+				err = a.applySongInfo(c, refresh, updated)
+				if err != nil {
+					a.logger.Warn(fmt.Sprintf("Failed to save song data. ID: %d: %s", song.ID, err))
+					refresh.Error = applyErrorText(err)
+					result.Failed++
+					result.Songs = append(result.Songs, refresh)
+					continue
+				}
+				result.Applied++
+			}
+		}
+		result.Songs = append(result.Songs, refresh)
+	}
+
+	// Возвращаем пользователю изменения по каждой песне
+	c.JSON(http.StatusOK, result)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: RefreshSongs api/songs/refresh' successfully done")
+}
+
+// Метод, считывающий параметр apply (если он не задан, используется REFRESH_AUTO_APPLY; в случае ошибки сам отвечает пользователю)
+func (a *API) bindApply(c *gin.Context) (bool, bool) {
+	value := c.Query("apply")
+	if value == "" {
+		return a.refreshAutoApply, true
+	}
+
+	apply, err := strconv.ParseBool(value)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected apply value in url: %s", value))
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: apply value must be true or false"})
+		return false, false
+	}
+
+	return apply, true
+}
+
+// Метод, запрашивающий информацию о песне у стороннего API и возвращающий ее отличия от сохраненной вместе с обновленной песней
+func (a *API) compareSongInfo(ctx context.Context, song *models.Song) (*models.SongRefresh, *models.Song, error) {
+	refresh := &models.SongRefresh{ID: song.ID, Group: song.Group, Song: song.Song, Changes: []models.FieldChange{}}
+
+	// Логируем обращение к стороннему API
+	a.logger.Debug("Sending a request to song info provider: GetSongInfo")
+
+	// Сохраненную информацию сравниваем с актуальным ответом, а не с закэшированным
+	detail, err := a.songInfo.GetSongInfo(songinfo.WithoutCache(ctx), song.Group, song.Song)
+	if err != nil {
+		return refresh, nil, err
+	}
+
+	// Дата релиза в неизвестном формате не должна затирать сохраненную, а известная приводится к виду, в котором ее хранит БД
+	updated := *song
+	err = detail.ApplyTo(&updated)
+	if err != nil {
+		a.logger.Warn(fmt.Sprintf("External API returned uncorrected release date, stored one is kept. ID: %d: %s", song.ID, err))
+		updated.ReleaseDate = song.ReleaseDate
+	} else if updated.ReleaseDate != "" {
+		date, precision, _ := models.ParseReleaseDate(updated.ReleaseDate)
+		updated.ReleaseDate = models.FormatReleaseDate(date, precision)
+	}
+
+	refresh.Changes = song.InfoChanges(&updated)
+	return refresh, &updated, nil
+}
+
+// Метод, сохраняющий обновленную информацию о песне, если песню не изменили после чтения (sql.ErrNoRows, если ее удалили; ErrStaleVersion, если изменили)
+func (a *API) applySongInfo(c *gin.Context, refresh *models.SongRefresh, updated *models.Song) error {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: UpdateSongInfo")
+
+	err := a.songStore(c).UpdateSongInfo(updated)
+	if err != nil {
+		return err
+	}
+	refresh.Applied = true
+
+	return nil
+}
+
+// Функция, возвращающая описание ошибки сохранения для ответа пользователю (внутренние ошибки БД не раскрываются)
+func applyErrorText(err error) string {
+	switch err {
+	case sql.ErrNoRows:
+		return "song was deleted while its info was refreshed"
+	case storage.ErrStaleVersion:
+		return "song was changed by another request while its info was refreshed"
+	default:
+		return "failed to save song info"
+	}
+}
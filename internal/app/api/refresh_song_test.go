@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"mus_lib/internal/app/models"
+	"mus_lib/internal/app/songinfo"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Источник информации о песнях, перед ответом вызывающий edit (имитирует действия пользователя, пока сервер ждет сторонний API)
+type editingSongInfo struct {
+	songinfo.Provider
+	edit func(song string)
+}
+
+func (p *editingSongInfo) GetSongInfo(ctx context.Context, group, song string) (*songinfo.SongDetail, error) {
+	if p.edit != nil {
+		p.edit(song)
+	}
+
+	return p.Provider.GetSongInfo(ctx, group, song)
+}
+
+// Функция, меняющая ссылку песни в хранилище в обход хэндлеров
+func changeTestSongLink(t *testing.T, a *API, id int64, link string) {
+	t.Helper()
+
+	old, err := a.storage.Song().GetSong(id)
+	if err != nil {
+		t.Fatalf("GetSong() error = %v", err)
+	}
+	changed := *old
+	changed.Link = link
+	err = a.storage.Song().UpdateSongInfo(&changed)
+	if err != nil {
+		t.Fatalf("UpdateSongInfo() error = %v", err)
+	}
+}
+
+func TestRefreshSong(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "verse")
+
+	// Без apply изменения только показываются
+	w := serve(a, http.MethodPost, songPath(id)+"/refresh", "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var refresh models.SongRefresh
+	decode(t, w, &refresh)
+	if len(refresh.Changes) != 3 || refresh.Applied {
+		t.Fatalf("refresh = %+v, want 3 unsaved changes", refresh)
+	}
+	if song := getTestSong(t, a, id); song.Link != "https://example.com/Uprising" {
+		t.Errorf("song link = %q, want unchanged", song.Link)
+	}
+
+	if w := serve(a, http.MethodPost, songPath(id)+"/refresh?apply=maybe", "", "Authorization", bearer(token)); w.Code != http.StatusBadRequest {
+		t.Errorf("uncorrected apply status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w = serve(a, http.MethodPost, songPath(id)+"/refresh?apply=true", "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("apply status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	refresh = models.SongRefresh{}
+	decode(t, w, &refresh)
+	if !refresh.Applied {
+		t.Errorf("refresh = %+v, want applied changes", refresh)
+	}
+	if song := getTestSong(t, a, id); song.Link != songinfo.DefaultFakeDetail.Link || song.ReleaseDate != "01.01.1990" {
+		t.Errorf("song = %+v, want refreshed info", song)
+	}
+
+	if w := serve(a, http.MethodPost, songPath(id+1)+"/refresh", "", "Authorization", bearer(token)); w.Code != http.StatusNotFound {
+		t.Errorf("missing song status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestRefreshSongChangedConcurrently(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "verse")
+	a.songInfo = &editingSongInfo{Provider: songinfo.NewFake(), edit: func(string) {
+		changeTestSongLink(t, a, id, "https://example.com/edited")
+	}}
+
+	// Правка пользователя, сделанная пока сервер ждал сторонний API, не затирается
+	w := serve(a, http.MethodPost, songPath(id)+"/refresh?apply=true", "", "Authorization", bearer(token))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusConflict, w.Body)
+	}
+	if song := getTestSong(t, a, id); song.Link != "https://example.com/edited" || song.ReleaseDate != "" {
+		t.Errorf("song = %+v, want user edit kept", song)
+	}
+}
+
+func TestRefreshSongs(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	edited := addTestSong(t, a, "Muse", "Hysteria", "verse")
+	addTestSong(t, a, "Muse", "Uprising", "verse")
+	a.songInfo = &editingSongInfo{Provider: songinfo.NewFake(), edit: func(song string) {
+		if strings.EqualFold(song, "Hysteria") {
+			changeTestSongLink(t, a, edited, "https://example.com/edited")
+		}
+	}}
+
+	// Ошибка сохранения одной песни не прерывает обновление остальных
+	w := serve(a, http.MethodPost, "/api/songs/refresh?group=Muse&offset=0&limit=10&apply=true", "", "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var result responceRefreshSongs
+	decode(t, w, &result)
+	if result.Changed != 2 || result.Applied != 1 || result.Failed != 1 {
+		t.Fatalf("result = %+v, want 2 changed, 1 applied and 1 failed", result)
+	}
+	for _, refresh := range result.Songs {
+		failed := refresh.ID == edited
+		if refresh.Applied == failed || (refresh.Error != "") != failed {
+			t.Errorf("refresh = %+v, want failed = %t", refresh, failed)
+		}
+	}
+
+	if w := serve(a, http.MethodPost, "/api/songs/refresh?group=Queen&offset=0&limit=10", "", "Authorization", bearer(token)); w.Code != http.StatusNotFound {
+		t.Errorf("no songs status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
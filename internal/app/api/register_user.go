@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/auth"
+	"mus_lib/internal/app/models"
+	"mus_lib/storage"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Допустимое имя пользователя (латиница в нижнем регистре, цифры и _.-; начинается с буквы, поэтому не совпадает с адресом клиента,
+// которым подписаны ревизии и плейлисты, созданные до появления пользователей)
+var userNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]{2,31}$`)
+
+// Модель с именем и паролем пользователя (для работы с request body)
+type requestBodyCredentials struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// Register godoc
+//	@Summary		Register
+//	@Tags			user
+//	@Description	Create user (name is case insensitive: 3-32 latin letters, digits and _.-, starting with letter; password is 8-72 bytes)
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		requestBodyCredentials	true	"Name and password"
+//	@Success		201		{object}	responceCreated
+//	@Failure		400		{object}	responceMessage
+//	@Failure		409		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/auth/register [post]
+
+// Хэндлер для регистрации пользователя
+func (a *API) Register(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: Register api/auth/register'")
+
+	// Парсим request body
+	credentials, ok := a.bindRequestBodyCredentials(c)
+	if !ok {
+		return
+	}
+	if !userNamePattern.MatchString(credentials.Name) {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: name is %q", credentials.Name))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: name value must be 3-32 latin letters, digits, '_', '.' or '-' and start with a letter"})
+		return
+	}
+	if len(credentials.Password) < auth.MinPasswordLength || len(credentials.Password) > auth.MaxPasswordLength {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: password length is %d", len(credentials.Password)))
+		c.JSON(http.StatusBadRequest, errorMessage{fmt.Sprintf("You provide uncorrected JSON: password value must be %d-%d bytes long", auth.MinPasswordLength, auth.MaxPasswordLength)})
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(credentials.Password)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with hashing password: %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	user := &models.User{Name: credentials.Name, PasswordHash: passwordHash}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: AddUser")
+
+	// Добавляем пользователя в БД
+	err = a.storage.User().AddUser(user)
+	if err == storage.ErrAlreadyExists {
+		a.logger.Info(fmt.Sprintf("User trying to register existed name. Name: %s", user.Name))
+		c.JSON(http.StatusConflict, errorMessage{"User with such name already exists"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table users): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusCreated, responceCreated{Message: fmt.Sprintf("User successfully registered. Name: %s", user.Name), ID: user.ID})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: Register api/auth/register' successfully done")
+}
+
+// Метод, считывающий request body с именем и паролем пользователя (имя приводится к нижнему регистру; в случае ошибки сам отвечает пользователю)
+func (a *API) bindRequestBodyCredentials(c *gin.Context) (*requestBodyCredentials, bool) {
+	var credentials requestBodyCredentials
+	err := c.ShouldBindJSON(&credentials)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+		return nil, false
+	}
+	credentials.Name = strings.ToLower(strings.TrimSpace(credentials.Name))
+	if credentials.Name == "" || credentials.Password == "" {
+		a.logger.Error("User provide uncorrected JSON: name or password is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: name and password values must be not empty"})
+		return nil, false
+	}
+
+	return &credentials, true
+}
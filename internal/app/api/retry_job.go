@@ -0,0 +1,65 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/storage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetryJob godoc
+//	@Summary		RetryJob
+//	@Tags			job
+//	@Description	Put dead enrichment job back to the queue (attempts counter is reset)
+//	@Produce		json
+//	@Param			id	path		integer	true	"Job id"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	responceMessage
+//	@Failure		400	{object}	responceMessage
+//	@Failure		401	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		409	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/jobs/{id}/retry [post]
+
+// Хэндлер для повторного запуска задания, все попытки которого исчерпаны
+func (a *API) RetryJob(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: RetryJob api/jobs/:id/retry'")
+
+	// Считываем идентификатор задания
+	id, ok := a.bindPathID(c, "id", "job")
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: RetryJob")
+
+	// Возвращаем задание в очередь
+	err := a.storage.Job().RetryJob(id)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		a.logger.Info(fmt.Sprintf("User trying to retry non existed job. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Job not found"})
+		return
+	case storage.ErrWrongState:
+		a.logger.Info(fmt.Sprintf("User trying to retry job that is not dead. ID: %d", id))
+		c.JSON(http.StatusConflict, errorMessage{"Only dead jobs can be retried"})
+		return
+	default:
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table enrichment_jobs): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Job %d is queued again", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: RetryJob api/jobs/:id/retry' successfully done")
+}
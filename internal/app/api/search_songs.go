@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю для возвращения результатов поиска по текстам песен
+type responceSearchSongs struct {
+	Results []*models.SongSearchResult `json:"results"`
+}
+
+// SearchSongs godoc
+//	@Summary		SearchSongs
+//	@Tags			song
+//	@Description	Full-text search over lyrics. Supports "quoted phrases", or and -exclusion. Results are ordered by relevance, matched verses are returned with highlighted snippets
+//	@Produce		json
+//	@Param			q		query		string	true	"Search query"
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list found songs"
+//	@Param			limit	query		integer	true	"Limit of quantity found songs"
+//	@Success		200		{object}	responceSearchSongs
+//	@Failure		400		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/songs/search [get]
+
+// Хэндлер для полнотекстового поиска песен по тексту
+func (a *API) SearchSongs(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: SearchSongs api/songs/search'")
+
+	// Считываем поисковый запрос
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		a.logger.Error("User provide uncorrected query string in url: q is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: q value must be not empty"})
+		return
+	}
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: SearchSongs")
+
+	// Выполняем поиск в БД
+	results, err := a.storage.Song().SearchSongs(query, offsetVal, limitVal)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	if len(results) == 0 {
+		a.logger.Info(fmt.Sprintf("No found songs in DB (table %s) for query: %s", os.Getenv("TABLE_NAME"), query))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"No found songs"})
+		return
+	}
+
+	// Возвращаем пользователю найденные песни
+	c.JSON(http.StatusOK, responceSearchSongs{Results: results})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: SearchSongs api/songs/search' successfully done")
+}
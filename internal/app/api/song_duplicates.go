@@ -0,0 +1,66 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Режим проверки добавляемой песни на почти-дубликаты (например, "Supermasive Black Hole" при существующей "Supermassive Black Hole")
+type duplicateMode string
+
+const (
+	duplicateOff    duplicateMode = "off"    // Проверка отключена
+	duplicateWarn   duplicateMode = "warn"   // Песня добавляется, а похожие песни возвращаются в ответе (по умолчанию)
+	duplicateReject duplicateMode = "reject" // Песня не добавляется, если есть похожие (можно обойти параметром force=true)
+)
+
+// Порог сходства по умолчанию, начиная с которого песня считается почти-дубликатом
+const defaultDuplicateThreshold = 0.8
+
+// Максимальное количество похожих песен, возвращаемых пользователю
+const similarSongsLimit = 5
+
+// Настройки проверки добавляемых песен на почти-дубликаты
+type duplicateCheck struct {
+	mode      duplicateMode
+	threshold float64 // Минимальное сходство и исполнителя, и названия песни (от 0 до 1)
+}
+
+// Модель ответа пользователю в случае отказа в добавлении почти-дубликата
+type errorDuplicateMessage struct {
+	Message string                   `json:"message"`
+	Similar []*models.SongSuggestion `json:"similar"`
+}
+
+// Метод, ищущий песни, похожие на добавляемую (в случае ошибки или отказа в добавлении сам отвечает пользователю)
+func (a *API) findSimilarSongs(c *gin.Context, group, song string, force bool) ([]*models.SongSuggestion, bool) {
+	if a.duplicates.mode == duplicateOff {
+		return nil, true
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: FindSimilarSongs")
+
+	similar, err := a.storage.Song().FindSimilarSongs(group, song, a.duplicates.threshold, similarSongsLimit)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return nil, false
+	}
+	if len(similar) == 0 {
+		return nil, true
+	}
+
+	if a.duplicates.mode == duplicateReject && !force {
+		a.logger.Info(fmt.Sprintf("User trying to add song similar to existed ones. Group: %s, song: %s", group, song))
+		c.JSON(http.StatusConflict, errorDuplicateMessage{Message: "You trying to add song that is similar to existed ones. Use force=true to add it anyway", Similar: similar})
+		return nil, false
+	}
+
+	a.logger.Warn(fmt.Sprintf("User add song similar to existed ones. Group: %s, song: %s", group, song))
+	return similar, true
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"mus_lib/storage"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю для возвращения истории изменений песни
+type responceSongHistory struct {
+	Revisions []*models.SongRevision `json:"revisions"`
+}
+
+// GetSongHistory godoc
+//	@Summary		GetSongHistory
+//	@Tags			song
+//	@Description	Retrieve history of song changes (newest first, history is kept after song is deleted)
+//	@Produce		json
+//	@Param			id		path		integer	true	"Song id"
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list extracted revisions"
+//	@Param			limit	query		integer	true	"Limit of quantity extracted revisions"
+//	@Success		200		{object}	responceSongHistory
+//	@Failure		400		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/songs/{id}/history [get]
+
+// Хэндлер для получения истории изменений песни
+func (a *API) GetSongHistory(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetSongHistory api/songs/:id/history'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetRevisions")
+
+	// Получаем ревизии песни из БД
+	revisions, err := a.storage.Revision().GetRevisions(id, offsetVal, limitVal)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table song_revisions): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	if len(revisions) == 0 {
+		a.logger.Info(fmt.Sprintf("No found revisions of song. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"No found revisions of song"})
+		return
+	}
+
+	// Возвращаем пользователю историю изменений
+	c.JSON(http.StatusOK, responceSongHistory{Revisions: revisions})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetSongHistory api/songs/:id/history' successfully done")
+}
+
+// GetSongRevision godoc
+//	@Summary		GetSongRevision
+//	@Tags			song
+//	@Description	Retrieve revision of song (song before and after the change)
+//	@Produce		json
+//	@Param			id	path		integer	true	"Song id"
+//	@Param			rev	path		integer	true	"Revision number"
+//	@Success		200	{object}	models.SongRevision
+//	@Failure		400	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/songs/{id}/history/{rev} [get]
+
+// Хэндлер для получения ревизии песни
+func (a *API) GetSongRevision(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetSongRevision api/songs/:id/history/:rev'")
+
+	// Считываем идентификатор песни и номер ревизии
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+	rev, ok := a.bindPathID(c, "rev", "revision")
+	if !ok {
+		return
+	}
+
+	// Получаем ревизию из БД
+	revision, ok := a.getRevision(c, id, rev)
+	if !ok {
+		return
+	}
+
+	// Возвращаем пользователю ревизию
+	c.JSON(http.StatusOK, revision)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetSongRevision api/songs/:id/history/:rev' successfully done")
+}
+
+// RestoreSongRevision godoc
+//	@Summary		RestoreSongRevision
+//	@Tags			song
+//	@Description	Restore song to the state after given revision (deleted song is added again with the same id)
+//	@Produce		json
+//	@Param			id			path		integer	true	"Song id"
+//	@Param			rev			path		integer	true	"Revision number"
+//	@Param			If-Match	header		string	false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true, if song is not deleted)"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	models.Song
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		409			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/songs/{id}/restore/{rev} [post]
+
+// Хэндлер для восстановления песни в том виде, который она имела после ревизии
+func (a *API) RestoreSongRevision(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: RestoreSongRevision api/songs/:id/restore/:rev'")
+
+	// Считываем идентификатор песни и номер ревизии
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+	rev, ok := a.bindPathID(c, "rev", "revision")
+	if !ok {
+		return
+	}
+
+	// Получаем ревизию из БД
+	revision, ok := a.getRevision(c, id, rev)
+	if !ok {
+		return
+	}
+	if revision.New == nil {
+		a.logger.Info(fmt.Sprintf("User trying to restore deletion revision. ID: %d, revision: %d", id, rev))
+		c.JSON(http.StatusConflict, errorMessage{"Revision deletes song: restore one of previous revisions"})
+		return
+	}
+	song := revision.New
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetSong")
+
+	// Существующая песня изменяется, как при PUT, а удаленная добавляется заново
+	_, err := a.storage.Song().GetSong(id)
+	switch {
+	case err == nil:
+		song.Version, ok = a.expectedVersion(c, id)
+		if !ok {
+			return
+		}
+		if !a.updateSong(c, song, true) {
+			return
+		}
+	case err == sql.ErrNoRows:
+		if !a.restoreSong(c, song) {
+			return
+		}
+	default:
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю восстановленную песню
+	restored, ok := a.getSong(c, id)
+	if !ok {
+		return
+	}
+	c.Header("ETag", songETag(restored.Version))
+	c.JSON(http.StatusOK, restored)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: RestoreSongRevision api/songs/:id/restore/:rev' successfully done")
+}
+
+// Метод, получающий ревизию песни из БД (в случае ошибки сам отвечает пользователю)
+func (a *API) getRevision(c *gin.Context, id, rev int64) (*models.SongRevision, bool) {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetRevision")
+
+	revision, err := a.storage.Revision().GetRevision(id, rev)
+	// Если ревизия не найдена
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to get non existed revision. ID: %d, revision: %d", id, rev))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Revision not found"})
+		return nil, false
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table song_revisions): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return nil, false
+	}
+
+	return revision, true
+}
+
+// Метод, добавляющий удаленную песню заново с прежним идентификатором (в случае ошибки сам отвечает пользователю)
+func (a *API) restoreSong(c *gin.Context, song *models.Song) bool {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: RestoreSong")
+
+	err := a.songStore(c).RestoreSong(song)
+	switch err {
+	case nil:
+		return true
+	case storage.ErrBrokenReference:
+		a.logger.Info(fmt.Sprintf("User trying to restore song into deleted album. ID: %d", song.ID))
+		c.JSON(http.StatusConflict, errorMessage{"Album of this revision no longer exists"})
+	case storage.ErrWrongState:
+		a.logger.Info(fmt.Sprintf("User trying to restore revision of song in trash. ID: %d", song.ID))
+		c.JSON(http.StatusConflict, errorMessage{"Song is in trash: restore it first (POST /songs/{id}/restore)"})
+	case storage.ErrAlreadyExists:
+		a.logger.Info(fmt.Sprintf("User trying to restore song that conflicts with existed one. ID: %d", song.ID))
+		c.JSON(http.StatusConflict, errorMessage{"Library already has such song or album already has a song with such disc and track number"})
+	default:
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+	}
+
+	return false
+}
+
+// Метод, возвращающий репозиторий песен, изменения через который записываются в историю от имени пользователя
+func (a *API) songStore(c *gin.Context) storage.SongStore {
+	return a.storage.Song().WithAuthor(a.requester(c))
+}
+
+// Метод, возвращающий репозиторий заданий, добавление песен через который записывается в историю от имени пользователя
+func (a *API) jobStore(c *gin.Context) storage.JobStore {
+	return a.storage.Job().WithAuthor(a.requester(c))
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"mus_lib/internal/app/models"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Функция, возвращающая историю изменений песни
+func getTestHistory(t *testing.T, a *API, id int64) []*models.SongRevision {
+	t.Helper()
+
+	w := serve(a, http.MethodGet, songPath(id)+"/history?offset=0&limit=10", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("history status = %d: %s", w.Code, w.Body)
+	}
+
+	var history responceSongHistory
+	decode(t, w, &history)
+	return history.Revisions
+}
+
+func TestSongHistory(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "one", "two")
+
+	if w := serve(a, http.MethodPatch, songPath(id), `{"link":"https://example.com/new"}`, "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("patch status = %d: %s", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodDelete, songPath(id)+"/text/0", "", "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("delete verse status = %d: %s", w.Code, w.Body)
+	}
+
+	// Последние изменения идут первыми, номер ревизии равен версии песни после изменения
+	history := getTestHistory(t, a, id)
+	if len(history) != 3 {
+		t.Fatalf("history = %d revisions, want 3", len(history))
+	}
+	wantActions := []models.RevisionAction{models.RevisionUpdate, models.RevisionUpdate, models.RevisionCreate}
+	for i, revision := range history {
+		if revision.Revision != int64(3-i) || revision.Action != wantActions[i] {
+			t.Errorf("revision %d = %d %s, want %d %s", i, revision.Revision, revision.Action, 3-i, wantActions[i])
+		}
+	}
+	// Изменения через API записываются от имени пользователя, а добавление в обход API - без автора
+	if history[0].Author != "alice" || history[2].Author != "" {
+		t.Errorf("authors = %q, %q; want user name and empty", history[0].Author, history[2].Author)
+	}
+	if patch := history[1]; patch.Old.Link != "https://example.com/Uprising" || patch.New.Link != "https://example.com/new" {
+		t.Errorf("patch revision = %+v -> %+v, want link change", patch.Old, patch.New)
+	}
+
+	w := serve(a, http.MethodGet, songPath(id)+"/history/1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("revision status = %d: %s", w.Code, w.Body)
+	}
+	var revision models.SongRevision
+	decode(t, w, &revision)
+	if revision.Action != models.RevisionCreate || revision.Old != nil || len(revision.New.Text) != 2 {
+		t.Errorf("revision = %+v, want creation with two verses", revision)
+	}
+
+	if w := serve(a, http.MethodGet, songPath(id)+"/history/9", ""); w.Code != http.StatusNotFound {
+		t.Errorf("missing revision status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := serve(a, http.MethodGet, songPath(id+1)+"/history?offset=0&limit=10", ""); w.Code != http.StatusNotFound {
+		t.Errorf("history of missing song status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestRestoreSongRevision(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addTestSong(t, a, "Muse", "Uprising", "one", "two")
+	if w := serve(a, http.MethodPatch, songPath(id), `{"text":["changed"]}`, "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("patch status = %d: %s", w.Code, w.Body)
+	}
+
+	// Существующая песня заменяется, а восстановление тоже попадает в историю
+	w := serve(a, http.MethodPost, songPath(id)+"/restore/1", "", "If-Match", `"2"`, "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("restore status = %d: %s", w.Code, w.Body)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("ETag = %q, want %q", etag, `"3"`)
+	}
+	if song := getTestSong(t, a, id); len(song.Text) != 2 {
+		t.Errorf("text = %v, want restored text", song.Text)
+	}
+	if w := serve(a, http.MethodPost, songPath(id)+"/restore/1", "", "If-Match", `"2"`, "Authorization", bearer(token)); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("restore with stale version status = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+
+	// Ревизию удаления восстановить нельзя, а песню из корзины нужно сначала вернуть
+	if w := serve(a, http.MethodDelete, songPath(id), "", "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d: %s", w.Code, w.Body)
+	}
+	if w := serve(a, http.MethodPost, songPath(id)+"/restore/4", "", "Authorization", bearer(token)); w.Code != http.StatusConflict {
+		t.Errorf("restore of deletion status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := serve(a, http.MethodPost, songPath(id)+"/restore/2", "", "Authorization", bearer(token)); w.Code != http.StatusConflict {
+		t.Errorf("restore of trashed song status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	// Окончательно удаленная песня добавляется заново с прежним id
+	if _, err := a.storage.Song().PurgeDeletedSongs(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("PurgeDeletedSongs() error = %v", err)
+	}
+	if w := serve(a, http.MethodPost, songPath(id)+"/restore/2", "", "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("restore of deleted song status = %d: %s", w.Code, w.Body)
+	}
+	if song := getTestSong(t, a, id); !slices.Equal(song.Text, []string{"changed"}) {
+		t.Errorf("text = %v, want text of revision 2", song.Text)
+	}
+	if history := getTestHistory(t, a, id); history[0].Action != models.RevisionCreate {
+		t.Errorf("last revision = %s, want %s", history[0].Action, models.RevisionCreate)
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Метод, считывающий идентификатор песни из пути запроса (в случае ошибки сам отвечает пользователю)
+func (a *API) bindSongID(c *gin.Context) (int64, bool) {
+	return a.bindPathID(c, "id", "song")
+}
+
+// Метод, считывающий идентификатор сущности entity из параметра пути param (в случае ошибки сам отвечает пользователю)
+func (a *API) bindPathID(c *gin.Context, param, entity string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil || id <= 0 {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected %s id in url: %s", entity, c.Param(param)))
+		c.JSON(http.StatusBadRequest, errorMessage{fmt.Sprintf("URL have uncorrected %s id: %s value must be a positive number", entity, param)})
+		return 0, false
+	}
+
+	return id, true
+}
+
+// Метод, ищущий идентификатор песни по названиям исполнителя и песни (action используется в сообщении пользователю, в случае ошибки сам отвечает пользователю)
+func (a *API) findSongID(c *gin.Context, group, song, action string) (int64, bool) {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: FindSongID")
+
+	// Ищем песню в БД
+	id, err := a.storage.Song().FindSongID(group, song)
+	// Если песня не найдена
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to %s non existed song. Group: %s, song: %s", action, group, song))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{fmt.Sprintf("You trying to %s non existed song", action)})
+		return 0, false
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Модель ответа пользователю для возвращения песен, похожих на запрос
+type responceSuggestSongs struct {
+	Suggestions []*models.SongSuggestion `json:"suggestions"`
+}
+
+// SuggestSongs godoc
+//	@Summary		SuggestSongs
+//	@Tags			song
+//	@Description	Fuzzy lookup of songs by name of group or song (trigram similarity, typos are allowed). Songs are ordered by similarity
+//	@Produce		json
+//	@Param			q		query		string	true	"Name of group or song"
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list found songs"
+//	@Param			limit	query		integer	true	"Limit of quantity found songs"
+//	@Success		200		{object}	responceSuggestSongs
+//	@Failure		400		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/songs/suggest [get]
+
+// Хэндлер для нечеткого поиска песен по названию исполнителя или песни
+func (a *API) SuggestSongs(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: SuggestSongs api/songs/suggest'")
+
+	// Считываем запрос
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		a.logger.Error("User provide uncorrected query string in url: q is empty")
+		c.JSON(http.StatusBadRequest, errorMessage{"URL have uncorrected parameters in the query string: q value must be not empty"})
+		return
+	}
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: SuggestSongs")
+
+	// Выполняем поиск в БД
+	suggestions, err := a.storage.Song().SuggestSongs(query, offsetVal, limitVal)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	if len(suggestions) == 0 {
+		a.logger.Info(fmt.Sprintf("No found similar songs in DB (table %s) for query: %s", os.Getenv("TABLE_NAME"), query))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"No found songs"})
+		return
+	}
+
+	// Возвращаем пользователю найденные песни
+	c.JSON(http.StatusOK, responceSuggestSongs{Suggestions: suggestions})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: SuggestSongs api/songs/suggest' successfully done")
+}
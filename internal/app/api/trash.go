@@ -0,0 +1,114 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/storage"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrash godoc
+//	@Summary		GetTrash
+//	@Tags			song
+//	@Description	Retrieve deleted songs from trash (recently deleted first)
+//	@Produce		json
+//	@Param			offset	query		integer	true	"Offset from the beginning of the list extracted songs"
+//	@Param			limit	query		integer	true	"Limit of quantity extracted songs"
+//	@Success		200		{object}	responceAllSongs
+//	@Failure		400		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/trash [get]
+
+// Хэндлер для получения песен из корзины
+func (a *API) GetTrash(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'GET: GetTrash api/trash'")
+
+	// Считываем значения смещения и лимита
+	offsetVal, limitVal, ok := a.bindPagination(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: GetTrash")
+
+	// Получаем песни из корзины
+	songs, err := a.storage.Song().GetTrash(offsetVal, limitVal)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю песни из корзины
+	c.JSON(http.StatusOK, responceAllSongs{Songs: songs})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'GET: GetTrash api/trash' successfully done")
+}
+
+// UndeleteSong godoc
+//	@Summary		UndeleteSong
+//	@Tags			song
+//	@Description	Restore song from trash
+//	@Produce		json
+//	@Param			id	path		integer	true	"Song id"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	models.Song
+//	@Failure		400	{object}	responceMessage
+//	@Failure		401	{object}	responceMessage
+//	@Failure		404	{object}	responceMessage
+//	@Failure		409	{object}	responceMessage
+//	@Failure		500	{object}	responceMessage
+//	@Router			/songs/{id}/restore [post]
+
+// Хэндлер для возвращения песни из корзины
+func (a *API) UndeleteSong(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'POST: UndeleteSong api/songs/:id/restore'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: UndeleteSong")
+
+	// Возвращаем песню из корзины
+	err := a.songStore(c).UndeleteSong(id)
+	// Если песни в корзине нет
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to restore song that is not in trash. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"Song not found in trash"})
+		return
+	}
+	// Если пока песня лежала в корзине, ее место заняла другая песня
+	if err == storage.ErrAlreadyExists {
+		a.logger.Info(fmt.Sprintf("User trying to restore song that conflicts with existed one. ID: %d", id))
+		c.JSON(http.StatusConflict, errorMessage{"Library already has such song or album already has a song with such disc and track number"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю восстановленную песню
+	song, ok := a.getSong(c, id)
+	if !ok {
+		return
+	}
+	c.Header("ETag", songETag(song.Version))
+	c.JSON(http.StatusOK, song)
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'POST: UndeleteSong api/songs/:id/restore' successfully done")
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateAlbum godoc
+//	@Summary		UpdateAlbum
+//	@Tags			album
+//	@Description	Update album by id (track list is replaced only if it is provided)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		integer				true	"Album id"
+//	@Param			input	body		requestBodyAlbum	true	"New album info"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200		{object}	responceMessage
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		409		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/albums/{id} [put]
+
+// Хэндлер для изменения альбома
+func (a *API) UpdateAlbum(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'PUT: UpdateAlbum api/albums/:id'")
+
+	// Считываем идентификатор альбома
+	id, ok := a.bindPathID(c, "id", "album")
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	album, ok := a.bindRequestBodyAlbum(c)
+	if !ok {
+		return
+	}
+	album.ID = id
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: UpdateAlbum")
+
+	// Обновляем данные альбома
+	err := a.storage.Album().UpdateAlbum(album)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to update non existed album. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to update non existed album"})
+		return
+	}
+	if !a.handleAlbumWriteError(c, err) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Album successfully update. ID: %d", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'PUT: UpdateAlbum api/albums/:id' successfully done")
+}
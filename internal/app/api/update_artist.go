@@ -0,0 +1,74 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/storage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateArtist godoc
+//	@Summary		UpdateArtist
+//	@Tags			artist
+//	@Description	Update artist by id (all songs of artist are changed too)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		integer				true	"Artist id"
+//	@Param			input	body		requestBodyArtist	true	"New artist info"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200		{object}	responceMessage
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		409		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/artists/{id} [put]
+
+// Хэндлер для изменения исполнителя
+func (a *API) UpdateArtist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'PUT: UpdateArtist api/artists/:id'")
+
+	// Считываем идентификатор исполнителя
+	id, ok := a.bindPathID(c, "id", "artist")
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	artist, ok := a.bindRequestBodyArtist(c)
+	if !ok {
+		return
+	}
+	artist.ID = id
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: UpdateArtist")
+
+	// Обновляем данные исполнителя
+	err := a.storage.Artist().UpdateArtist(artist)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to update non existed artist. ID: %d", id))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to update non existed artist"})
+		return
+	}
+	if err == storage.ErrAlreadyExists {
+		a.logger.Info(fmt.Sprintf("User trying to rename artist to existed one. Name: %s", artist.Name))
+		c.JSON(http.StatusConflict, errorMessage{"Artist with such name already exists"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table artists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Artist successfully update. ID: %d", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'PUT: UpdateArtist api/artists/:id' successfully done")
+}
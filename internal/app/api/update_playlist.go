@@ -0,0 +1,68 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdatePlaylist godoc
+//	@Summary		UpdatePlaylist
+//	@Tags			playlist
+//	@Description	Rename playlist and replace its description and public flag (only owner can change playlist)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		integer				true	"Playlist id"
+//	@Param			input	body		requestBodyPlaylist	true	"New playlist info"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200		{object}	responceMessage
+//	@Failure		400		{object}	responceMessage
+//	@Failure		401		{object}	responceMessage
+//	@Failure		403		{object}	responceMessage
+//	@Failure		404		{object}	responceMessage
+//	@Failure		500		{object}	responceMessage
+//	@Router			/playlists/{id} [put]
+
+// Хэндлер для изменения плейлиста
+func (a *API) UpdatePlaylist(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'PUT: UpdatePlaylist api/playlists/:id'")
+
+	// Получаем плейлист, если клиенту можно его изменять
+	stored, ok := a.findPlaylist(c, true)
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	playlist, ok := a.bindRequestBodyPlaylist(c)
+	if !ok {
+		return
+	}
+	playlist.ID = stored.ID
+
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: UpdatePlaylist")
+
+	// Изменяем плейлист (его могли удалить, пока запрос выполнялся)
+	err := a.storage.Playlist().UpdatePlaylist(playlist)
+	if err == sql.ErrNoRows {
+		a.logger.Info(fmt.Sprintf("User trying to update non existed playlist. ID: %d", playlist.ID))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to update non existed playlist"})
+		return
+	}
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table playlists): %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Playlist successfully update. ID: %d", playlist.ID)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'PUT: UpdatePlaylist api/playlists/:id' successfully done")
+}
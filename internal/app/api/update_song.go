@@ -1,30 +1,66 @@
 package api
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mus_lib/internal/app/models"
+	"mus_lib/storage"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Модель со всей изменяемой информацией о песне (для работы с request body PUT запроса, отсутствующие поля очищаются)
+type requestPutSong struct {
+	Group       string   `json:"group"`
+	Song        string   `json:"song"`
+	ReleaseDate string   `json:"releaseDate"`
+	Text        []string `json:"text"`
+	Link        string   `json:"link"`
+	AlbumID     *int64   `json:"albumId"`
+	DiscNumber  *int     `json:"discNumber"`
+	TrackNumber *int     `json:"trackNumber"`
+}
+
+// Модель ответа пользователю в случае, если значения полей не прошли проверку
+type errorValidationMessage struct {
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields"` // Описание ошибки по каждому некорректному полю
+}
+
+// Поля песни, которые нельзя изменить
+var readOnlySongFields = []string{"id", "artistId", "version", "deletedAt"}
+
 // UpdateSong godoc
 //	@Summary		UpdateSong
 //	@Tags			song
-//	@Description	Update song on given info
+//	@Description	Rename song (other fields are kept; use PUT /songs/{id} to replace all fields)
 //	@Accept			json
 //	@Produce		json
 //	@Param			group	path		string			true	"Name of group"
-//	@Param			song	path		string			true	"Name of song"
-//	@Param			input	body		requestBodySong	true	"New song info"
-//	@Success		200		{object}	responceMessage
-//	@Failure		400		{object}	responceMessage
-//	@Failure		404		{object}	responceMessage
-//	@Failure		500		{object}	responceMessage
+//	@Param			song		path		string			true	"Name of song"
+//	@Param			If-Match	header		string			false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Param			input		body		requestBodySong	true	"New names of group and song"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	responceMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		409			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
 //	@Router			/song [put]
 
-// Хэндлер для изменения песни
+// Хэндлер для переименования песни (песня ищется по названиям исполнителя и песни, остальные ее данные сохраняются)
 func (a *API) UpdateSong(c *gin.Context) {
 	// Логируем начало выполнение запроса
 	a.logger.Info("User do 'PUT: UpdateSong api/song'")
@@ -50,46 +86,404 @@ func (a *API) UpdateSong(c *gin.Context) {
 	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
 	if err != nil {
 		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
-		c.JSON(http.StatusInternalServerError, serverError)
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
 		return
 	}
+	reqSong.Group, reqSong.Song = strings.TrimSpace(reqSong.Group), strings.TrimSpace(reqSong.Song)
 	if reqSong.Group == "" || reqSong.Song == "" {
 		a.logger.Error("User provide uncorrected JSON: group or song is empty")
 		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: group and song value must be not empty"})
 		return
 	}
 
-	// Логируем обращение к БД
-	a.logger.Debug("Sending a request to DB: CheckSong")
+	// Ищем идентификатор песни в БД
+	id, ok := a.findSongID(c, qSong.Group, qSong.Song, "update")
+	if !ok {
+		return
+	}
 
-	// Ищем песню в БД
-	err = a.storage.Song().CheckSong(qSong.Group, qSong.Song)
-	// Если песня не найдена
-	if err != nil && err == sql.ErrNoRows {
-		a.logger.Info(fmt.Sprintf("User trying to update non existed song. Group: %s, song: %s", qSong.Group, qSong.Song))
-		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to update non existed song"})
+	// Считываем версию песни, которую пользователь собирается переименовать
+	expected, ok := a.expectedVersion(c, id)
+	if !ok {
 		return
 	}
-	if err != nil {
-		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
-		c.JSON(http.StatusInternalServerError, serverError)
+
+	// Получаем текущие данные песни (меняются только названия, остальные поля сохраняются как есть)
+	song, ok := a.getSong(c, id)
+	if !ok {
 		return
 	}
+	if expected != 0 && song.Version != expected {
+		a.preconditionFailed(c, id)
+		return
+	}
+	song.Group, song.Song = reqSong.Group, reqSong.Song
 
-	// Логируем обращение к БД
-	a.logger.Debug("Sending a request to DB: UpdateSong")
+	// Сохраняем, только если песню не изменили, пока она переименовывалась
+	if !a.updateSong(c, song, expected != 0) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Song successfully update. Group: %s, song: %s", qSong.Group, qSong.Song)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'PUT: UpdateSong api/song' successfully done")
+}
+
+// UpdateSongByID godoc
+//	@Summary		UpdateSongByID
+//	@Tags			song
+//	@Description	Replace song by id (absent fields are cleared)
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		integer			true	"Song id"
+//	@Param			If-Match	header		string			false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Param			input		body		requestPutSong	true	"New song info"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	errorValidationMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		409			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/songs/{id} [put]
+
+// Хэндлер для замены песни по идентификатору
+func (a *API) UpdateSongByID(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'PUT: UpdateSongByID api/songs/:id'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Парсим request body
+	song, ok := a.bindRequestPutSong(c)
+	if !ok {
+		return
+	}
+	song.ID = id
+
+	// Считываем версию песни, которую пользователь собирается заменить
+	song.Version, ok = a.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	// Заменяем данные песни
+	if !a.updateSong(c, song, true) {
+		return
+	}
+
+	// Возвращаем пользователю сообщение об успешно выполненной операции
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Song successfully update. ID: %d", id)})
+
+	// Логируем окончание запроса
+	a.logger.Info("Request 'PUT: UpdateSongByID api/songs/:id' successfully done")
+}
+
+// PatchSong godoc
+//	@Summary		PatchSong
+//	@Tags			song
+//	@Description	Partially update song by id (JSON Merge Patch, RFC 7396: absent fields are not changed, null clears the field). Without If-Match 409 is returned if song was changed concurrently
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		integer			true	"Song id"
+//	@Param			If-Match	header		string			false	"ETag of song (required with SONG_REQUIRE_IF_MATCH=true)"
+//	@Param			input		body		requestPutSong	true	"Changed song info"
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200			{object}	responceMessage
+//	@Failure		400			{object}	errorValidationMessage
+//	@Failure		401			{object}	responceMessage
+//	@Failure		404			{object}	responceMessage
+//	@Failure		409			{object}	responceMessage
+//	@Failure		412			{object}	responceMessage
+//	@Failure		428			{object}	responceMessage
+//	@Failure		500			{object}	responceMessage
+//	@Router			/songs/{id} [patch]
+
+// Хэндлер для частичного изменения песни по идентификатору
+func (a *API) PatchSong(c *gin.Context) {
+	// Логируем начало выполнение запроса
+	a.logger.Info("User do 'PATCH: PatchSong api/songs/:id'")
+
+	// Считываем идентификатор песни
+	id, ok := a.bindSongID(c)
+	if !ok {
+		return
+	}
+
+	// Парсим request body (патч должен быть JSON объектом)
+	var patch map[string]any
+	err := c.ShouldBindJSON(&patch)
+	// Проверка request body (удовлетворяет ли оно условиям для данного хэндлера)
+	if err != nil || patch == nil {
+		a.logger.Error(fmt.Sprintf("Trouble with bind request body: %v", err))
+		c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON: merge patch must be a JSON object"})
+		return
+	}
+	fields := make(map[string]string)
+	for _, field := range readOnlySongFields {
+		if _, ok := patch[field]; ok {
+			fields[field] = "field is read only"
+		}
+	}
+	if len(fields) > 0 {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: %v", fields))
+		c.JSON(http.StatusBadRequest, errorValidationMessage{Message: "You provide uncorrected JSON", Fields: fields})
+		return
+	}
+
+	// Считываем версию песни, на которую пользователь рассчитывал патч
+	expected, ok := a.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	// Получаем текущие данные песни (патч не накладывается на версию, отличную от ожидаемой)
+	current, ok := a.getSong(c, id)
+	if !ok {
+		return
+	}
+	if expected != 0 && current.Version != expected {
+		a.preconditionFailed(c, id)
+		return
+	}
 
-	// Если песня найдена, то обновляем ее данные
-	err = a.storage.Song().UpdateSong(reqSong.Group, reqSong.Song, qSong.Group, qSong.Song)
+	// Накладываем патч на текущие данные песни (без полей, которые нельзя изменить)
+	target, err := toJSONObject(current)
 	if err != nil {
-		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		a.logger.Error(fmt.Sprintf("Trouble with encoding song: %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return
+	}
+	for _, field := range readOnlySongFields {
+		delete(target, field)
+	}
+	// Песня, убранная из альбома, теряет и номера диска и трека, если они не заданы в патче явно
+	if albumID, ok := patch["albumId"]; ok && albumID == nil {
+		for _, field := range []string{"discNumber", "trackNumber"} {
+			if _, ok := patch[field]; !ok {
+				delete(target, field)
+			}
+		}
+	}
+	merged, err := json.Marshal(mergePatch(target, patch))
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with encoding song: %s", err))
 		c.JSON(http.StatusInternalServerError, serverError)
 		return
 	}
 
+	// Проверяем получившуюся песню так же, как при полной замене
+	song, ok := a.decodeRequestPutSong(c, merged)
+	if !ok {
+		return
+	}
+	song.ID = id
+	// Сохраняем, только если песню не изменили, пока накладывался патч
+	song.Version = current.Version
+
+	// Обновляем данные песни
+	if !a.updateSong(c, song, expected != 0) {
+		return
+	}
+
 	// Возвращаем пользователю сообщение об успешно выполненной операции
-	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Song successfully update. Group: %s, song: %s", qSong.Group, qSong.Song)})
+	c.JSON(http.StatusOK, responceMessage{fmt.Sprintf("Song successfully update. ID: %d", id)})
 
 	// Логируем окончание запроса
-	a.logger.Info("Request 'PUT: UpdateSong api/song' successfully done")
+	a.logger.Info("Request 'PATCH: PatchSong api/songs/:id' successfully done")
+}
+
+// Метод, считывающий и проверяющий request body со всей информацией о песне (в случае ошибки сам отвечает пользователю)
+func (a *API) bindRequestPutSong(c *gin.Context) (*models.Song, bool) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		a.logger.Error(fmt.Sprintf("Trouble with reading request body: %s", err))
+		c.JSON(http.StatusInternalServerError, serverError)
+		return nil, false
+	}
+
+	return a.decodeRequestPutSong(c, body)
+}
+
+// Метод, разбирающий JSON со всей информацией о песне и проверяющий каждое поле (в случае ошибки сам отвечает пользователю)
+func (a *API) decodeRequestPutSong(c *gin.Context, body []byte) (*models.Song, bool) {
+	var reqSong requestPutSong
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&reqSong)
+	if err != nil {
+		fields := jsonFieldErrors(err)
+		if fields == nil {
+			a.logger.Error(fmt.Sprintf("Trouble with bind request body: %s", err))
+			c.JSON(http.StatusBadRequest, errorMessage{"You provide uncorrected JSON"})
+			return nil, false
+		}
+		a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: %v", fields))
+		c.JSON(http.StatusBadRequest, errorValidationMessage{Message: "You provide uncorrected JSON", Fields: fields})
+		return nil, false
+	}
+
+	song, fields := validateSong(reqSong)
+	if len(fields) > 0 {
+		a.logger.Error(fmt.Sprintf("User provide uncorrected JSON: %v", fields))
+		c.JSON(http.StatusBadRequest, errorValidationMessage{Message: "You provide uncorrected JSON", Fields: fields})
+		return nil, false
+	}
+
+	return song, true
+}
+
+// Функция, показывающая ошибку типа или неизвестное поле как ошибку конкретного поля (nil, если ошибка не относится к полю)
+func jsonFieldErrors(err error) map[string]string {
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		return map[string]string{typeErr.Field: fmt.Sprintf("value must be %s", typeErr.Type)}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return map[string]string{strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`): "unknown field"}
+	default:
+		return nil
+	}
+}
+
+// Функция, проверяющая каждое поле песни и возвращающая песню вместе с ошибками по полям
+func validateSong(reqSong requestPutSong) (*models.Song, map[string]string) {
+	fields := make(map[string]string)
+
+	// Пробелы по краям названий не должны порождать отдельных исполнителей и песен ("Muse " и "Muse")
+	song := &models.Song{
+		Group:       strings.TrimSpace(reqSong.Group),
+		Song:        strings.TrimSpace(reqSong.Song),
+		ReleaseDate: strings.TrimSpace(reqSong.ReleaseDate),
+		Text:        reqSong.Text,
+		Link:        strings.TrimSpace(reqSong.Link),
+		AlbumID:     reqSong.AlbumID,
+		DiscNumber:  reqSong.DiscNumber,
+		TrackNumber: reqSong.TrackNumber,
+	}
+	if song.Text == nil {
+		song.Text = []string{}
+	}
+
+	if song.Group == "" {
+		fields["group"] = "value must be not empty"
+	}
+	if song.Song == "" {
+		fields["song"] = "value must be not empty"
+	}
+	if song.ReleaseDate != "" {
+		if _, _, err := models.ParseReleaseDate(song.ReleaseDate); err != nil {
+			fields["releaseDate"] = "value must be a date in dd.mm.yyyy, mm.yyyy or yyyy format"
+		}
+	}
+	if song.Link != "" {
+		link, err := url.Parse(song.Link)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			fields["link"] = "value must be an absolute http or https url"
+		}
+	}
+
+	// Номера диска и трека имеют смысл только вместе с альбомом (номер диска по умолчанию равен 1)
+	if song.AlbumID == nil {
+		if song.DiscNumber != nil {
+			fields["discNumber"] = "value must be null when albumId is null"
+		}
+		if song.TrackNumber != nil {
+			fields["trackNumber"] = "value must be null when albumId is null"
+		}
+		return song, fields
+	}
+	if *song.AlbumID <= 0 {
+		fields["albumId"] = "value must be a positive number"
+	}
+	if song.DiscNumber == nil {
+		disc := 1
+		song.DiscNumber = &disc
+	} else if *song.DiscNumber <= 0 {
+		fields["discNumber"] = "value must be a positive number"
+	}
+	if song.TrackNumber == nil || *song.TrackNumber <= 0 {
+		fields["trackNumber"] = "value must be a positive number when albumId is set"
+	}
+
+	return song, fields
+}
+
+// Функция, преобразующая значение в JSON объект (для наложения merge patch)
+func toJSONObject(value any) (map[string]any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]any
+	err = json.Unmarshal(data, &object)
+	return object, err
+}
+
+// Функция, накладывающая JSON Merge Patch (RFC 7396) на объект: null удаляет поле, вложенные объекты объединяются, остальные значения заменяются
+func mergePatch(target, patch map[string]any) map[string]any {
+	if target == nil {
+		target = make(map[string]any)
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		if object, ok := value.(map[string]any); ok {
+			nested, _ := target[key].(map[string]any)
+			target[key] = mergePatch(nested, object)
+			continue
+		}
+		target[key] = value
+	}
+
+	return target
+}
+
+// Метод, сохраняющий все поля песни в БД, если ее версия равна song.Version, и возвращающий новый ETag (conditional - версию задал пользователь через If-Match; в случае ошибки сам отвечает пользователю)
+func (a *API) updateSong(c *gin.Context, song *models.Song, conditional bool) bool {
+	// Логируем обращение к БД
+	a.logger.Debug("Sending a request to DB: UpdateSong")
+
+	err := a.songStore(c).UpdateSong(song)
+	switch {
+	case err == nil:
+		c.Header("ETag", songETag(song.Version))
+		return true
+	// Если песня не найдена
+	case err == sql.ErrNoRows:
+		a.logger.Info(fmt.Sprintf("User trying to update non existed song. ID: %d", song.ID))
+		c.JSON(http.StatusNotFound, errorNotFoundMessage{"You trying to update non existed song"})
+	// Если песню успели изменить (без If-Match версию проверял сам сервер, поэтому это конфликт, а не проваленное условие)
+	case err == storage.ErrStaleVersion && conditional:
+		a.preconditionFailed(c, song.ID)
+	case err == storage.ErrStaleVersion:
+		a.logger.Info(fmt.Sprintf("Song was changed concurrently with update. ID: %d", song.ID))
+		c.JSON(http.StatusConflict, errorMessage{"Song was changed concurrently by another request: try again"})
+	case err == storage.ErrBrokenReference:
+		a.logger.Info(fmt.Sprintf("User trying to attach song to non existed album. ID: %d", song.ID))
+		c.JSON(http.StatusBadRequest, errorValidationMessage{Message: "You provide uncorrected JSON", Fields: map[string]string{"albumId": "album does not exist"}})
+	case err == storage.ErrAlreadyExists:
+		a.logger.Info(fmt.Sprintf("User trying to rename song to existed one or put it on occupied album track. ID: %d", song.ID))
+		c.JSON(http.StatusConflict, errorMessage{"Library already has such song or album already has a song with such disc and track number"})
+	default:
+		a.logger.Error(fmt.Sprintf("Trouble with connecting to DB (table %s): %s", os.Getenv("TABLE_NAME"), err))
+		c.JSON(http.StatusInternalServerError, serverError)
+	}
+
+	return false
 }
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/json"
+	"mus_lib/internal/app/models"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// Функция, добавляющая песню через API (информация о ней берется из фейкового провайдера) и возвращающая ее идентификатор
+func addSongWithInfo(t *testing.T, a *API, token, group, song string) int64 {
+	t.Helper()
+
+	w := serve(a, http.MethodPost, "/api/song", `{"group":"`+group+`","song":"`+song+`"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add song status = %d: %s", w.Code, w.Body)
+	}
+
+	var added responceAddSong
+	decode(t, w, &added)
+	return added.ID
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		patch  string
+		want   string
+	}{
+		{"replace value", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add value", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null removes field", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"null for missing field", `{"a":"b"}`, `{"c":null}`, `{"a":"b"}`},
+		{"array is replaced", `{"a":["b","c"]}`, `{"a":["d"]}`, `{"a":["d"]}`},
+		{"nested objects are merged", `{"a":{"b":"c","d":"e"}}`, `{"a":{"b":"f","d":null}}`, `{"a":{"b":"f"}}`},
+		{"object replaces value", `{"a":"b"}`, `{"a":{"c":"d"}}`, `{"a":{"c":"d"}}`},
+		{"empty patch", `{"a":"b"}`, `{}`, `{"a":"b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target, patch, want map[string]any
+			for _, v := range []struct {
+				data  string
+				value *map[string]any
+			}{{tt.target, &target}, {tt.patch, &patch}, {tt.want, &want}} {
+				if err := json.Unmarshal([]byte(v.data), v.value); err != nil {
+					t.Fatalf("json.Unmarshal(%s) error = %v", v.data, err)
+				}
+			}
+
+			if got := mergePatch(target, patch); !reflect.DeepEqual(got, want) {
+				t.Errorf("mergePatch() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPatchSong(t *testing.T) {
+	tests := []struct {
+		name       string
+		patch      string
+		wantStatus int
+		check      func(t *testing.T, song *models.Song)
+	}{
+		{
+			name:       "null removes link",
+			patch:      `{"link":null}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, song *models.Song) {
+				if song.Link != "" || song.ReleaseDate != "01.01.1990" {
+					t.Errorf("song = %+v, want empty link and unchanged release date", song)
+				}
+			},
+		},
+		{
+			name:       "change release date and text",
+			patch:      `{"releaseDate":"1991","text":["one"]}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, song *models.Song) {
+				if song.ReleaseDate != "1991" || !reflect.DeepEqual(song.Text, []string{"one"}) {
+					t.Errorf("song = %+v, want release date 1991 and one verse", song)
+				}
+			},
+		},
+		{name: "read only field", patch: `{"id":5}`, wantStatus: http.StatusBadRequest},
+		{name: "not an object", patch: `["link"]`, wantStatus: http.StatusBadRequest},
+		{name: "uncorrected release date", patch: `{"releaseDate":"someday"}`, wantStatus: http.StatusBadRequest},
+		{name: "track without album", patch: `{"trackNumber":1}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAPI(t)
+			token := loginTestUser(t, a, "alice")
+			id := addSongWithInfo(t, a, token, "Muse", "Uprising")
+
+			w := serve(a, http.MethodPatch, songPath(id), tt.patch, "Authorization", bearer(token))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.check != nil {
+				tt.check(t, getTestSong(t, a, id))
+			}
+		})
+	}
+}
+
+func TestUpdateSongByIDValidation(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addSongWithInfo(t, a, token, "Muse", "Uprising")
+
+	// Каждое некорректное поле описывается отдельно
+	w := serve(a, http.MethodPut, songPath(id), `{"group":"","song":"Uprising","releaseDate":"someday","link":"example.com","unknown":1}`, "Authorization", bearer(token))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body)
+	}
+	var invalid errorValidationMessage
+	decode(t, w, &invalid)
+	if _, ok := invalid.Fields["unknown"]; !ok {
+		t.Errorf("fields = %v, want unknown field error", invalid.Fields)
+	}
+
+	w = serve(a, http.MethodPut, songPath(id), `{"group":"","song":"Uprising","releaseDate":"someday","link":"example.com"}`, "Authorization", bearer(token))
+	invalid = errorValidationMessage{}
+	decode(t, w, &invalid)
+	for _, field := range []string{"group", "releaseDate", "link"} {
+		if _, ok := invalid.Fields[field]; !ok {
+			t.Errorf("fields = %v, want %s error", invalid.Fields, field)
+		}
+	}
+
+	if w := serve(a, http.MethodPut, songPath(id), `{"group":"Muse","song":"Uprising","albumId":100,"trackNumber":1}`, "Authorization", bearer(token)); w.Code != http.StatusBadRequest {
+		t.Errorf("missing album status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// Поля, которых нет в теле запроса, очищаются
+	if w := serve(a, http.MethodPut, songPath(id), `{"group":"Muse","song":"Uprising","link":"https://example.com"}`, "Authorization", bearer(token)); w.Code != http.StatusOK {
+		t.Fatalf("put status = %d: %s", w.Code, w.Body)
+	}
+	if song := getTestSong(t, a, id); song.ReleaseDate != "" || len(song.Text) != 0 || song.Link != "https://example.com" {
+		t.Errorf("song = %+v, want only link kept", song)
+	}
+}
+
+func TestLegacyUpdateSongRenamesOnly(t *testing.T) {
+	a := newTestAPI(t)
+	token := loginTestUser(t, a, "alice")
+	id := addSongWithInfo(t, a, token, "Muse", "Uprising")
+
+	w := serve(a, http.MethodPut, "/api/song?group=muse&song=uprising", `{"group":"Muse","song":"Uprising (Live)"}`, "Authorization", bearer(token))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	song := getTestSong(t, a, id)
+	if song.Song != "Uprising (Live)" || song.ReleaseDate != "01.01.1990" || len(song.Text) != 3 {
+		t.Errorf("song = %+v, want renamed song with the same info", song)
+	}
+}
+
+func TestUpdateSongIfMatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		ifMatch        string
+		requireIfMatch bool
+		wantStatus     int
+		wantETag       string
+	}{
+		{"without If-Match", "", false, http.StatusOK, `"2"`},
+		{"actual version", `"1"`, false, http.StatusOK, `"2"`},
+		{"any version", `*`, true, http.StatusOK, `"2"`},
+		{"stale version", `"2"`, false, http.StatusPreconditionFailed, ""},
+		{"weak tag", `W/"1"`, false, http.StatusPreconditionFailed, ""},
+		{"required If-Match", "", true, http.StatusPreconditionRequired, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAPI(t)
+			token := loginTestUser(t, a, "alice")
+			a.requireIfMatch = tt.requireIfMatch
+			id := addSongWithInfo(t, a, token, "Muse", "Uprising")
+
+			body := `{"group":"Muse","song":"Uprising","releaseDate":"2009","text":["one"],"link":""}`
+			w := serve(a, http.MethodPut, songPath(id), body, "If-Match", tt.ifMatch, "Authorization", bearer(token))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if etag := w.Header().Get("ETag"); etag != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", etag, tt.wantETag)
+			}
+
+			// Отклоненное изменение не должно затронуть песню
+			wantDate := "01.01.1990"
+			if tt.wantStatus == http.StatusOK {
+				wantDate = "2009"
+			}
+			if song := getTestSong(t, a, id); song.ReleaseDate != wantDate {
+				t.Errorf("releaseDate = %v, want %v", song.ReleaseDate, wantDate)
+			}
+		})
+	}
+}
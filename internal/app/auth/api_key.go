@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// Префикс API-ключей (позволяет отличить ключ от JWT и найти случайно опубликованный ключ)
+const apiKeyPrefix = "ml_"
+
+// Сколько символов ключа после префикса показывается в списке ключей
+const apiKeyVisibleLength = 8
+
+// Функция, генерирующая API-ключ и возвращающая его вместе с видимым началом и хэшем, который сохраняется в хранилище
+func NewAPIKey() (key, prefix, hash string, err error) {
+	random := make([]byte, 32)
+	_, err = rand.Read(random)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+	return key, key[:len(apiKeyPrefix)+apiKeyVisibleLength], HashAPIKey(key), nil
+}
+
+// Функция, возвращающая хэш API-ключа (у ключа достаточно случайных байт, поэтому медленный хэш, как у паролей, не нужен)
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Функция, проверяющая, похожа ли строка на API-ключ
+func IsAPIKey(value string) bool {
+	return strings.HasPrefix(value, apiKeyPrefix)
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key, prefix, hash, err := NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+
+	if !IsAPIKey(key) {
+		t.Errorf("key %q is not recognized as API key", key)
+	}
+	if !strings.HasPrefix(key, prefix) || len(prefix) != len(apiKeyPrefix)+apiKeyVisibleLength {
+		t.Errorf("prefix = %q, want first %d characters of key %q", prefix, len(apiKeyPrefix)+apiKeyVisibleLength, key)
+	}
+	if hash != HashAPIKey(key) || strings.Contains(hash, key) {
+		t.Errorf("hash = %q, want hash of key", hash)
+	}
+
+	other, _, _, err := NewAPIKey()
+	if err != nil || other == key {
+		t.Errorf("NewAPIKey() returned the same key twice")
+	}
+}
+
+func TestIsAPIKey(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"ml_abc", true},
+		{"eyJhbGciOiJIUzI1NiJ9.e30.sig", false},
+		{"ML_abc", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsAPIKey(tt.value); got != tt.want {
+			t.Errorf("IsAPIKey(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token") // Токен поврежден, подписан другим ключом или другим алгоритмом
+	ErrTokenExpired = errors.New("token expired") // Срок действия токена истек
+)
+
+// Время жизни токена по умолчанию
+const defaultTokenTTL = 24 * time.Hour
+
+// Минимальная длина секрета подписи токенов (HMAC-SHA256 не стоит подписывать ключом короче хэша)
+const minSecretLength = 32
+
+// Настройки выдачи токенов
+type Config struct {
+	Secret []byte        // Секрет, которым подписываются токены (если пустой, генерируется при запуске)
+	TTL    time.Duration // Время жизни токена
+}
+
+// Функция, считывающая настройки выдачи токенов из переменных окружения
+func ConfigFromEnv() (Config, error) {
+	config := Config{Secret: []byte(os.Getenv("JWT_SECRET")), TTL: defaultTokenTTL}
+
+	if len(config.Secret) > 0 && len(config.Secret) < minSecretLength {
+		return config, fmt.Errorf("uncorrected JWT_SECRET: must be at least %d bytes", minSecretLength)
+	}
+
+	if value := os.Getenv("JWT_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return config, fmt.Errorf("uncorrected JWT_TTL %q: must be a positive duration (e.g. 24h)", value)
+		}
+		config.TTL = ttl
+	}
+
+	return config, nil
+}
+
+// Функция, генерирующая случайный секрет подписи (токены, подписанные им, перестают действовать после перезапуска)
+func RandomSecret() ([]byte, error) {
+	secret := make([]byte, minSecretLength)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     string
+		ttl        string
+		wantTTL    time.Duration
+		wantSecret string
+		wantErr    bool
+	}{
+		{"defaults", "", "", defaultTokenTTL, "", false},
+		{"secret and ttl", "0123456789abcdef0123456789abcdef", "2h", 2 * time.Hour, "0123456789abcdef0123456789abcdef", false},
+		{"short secret", "short", "", 0, "", true},
+		{"uncorrected ttl", "", "tomorrow", 0, "", true},
+		{"negative ttl", "", "-1h", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", tt.secret)
+			t.Setenv("JWT_TTL", tt.ttl)
+
+			config, err := ConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConfigFromEnv() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && (config.TTL != tt.wantTTL || string(config.Secret) != tt.wantSecret) {
+				t.Errorf("ConfigFromEnv() = %+v, want ttl %v and secret %q", config, tt.wantTTL, tt.wantSecret)
+			}
+		})
+	}
+}
+
+func TestRandomSecret(t *testing.T) {
+	secret, err := RandomSecret()
+	if err != nil || len(secret) < minSecretLength {
+		t.Fatalf("RandomSecret() = %d bytes, %v; want at least %d bytes", len(secret), err, minSecretLength)
+	}
+}
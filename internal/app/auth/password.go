@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Границы длины пароля (bcrypt учитывает только первые 72 байта)
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// Хэш, с которым сравнивается пароль, если пользователь не найден (чтобы по времени ответа нельзя было узнать, есть ли пользователь)
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// Функция, возвращающая bcrypt хэш пароля
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Функция, проверяющая пароль по хэшу (пустой хэш - пользователь не найден, пароль проверяется с той же задержкой и не подходит)
+func CheckPassword(hash, password string) (bool, error) {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return false, nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
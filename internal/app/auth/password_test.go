@@ -0,0 +1,32 @@
+package auth
+
+import "testing"
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("secretpass")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+		wantErr  bool
+	}{
+		{"right password", hash, "secretpass", true, false},
+		{"wrong password", hash, "secretpas", false, false},
+		{"user not found", "", "secretpass", false, false},
+		{"broken hash", "not a bcrypt hash", "secretpass", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckPassword(tt.hash, tt.password)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("CheckPassword() = %v, %v; want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
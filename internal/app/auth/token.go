@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовок JWT (поддерживается только HMAC-SHA256)
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+// Заголовок, с которым выдаются все токены (закодирован заранее)
+var encodedHeader = encodeSegment(tokenHeader{Algorithm: "HS256", Type: "JWT"})
+
+// Данные, которые хранит токен
+type Claims struct {
+	Subject   string `json:"sub"`  // Идентификатор пользователя
+	Name      string `json:"name"` // Имя пользователя
+	IssuedAt  int64  `json:"iat"`  // Время выдачи (Unix)
+	ExpiresAt int64  `json:"exp"`  // Время окончания действия (Unix)
+}
+
+// Метод, возвращающий идентификатор пользователя из токена
+func (claims *Claims) UserID() (int64, error) {
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidToken
+	}
+
+	return id, nil
+}
+
+// Выдает и проверяет токены (JWT, подписанные HMAC-SHA256)
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// Конструктор, возвращающий выдачу токенов с заданными настройками
+func NewTokens(config Config) *Tokens {
+	return &Tokens{secret: config.Secret, ttl: config.TTL, now: time.Now}
+}
+
+// Метод, выдающий токен пользователю и возвращающий его вместе с временем окончания действия
+func (t *Tokens) Issue(userID int64, name string) (string, time.Time) {
+	now := t.now()
+	expiresAt := now.Add(t.ttl)
+
+	claims := Claims{
+		Subject:   strconv.FormatInt(userID, 10),
+		Name:      name,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}
+	unsigned := encodedHeader + "." + encodeSegment(claims)
+
+	return unsigned + "." + t.sign(unsigned), expiresAt
+}
+
+// Метод, проверяющий подпись и срок действия токена и возвращающий его данные (ErrInvalidToken или ErrTokenExpired)
+func (t *Tokens) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	// Алгоритм берется не из заголовка, а проверяется на совпадение (иначе можно прислать токен с alg=none)
+	var header tokenHeader
+	if decodeSegment(parts[0], &header) != nil || header.Algorithm != "HS256" {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	expected, _ := base64.RawURLEncoding.DecodeString(t.sign(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if decodeSegment(parts[1], &claims) != nil {
+		return nil, ErrInvalidToken
+	}
+	if t.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+// Метод, возвращающий подпись части токена до последней точки
+func (t *Tokens) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(unsigned))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Функция, кодирующая часть токена (JSON в base64url без выравнивания)
+func encodeSegment(value any) string {
+	data, _ := json.Marshal(value)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Функция, декодирующая часть токена
+func decodeSegment(segment string, value any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, value)
+}
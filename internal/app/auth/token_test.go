@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Секрет подписи для тестов
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// Функция, возвращающая выдачу токенов с часами, которые показывают *now
+func newTestTokens(now *time.Time) *Tokens {
+	tokens := NewTokens(Config{Secret: testSecret, TTL: time.Hour})
+	tokens.now = func() time.Time { return *now }
+	return tokens
+}
+
+func TestTokensIssueAndParse(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tokens := newTestTokens(&now)
+
+	token, expiresAt := tokens.Issue(42, "alice")
+	if !expiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expiresAt = %v, want %v", expiresAt, now.Add(time.Hour))
+	}
+
+	claims, err := tokens.Parse(token)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	id, err := claims.UserID()
+	if err != nil || id != 42 || claims.Name != "alice" {
+		t.Errorf("claims = %+v, UserID() = %d, %v; want user 42 alice", claims, id, err)
+	}
+}
+
+func TestTokensParseRejects(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tokens := newTestTokens(&now)
+	token, _ := tokens.Issue(42, "alice")
+	parts := strings.Split(token, ".")
+
+	other := newTestTokens(&now)
+	other.secret = []byte("another secret of at least 32 bytes")
+	foreign, _ := other.Issue(42, "alice")
+
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	forgedClaims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","name":"admin","iat":1700000000,"exp":1800000000}`))
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"empty", "", ErrInvalidToken},
+		{"not three parts", parts[0] + "." + parts[1], ErrInvalidToken},
+		{"other secret", foreign, ErrInvalidToken},
+		{"changed claims", parts[0] + "." + forgedClaims + "." + parts[2], ErrInvalidToken},
+		{"alg none", noneHeader + "." + parts[1] + ".", ErrInvalidToken},
+		{"broken signature", parts[0] + "." + parts[1] + ".!!!", ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tokens.Parse(tt.token)
+			if err != tt.want {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokensParseExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tokens := newTestTokens(&now)
+	token, _ := tokens.Issue(42, "alice")
+
+	now = now.Add(time.Hour - time.Second)
+	if _, err := tokens.Parse(token); err != nil {
+		t.Fatalf("Parse() before expiration error = %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if _, err := tokens.Parse(token); err != ErrTokenExpired {
+		t.Errorf("Parse() after expiration error = %v, want %v", err, ErrTokenExpired)
+	}
+}
+
+func TestClaimsUserID(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    int64
+		wantErr bool
+	}{
+		{"1", 1, false},
+		{"0", 0, true},
+		{"-5", 0, true},
+		{"alice", 0, true},
+	}
+
+	for _, tt := range tests {
+		claims := Claims{Subject: tt.subject}
+		got, err := claims.UserID()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("UserID() for subject %q = %d, %v; want %d, error %v", tt.subject, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
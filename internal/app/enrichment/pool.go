@@ -0,0 +1,232 @@
+package enrichment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"mus_lib/internal/app/models"
+	"mus_lib/internal/app/songinfo"
+	"mus_lib/storage"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Время, на которое воркер арендует задание (если воркер упал, задание забирает другой воркер после окончания аренды)
+const jobLease = 5 * time.Minute
+
+// Автор, от имени которого изменения воркеров записываются в историю изменений песен
+const workerAuthor = "enrichment"
+
+// Сколько раз воркер пробует сохранить информацию о песне, которую одновременно с ним меняют пользователи
+const staleRetries = 3
+
+// Метрики обработки заданий (доступны по /debug/vars в разделе enrichment)
+var metrics = expvar.NewMap("enrichment")
+
+// Настройки пула воркеров
+type Config struct {
+	Workers       int           // Количество воркеров
+	PollInterval  time.Duration // Как часто свободный воркер проверяет очередь
+	MaxAttempts   int           // Количество попыток, после которого задание переводится в dead
+	RetryDelay    time.Duration // Задержка перед первой повторной попыткой (дальше удваивается)
+	MaxRetryDelay time.Duration // Максимальная задержка перед повторной попыткой
+}
+
+// Настройки пула воркеров по умолчанию
+var DefaultConfig = Config{Workers: 2, PollInterval: time.Second, MaxAttempts: 5, RetryDelay: 30 * time.Second, MaxRetryDelay: time.Hour}
+
+// Функция, считывающая настройки пула воркеров из переменных окружения
+func ConfigFromEnv() (Config, error) {
+	config := DefaultConfig
+
+	numbers := []struct {
+		env   string
+		value *int
+	}{
+		{"ENRICHMENT_WORKERS", &config.Workers},
+		{"ENRICHMENT_MAX_ATTEMPTS", &config.MaxAttempts},
+	}
+	for _, n := range numbers {
+		value := os.Getenv(n.env)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number <= 0 {
+			return config, fmt.Errorf("uncorrected %s %q: must be a positive number", n.env, value)
+		}
+		*n.value = number
+	}
+
+	durations := []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"ENRICHMENT_POLL_INTERVAL", &config.PollInterval},
+		{"ENRICHMENT_RETRY_DELAY", &config.RetryDelay},
+		{"ENRICHMENT_MAX_RETRY_DELAY", &config.MaxRetryDelay},
+	}
+	for _, d := range durations {
+		value := os.Getenv(d.env)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return config, fmt.Errorf("uncorrected %s %q: must be a positive duration, for example 30s", d.env, value)
+		}
+		*d.value = duration
+	}
+
+	return config, nil
+}
+
+// Пул воркеров, заполняющих информацию о песнях, добавленных асинхронно
+type Pool struct {
+	store    storage.Store
+	provider songinfo.Provider
+	logger   *slog.Logger
+	config   Config
+}
+
+// Конструктор, возвращающий пул воркеров (воркеры запускаются методом Start)
+func NewPool(store storage.Store, provider songinfo.Provider, logger *slog.Logger, config Config) *Pool {
+	return &Pool{store: store, provider: provider, logger: logger, config: config}
+}
+
+// Метод, запускающий воркеров (они работают, пока не будет отменен контекст)
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.config.Workers; i++ {
+		go p.work(ctx)
+	}
+}
+
+// Метод, в котором воркер забирает задания из очереди, пока не будет отменен контекст
+func (p *Pool) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := p.store.Job().ClaimJob(jobLease)
+		if err == nil {
+			p.process(ctx, job)
+			continue
+		}
+		if err != sql.ErrNoRows {
+			p.logger.Error(fmt.Sprintf("Enrichment worker failed to claim job: %s", err))
+		}
+
+		// Очередь пуста (или недоступна), ждем перед следующей проверкой
+		select {
+		case <-ctx.Done():
+		case <-time.After(p.config.PollInterval):
+		}
+	}
+}
+
+// Метод, выполняющий задание: запрашивает информацию о песне у стороннего API и сохраняет ее
+func (p *Pool) process(ctx context.Context, job *models.Job) {
+	song, err := p.store.Song().GetSong(job.SongID)
+	if err == sql.ErrNoRows {
+		p.skipDeleted(job)
+		return
+	}
+	if err != nil {
+		p.fail(job, err, false)
+		return
+	}
+
+	detail, err := p.provider.GetSongInfo(ctx, song.Group, song.Song)
+	if err != nil {
+		// Песню, которую сторонний API не знает, нет смысла запрашивать повторно
+		p.fail(job, err, errors.Is(err, songinfo.ErrSongNotFound))
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		// Заполняются только пустые поля (песня, добавленная импортом, могла прийти с частью информации), а версия прочитанной песни остается, чтобы проверить, что ее не изменили
+		err = detail.FillMissing(song)
+		if err != nil && attempt == 1 {
+			p.logger.Warn(fmt.Sprintf("External API returned uncorrected release date for song %d, it will be stored empty: %s", song.ID, err))
+		}
+
+		// Информация сохраняется, только если песню не изменили, пока воркер ждал ответа API (иначе правка пользователя потерялась бы)
+		err = p.store.Job().WithAuthor(workerAuthor).CompleteJob(job, song)
+		if !errors.Is(err, storage.ErrStaleVersion) || attempt == staleRetries {
+			break
+		}
+
+		// Песню изменили: перечитываем ее и заполняем пустые поля заново
+		p.logger.Debug(fmt.Sprintf("Enrichment job %d: song %d was changed concurrently, reading it again", job.ID, song.ID))
+		song, err = p.store.Song().GetSong(job.SongID)
+		if err != nil {
+			break
+		}
+	}
+	if err == sql.ErrNoRows {
+		// Песню удалили, пока воркер ждал ответа API
+		p.skipDeleted(job)
+		return
+	}
+	if errors.Is(err, storage.ErrLeaseLost) {
+		p.saveFailed(job, err)
+		return
+	}
+	if err != nil {
+		// В том числе если песню все время меняют: задание повторится позже
+		p.fail(job, err, false)
+		return
+	}
+
+	metrics.Add("done", 1)
+	p.logger.Info(fmt.Sprintf("Enrichment job %d done: song %d info filled", job.ID, song.ID))
+}
+
+// Метод, пропускающий задание песни, которую удалили в корзину (задание удалится вместе с ней, а если песню вернут, его можно повторить)
+func (p *Pool) skipDeleted(job *models.Job) {
+	p.logger.Info(fmt.Sprintf("Enrichment job %d skipped: song %d was deleted", job.ID, job.SongID))
+	err := p.store.Job().BuryJob(job, "song was deleted")
+	if err != nil {
+		p.logger.Error(fmt.Sprintf("Enrichment worker failed to save job %d state: %s", job.ID, err))
+	}
+}
+
+// Метод, обрабатывающий неудачную попытку: задание возвращается в очередь с увеличенной задержкой или переводится в dead
+func (p *Pool) fail(job *models.Job, jobErr error, permanent bool) {
+	var err error
+	if permanent || job.Attempts >= p.config.MaxAttempts {
+		metrics.Add("dead", 1)
+		p.logger.Error(fmt.Sprintf("Enrichment job %d is dead after %d attempts: %s", job.ID, job.Attempts, jobErr))
+		err = p.store.Job().BuryJob(job, jobErr.Error())
+	} else {
+		metrics.Add("retried", 1)
+		runAt := time.Now().Add(p.retryDelay(job.Attempts))
+		p.logger.Warn(fmt.Sprintf("Enrichment job %d failed (attempt %d), next attempt at %s: %s", job.ID, job.Attempts, runAt.Format(time.RFC3339), jobErr))
+		err = p.store.Job().RescheduleJob(job, jobErr.Error(), runAt)
+	}
+	if err != nil {
+		p.saveFailed(job, err)
+	}
+}
+
+// Метод, логирующий ошибку сохранения состояния задания (потерянная аренда - не ошибка: задание уже выполняет другой воркер, и результат этого воркера просто отбрасывается)
+func (p *Pool) saveFailed(job *models.Job, err error) {
+	if errors.Is(err, storage.ErrLeaseLost) {
+		metrics.Add("lease_lost", 1)
+		p.logger.Warn(fmt.Sprintf("Enrichment job %d lease was lost (attempt %d): the job was taken by another worker", job.ID, job.Attempts))
+		return
+	}
+
+	p.logger.Error(fmt.Sprintf("Enrichment worker failed to save job %d state: %s", job.ID, err))
+}
+
+// Метод, возвращающий задержку перед следующей попыткой (удваивается с каждой попыткой)
+func (p *Pool) retryDelay(attempts int) time.Duration {
+	delay := p.config.RetryDelay << max(attempts-1, 0)
+	if delay <= 0 || delay > p.config.MaxRetryDelay {
+		return p.config.MaxRetryDelay
+	}
+
+	return delay
+}
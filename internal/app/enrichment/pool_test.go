@@ -0,0 +1,240 @@
+package enrichment
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"mus_lib/internal/app/models"
+	"mus_lib/internal/app/songinfo"
+	"mus_lib/storage"
+	"testing"
+	"time"
+)
+
+// Источник информации о песнях, перед ответом вызывающий edit (имитирует действия пользователя, пока воркер ждет сторонний API)
+type editingProvider struct {
+	songinfo.Provider
+	edit func()
+}
+
+func (p *editingProvider) GetSongInfo(ctx context.Context, group, song string) (*songinfo.SongDetail, error) {
+	if p.edit != nil {
+		p.edit()
+	}
+
+	return p.Provider.GetSongInfo(ctx, group, song)
+}
+
+// Функция, возвращающая пул без воркеров, который не пишет логи
+func newTestPool(store storage.Store, provider songinfo.Provider) *Pool {
+	return NewPool(store, provider, slog.New(slog.NewTextHandler(io.Discard, nil)), DefaultConfig)
+}
+
+// Функция, добавляющая песню с заданием и возвращающая задание, забранное воркером на lease
+func enqueueAndClaim(t *testing.T, store storage.Store, lease time.Duration) *models.Job {
+	t.Helper()
+
+	_, err := store.Job().EnqueueSong(&models.Song{Group: "Muse", Song: "Uprising", Text: []string{}})
+	if err != nil {
+		t.Fatalf("EnqueueSong() error = %v", err)
+	}
+	job, err := store.Job().ClaimJob(lease)
+	if err != nil {
+		t.Fatalf("ClaimJob() error = %v", err)
+	}
+
+	return job
+}
+
+func TestProcess(t *testing.T) {
+	store := storage.NewMemory()
+	job := enqueueAndClaim(t, store, time.Minute)
+
+	newTestPool(store, songinfo.NewFake()).process(context.Background(), job)
+
+	stored, err := store.Job().GetJob(job.ID)
+	if err != nil || stored.Status != models.JobDone {
+		t.Fatalf("job = %+v, %v; want status %s", stored, err, models.JobDone)
+	}
+	song, err := store.Song().GetSong(job.SongID)
+	if err != nil || song.ReleaseDate != "01.01.1990" || len(song.Text) != 3 || song.Link != songinfo.DefaultFakeDetail.Link {
+		t.Errorf("song = %+v, %v; want filled info", song, err)
+	}
+
+	// Изменение воркера записывается в историю от его имени
+	revisions, err := store.Revision().GetRevisions(job.SongID, 0, 1)
+	if err != nil || len(revisions) != 1 || revisions[0].Author != workerAuthor {
+		t.Errorf("GetRevisions() = %v, %v; want revision of %s", revisions, err, workerAuthor)
+	}
+}
+
+func TestProcessSongNotFound(t *testing.T) {
+	store := storage.NewMemory()
+	job := enqueueAndClaim(t, store, time.Minute)
+
+	// Песню, которую сторонний API не знает, нет смысла запрашивать повторно
+	fake := songinfo.NewFake()
+	fake.Default = nil
+	newTestPool(store, fake).process(context.Background(), job)
+
+	stored, err := store.Job().GetJob(job.ID)
+	if err != nil || stored.Status != models.JobDead {
+		t.Errorf("job = %+v, %v; want status %s", stored, err, models.JobDead)
+	}
+}
+
+func TestProcessKeepsConcurrentEdit(t *testing.T) {
+	tests := []struct {
+		name       string
+		edits      int // Сколько раз пользователь меняет песню, пока воркер ждет ответа
+		wantStatus models.JobStatus
+		wantLink   string
+	}{
+		{"without edits", 0, models.JobDone, songinfo.DefaultFakeDetail.Link},
+		{"edit during request", 1, models.JobDone, "https://example.com/user"},
+		{"song keeps changing", staleRetries + 1, models.JobPending, "https://example.com/user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemory()
+			job := enqueueAndClaim(t, store, time.Minute)
+
+			edits := 0
+			edit := func() {
+				song, err := store.Song().GetSong(job.SongID)
+				if err != nil {
+					t.Fatalf("GetSong() error = %v", err)
+				}
+				song.Link = "https://example.com/user"
+				if err = store.Song().UpdateSong(song); err != nil {
+					t.Fatalf("UpdateSong() error = %v", err)
+				}
+			}
+			provider := &editingProvider{Provider: songinfo.NewFake(), edit: func() {
+				if edits < tt.edits {
+					edits++
+					edit()
+				}
+			}}
+
+			// Если песню все время меняют, каждая попытка сохранения натыкается на новую правку
+			jobs := &editingJobStore{JobStore: store.Job(), before: func() {
+				if edits > 0 && edits < tt.edits {
+					edits++
+					edit()
+				}
+			}}
+
+			newTestPool(&jobStoreOverride{Store: store, jobs: jobs}, provider).process(context.Background(), job)
+
+			stored, err := store.Job().GetJob(job.ID)
+			if err != nil || stored.Status != tt.wantStatus {
+				t.Fatalf("job = %+v, %v; want status %s", stored, err, tt.wantStatus)
+			}
+			song, err := store.Song().GetSong(job.SongID)
+			if err != nil || song.Link != tt.wantLink {
+				t.Fatalf("song = %+v, %v; want link %s", song, err, tt.wantLink)
+			}
+			// Пустые поля заполняются, даже если пользователь успел изменить другие
+			if tt.wantStatus == models.JobDone && (song.ReleaseDate != "01.01.1990" || len(song.Text) != 3) {
+				t.Errorf("song = %+v, want filled release date and text", song)
+			}
+		})
+	}
+}
+
+func TestProcessDeletedSong(t *testing.T) {
+	store := storage.NewMemory()
+	job := enqueueAndClaim(t, store, time.Minute)
+
+	provider := &editingProvider{Provider: songinfo.NewFake(), edit: func() {
+		if err := store.Song().DeleteSong(job.SongID, 0); err != nil {
+			t.Fatalf("DeleteSong() error = %v", err)
+		}
+	}}
+	newTestPool(store, provider).process(context.Background(), job)
+
+	// Песня лежит в корзине, поэтому задание не повторяется, пока ее не вернут
+	stored, err := store.Job().GetJob(job.ID)
+	if err != nil || stored.Status != models.JobDead {
+		t.Errorf("job = %+v, %v; want status %s", stored, err, models.JobDead)
+	}
+}
+
+func TestProcessLeaseLost(t *testing.T) {
+	store := storage.NewMemory()
+	job := enqueueAndClaim(t, store, -time.Second)
+
+	// Аренда истекла, и задание забрал другой воркер, пока первый ждал ответа API
+	var stolen *models.Job
+	provider := &editingProvider{Provider: songinfo.NewFake(), edit: func() {
+		var err error
+		stolen, err = store.Job().ClaimJob(time.Minute)
+		if err != nil {
+			t.Fatalf("ClaimJob() error = %v", err)
+		}
+	}}
+	newTestPool(store, provider).process(context.Background(), job)
+
+	stored, err := store.Job().GetJob(job.ID)
+	if err != nil || stored.Status != models.JobRunning || stored.Attempts != stolen.Attempts {
+		t.Fatalf("job = %+v, %v; want job running by second worker", stored, err)
+	}
+	if song, err := store.Song().GetSong(job.SongID); err != nil || song.Link != "" {
+		t.Errorf("song = %+v, %v; want song untouched by first worker", song, err)
+	}
+
+	// Неудачная попытка первого воркера тоже не меняет задание второго
+	newTestPool(store, songinfo.NewFake()).fail(job, context.DeadlineExceeded, false)
+	if stored, err := store.Job().GetJob(job.ID); err != nil || stored.Status != models.JobRunning {
+		t.Errorf("job = %+v, %v; want job still running", stored, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	pool := &Pool{config: Config{RetryDelay: time.Second, MaxRetryDelay: 5 * time.Second}}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{100, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := pool.retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// Хранилище, у которого подменен репозиторий заданий
+type jobStoreOverride struct {
+	storage.Store
+	jobs storage.JobStore
+}
+
+func (s *jobStoreOverride) Job() storage.JobStore {
+	return s.jobs
+}
+
+// Репозиторий заданий, вызывающий before перед каждым сохранением результата задания
+type editingJobStore struct {
+	storage.JobStore
+	before func()
+}
+
+func (r *editingJobStore) CompleteJob(job *models.Job, song *models.Song) error {
+	r.before()
+	return r.JobStore.CompleteJob(job, song)
+}
+
+func (r *editingJobStore) WithAuthor(author string) storage.JobStore {
+	return &editingJobStore{JobStore: r.JobStore.WithAuthor(author), before: r.before}
+}
@@ -0,0 +1,20 @@
+package models
+
+// Модель альбома, в который входят песни исполнителя
+type Album struct {
+	ID          int64         `json:"id"`
+	ArtistID    int64         `json:"artistId"`
+	Group       string        `json:"group"`
+	Title       string        `json:"title"`
+	ReleaseDate string        `json:"releaseDate,omitempty"`
+	CoverLink   string        `json:"coverLink,omitempty"`
+	Tracks      []*AlbumTrack `json:"tracks,omitempty"`
+}
+
+// Модель трека альбома (ссылка на песню с ее позицией в альбоме)
+type AlbumTrack struct {
+	SongID      int64  `json:"songId"`
+	Song        string `json:"song,omitempty"`
+	DiscNumber  int    `json:"discNumber"`
+	TrackNumber int    `json:"trackNumber"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// Модель исполнителя, на которого ссылаются песни (переименование исполнителя сразу отражается во всех его песнях)
+type Artist struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName"`
+	Country     string   `json:"country,omitempty"`
+	FormedYear  *int     `json:"formedYear,omitempty"`
+	Members     []string `json:"members"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Статус задания на заполнение информации о песне
+type JobStatus string
+
+const (
+	JobPending JobStatus = "pending" // Ожидает выполнения (в том числе повторного после ошибки)
+	JobRunning JobStatus = "running" // Выполняется воркером
+	JobDone    JobStatus = "done"    // Информация о песне заполнена
+	JobDead    JobStatus = "dead"    // Все попытки исчерпаны (задание можно перезапустить вручную)
+)
+
+// Модель задания на заполнение информации о песне (дата релиза, текст и ссылка) из стороннего API
+type Job struct {
+	ID        int64     `json:"id"`
+	SongID    int64     `json:"songId"`
+	Status    JobStatus `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError,omitempty"`
+	RunAt     time.Time `json:"runAt"` // Время, не раньше которого задание будет выполнено (следующая попытка)
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Функция, возвращающая ключ названия исполнителя или песни, по которому они ищутся и проверяются на уникальность
+// (Unicode NFKC, нижний регистр, без пробелов по краям и с одним пробелом между словами; само название хранится как есть)
+func NameKey(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(norm.NFKC.String(name))), " ")
+}
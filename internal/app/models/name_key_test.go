@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+func TestNameKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"AC/DC", "ac/dc"},
+		{"  Back   In\tBlack ", "back in black"},
+		{"Ｍｕｓｅ", "muse"},
+		{"ﬁre", "fire"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NameKey(tt.name); got != tt.want {
+			t.Errorf("NameKey(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Модель плейлиста (упорядоченный список песен пользователя)
+type Playlist struct {
+	ID          int64            `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Owner       string           `json:"owner"`  // Владелец плейлиста (только он может изменять плейлист и видеть его, если плейлист не публичный)
+	Public      bool             `json:"public"` // Плейлист виден всем пользователям
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+	Entries     []*PlaylistEntry `json:"entries,omitempty"`
+}
+
+// Модель записи плейлиста (ссылка на песню с ее номером в плейлисте; одна песня может входить в плейлист несколько раз)
+type PlaylistEntry struct {
+	Position   int    `json:"position"` // Номер записи в плейлисте (отсчитывается с 0)
+	SongID     int64  `json:"songId"`
+	Group      string `json:"group"`
+	Song       string `json:"song"`
+	Link       string `json:"link,omitempty"`
+	VerseCount int    `json:"verseCount"`
+	Deleted    bool   `json:"deleted,omitempty"` // Песня лежит в корзине (запись вернется в плейлист вместе с песней)
+}
@@ -0,0 +1,37 @@
+package models
+
+import "slices"
+
+// Модель результата повторного запроса информации о песне у стороннего API
+type SongRefresh struct {
+	ID      int64         `json:"id"`
+	Group   string        `json:"group"`
+	Song    string        `json:"song"`
+	Changes []FieldChange `json:"changes"`         // Поля, значения которых у стороннего API отличаются от сохраненных
+	Applied bool          `json:"applied"`         // Сохранены ли новые значения в библиотеке
+	Error   string        `json:"error,omitempty"` // Причина, по которой информацию получить или сохранить не удалось (только при массовом обновлении)
+}
+
+// Модель изменения одного поля песни
+type FieldChange struct {
+	Field string `json:"field"` // Название поля в формате JSON (releaseDate, text или link)
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Метод, возвращающий отличия даты релиза, текста и ссылки обновленной песни от текущей
+func (song *Song) InfoChanges(updated *Song) []FieldChange {
+	changes := []FieldChange{}
+
+	if song.ReleaseDate != updated.ReleaseDate {
+		changes = append(changes, FieldChange{Field: "releaseDate", Old: song.ReleaseDate, New: updated.ReleaseDate})
+	}
+	if !slices.Equal(song.Text, updated.Text) {
+		changes = append(changes, FieldChange{Field: "text", Old: song.Text, New: updated.Text})
+	}
+	if song.Link != updated.Link {
+		changes = append(changes, FieldChange{Field: "link", Old: song.Link, New: updated.Link})
+	}
+
+	return changes
+}
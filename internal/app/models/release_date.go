@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Точность даты релиза (сторонний API может вернуть как полную дату, так и только месяц или год)
+type DatePrecision string
+
+const (
+	PrecisionDay   DatePrecision = "day"   // Полная дата: 25.08.1991
+	PrecisionMonth DatePrecision = "month" // Месяц и год: 08.1991
+	PrecisionYear  DatePrecision = "year"  // Только год: 1991
+)
+
+// Поддерживаемые форматы даты релиза (формат dd.mm.yyyy возвращает сторонний API)
+var releaseDateLayouts = []struct {
+	layout    string
+	precision DatePrecision
+}{
+	{"02.01.2006", PrecisionDay},
+	{"2.1.2006", PrecisionDay},
+	{"2006-01-02", PrecisionDay},
+	{"01.2006", PrecisionMonth},
+	{"1.2006", PrecisionMonth},
+	{"2006-01", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// Функция, разбирающая дату релиза (неполная дата приводится к первому дню своего периода)
+func ParseReleaseDate(value string) (time.Time, DatePrecision, error) {
+	for _, format := range releaseDateLayouts {
+		date, err := time.Parse(format.layout, value)
+		if err == nil {
+			return date, format.precision, nil
+		}
+	}
+
+	return time.Time{}, "", fmt.Errorf("uncorrected release date %q: expected dd.mm.yyyy, mm.yyyy or yyyy", value)
+}
+
+// Функция, форматирующая дату релиза с учетом ее точности (обратная к ParseReleaseDate)
+func FormatReleaseDate(date time.Time, precision DatePrecision) string {
+	switch precision {
+	case PrecisionYear:
+		return date.Format("2006")
+	case PrecisionMonth:
+		return date.Format("01.2006")
+	default:
+		return date.Format("02.01.2006")
+	}
+}
+
+// Функция, возвращающая период, который покрывает дата релиза с учетом точности (конец периода не включается)
+func ReleasePeriod(date time.Time, precision DatePrecision) (time.Time, time.Time) {
+	switch precision {
+	case PrecisionYear:
+		return date, date.AddDate(1, 0, 0)
+	case PrecisionMonth:
+		return date, date.AddDate(0, 1, 0)
+	default:
+		return date, date.AddDate(0, 0, 1)
+	}
+}
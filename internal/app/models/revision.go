@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Вид изменения песни
+type RevisionAction string
+
+const (
+	RevisionCreate RevisionAction = "create" // Песня добавлена (в том числе восстановлена после удаления)
+	RevisionUpdate RevisionAction = "update" // Песня изменена
+	RevisionDelete RevisionAction = "delete" // Песня удалена
+)
+
+// Модель ревизии песни (запись в истории изменений, номер ревизии совпадает с версией песни после изменения)
+type SongRevision struct {
+	SongID    int64          `json:"songId"`
+	Revision  int64          `json:"revision"`
+	Action    RevisionAction `json:"action"`
+	Author    string         `json:"author,omitempty"` // Кто изменил песню (пусто, если изменение сделал сам сервер, например воркер)
+	ChangedAt time.Time      `json:"changedAt"`
+	Old       *Song          `json:"old,omitempty"` // Песня до изменения (нет у добавления)
+	New       *Song          `json:"new,omitempty"` // Песня после изменения (нет у удаления)
+}
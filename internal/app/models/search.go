@@ -0,0 +1,20 @@
+package models
+
+// Модель результата полнотекстового поиска по текстам песен
+type SongSearchResult struct {
+	Song    *Song        `json:"song"`
+	Rank    float64      `json:"rank"`    // Релевантность песни запросу (чем больше, тем выше песня в выдаче)
+	Matches []VerseMatch `json:"matches"` // Куплеты, в которых найдено совпадение
+}
+
+// Модель куплета, в котором найдено совпадение с поисковым запросом
+type VerseMatch struct {
+	Verse   int    `json:"verse"`   // Номер куплета (отсчитывается с 0, как offset в /api/songs/{id}/text)
+	Snippet string `json:"snippet"` // Фрагмент куплета, в котором найденные слова выделены тегами <b></b>
+}
+
+// Модель песни, похожей на запрос по названию исполнителя или песни (нечеткий поиск)
+type SongSuggestion struct {
+	Song       *Song   `json:"song"`
+	Similarity float64 `json:"similarity"` // Триграммное сходство с запросом от 0 до 1 (1 - полное совпадение)
+}
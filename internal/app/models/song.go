@@ -1,10 +1,19 @@
 package models
 
+import "time"
+
 // Модель песни, представляющая собой способ хранения сущности, используемой в нашей БД
 type Song struct {
-	Group       string   `json:"group"`
-	Song        string   `json:"song"`
-	ReleaseDate string   `json:"releaseDate,omitempty"`
-	Text        []string `json:"text"`
-	Link        string   `json:"link,omitempty"`
+	ID          int64      `json:"id"`
+	ArtistID    int64      `json:"artistId"`
+	Group       string     `json:"group"`
+	Song        string     `json:"song"`
+	ReleaseDate string     `json:"releaseDate,omitempty"`
+	Text        []string   `json:"text"`
+	Link        string     `json:"link,omitempty"`
+	AlbumID     *int64     `json:"albumId,omitempty"`
+	DiscNumber  *int       `json:"discNumber,omitempty"`
+	TrackNumber *int       `json:"trackNumber,omitempty"`
+	Version     int64      `json:"version"`             // Номер версии, увеличивается при каждом изменении песни (используется в ETag)
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"` // Когда песня удалена в корзину (только у песен в корзине)
 }
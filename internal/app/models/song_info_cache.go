@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Модель закэшированного ответа стороннего API с информацией о песне
+type SongInfoCacheEntry struct {
+	GroupKey    string    // Название исполнителя, приведенное к нижнему регистру и одиночным пробелам
+	SongKey     string    // Название песни, приведенное к нижнему регистру и одиночным пробелам
+	Found       bool      // Знает ли сторонний API такую песню (false - закэширован ответ "песня не найдена")
+	ReleaseDate string    // Дата релиза в формате стороннего API
+	Text        string    // Текст песни в формате стороннего API (куплеты разделены пустой строкой)
+	Link        string    // Ссылка на песню
+	ExpiresAt   time.Time // Время, после которого ответ нужно запросить заново
+}
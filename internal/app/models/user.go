@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Модель пользователя (от его имени выполняются изменения библиотеки; владелец плейлистов и автор ревизий песен)
+type User struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	PasswordHash string    `json:"-"` // bcrypt хэш пароля (наружу не отдается)
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Модель API-ключа пользователя (долгоживущий ключ для сервисных аккаунтов и скриптов; сам ключ показывается только при создании)
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"-"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // Начало ключа, по которому пользователь может узнать ключ в списке
+	KeyHash    string     `json:"-"`      // SHA-256 хэш ключа (сам ключ не хранится)
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
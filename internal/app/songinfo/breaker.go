@@ -0,0 +1,105 @@
+package songinfo
+
+import (
+	"sync"
+	"time"
+)
+
+// Состояние автоматического выключателя
+type breakerState string
+
+const (
+	breakerClosed   breakerState = "closed"    // Запросы выполняются как обычно
+	breakerOpen     breakerState = "open"      // Запросы сразу завершаются ошибкой, не обращаясь к стороннему API
+	breakerHalfOpen breakerState = "half-open" // Выполняется один пробный запрос, по результату которого выключатель закрывается или снова открывается
+)
+
+// Настройки автоматического выключателя
+type BreakerConfig struct {
+	FailureThreshold int           // Количество неудачных запросов подряд, после которого выключатель открывается
+	OpenTimeout      time.Duration // Время, в течение которого запросы не выполняются, после чего разрешается пробный запрос
+}
+
+// Настройки автоматического выключателя по умолчанию
+var DefaultBreakerConfig = BreakerConfig{FailureThreshold: 5, OpenTimeout: 30 * time.Second}
+
+// Автоматический выключатель (circuit breaker), не дающий обращаться к стороннему API, пока тот недоступен
+type breaker struct {
+	mu       sync.Mutex
+	config   BreakerConfig
+	state    breakerState
+	failures int       // Количество неудачных запросов подряд
+	openedAt time.Time // Время последнего открытия выключателя
+	probing  bool      // Пробный запрос в полуоткрытом состоянии уже выполняется
+	onChange func(state breakerState)
+}
+
+// Конструктор, возвращающий закрытый выключатель (onChange вызывается при каждой смене состояния)
+func newBreaker(config BreakerConfig, onChange func(state breakerState)) *breaker {
+	b := &breaker{config: config, state: breakerClosed, onChange: onChange}
+	onChange(b.state)
+	return b
+}
+
+// Метод, проверяющий, можно ли выполнить запрос (в полуоткрытом состоянии разрешается только один запрос)
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.config.OpenTimeout {
+			return false
+		}
+		b.setState(breakerHalfOpen)
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Метод, фиксирующий успешный запрос (выключатель закрывается)
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	b.setState(breakerClosed)
+}
+
+// Метод, фиксирующий неудачный запрос (выключатель открывается после FailureThreshold неудач подряд или неудачного пробного запроса)
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == breakerHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = time.Now()
+		b.setState(breakerOpen)
+	}
+}
+
+// Метод, фиксирующий запрос, результат которого ничего не говорит о состоянии стороннего API (например, пользователь отменил запрос)
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Метод, меняющий состояние выключателя (вызывается под блокировкой)
+func (b *breaker) setState(state breakerState) {
+	if b.state != state {
+		b.state = state
+		b.onChange(state)
+	}
+}
@@ -0,0 +1,80 @@
+package songinfo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Функция, возвращающая выключатель, который записывает смены своего состояния в *states
+func newTestBreaker(threshold int, states *[]breakerState) *breaker {
+	return newBreaker(BreakerConfig{FailureThreshold: threshold, OpenTimeout: time.Minute}, func(state breakerState) {
+		*states = append(*states, state)
+	})
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	var states []breakerState
+	b := newTestBreaker(3, &states)
+
+	// Успешный запрос сбрасывает счетчик неудач подряд
+	b.failure()
+	b.failure()
+	b.success()
+	b.failure()
+	b.failure()
+	if b.state != breakerClosed || !b.allow() {
+		t.Fatalf("state = %s after 2 failures in a row, want %s", b.state, breakerClosed)
+	}
+
+	b.failure()
+	if b.state != breakerOpen {
+		t.Fatalf("state = %s after 3 failures in a row, want %s", b.state, breakerOpen)
+	}
+	if b.allow() {
+		t.Error("open breaker allows request before timeout")
+	}
+
+	want := []breakerState{breakerClosed, breakerOpen}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("states = %v, want %v", states, want)
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		probe     func(b *breaker)
+		wantState breakerState
+		wantAllow bool
+	}{
+		{"successful probe closes", (*breaker).success, breakerClosed, true},
+		{"failed probe opens again", (*breaker).failure, breakerOpen, false},
+		{"released probe allows another probe", (*breaker).release, breakerHalfOpen, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var states []breakerState
+			b := newTestBreaker(1, &states)
+			b.failure()
+
+			// Время ожидания истекло: разрешается ровно один пробный запрос
+			b.openedAt = time.Now().Add(-time.Minute)
+			if !b.allow() {
+				t.Fatal("breaker does not allow probe after timeout")
+			}
+			if b.state != breakerHalfOpen || b.allow() {
+				t.Fatalf("state = %s, second request allowed during probe", b.state)
+			}
+
+			tt.probe(b)
+			if b.state != tt.wantState {
+				t.Errorf("state = %s, want %s", b.state, tt.wantState)
+			}
+			if got := b.allow(); got != tt.wantAllow {
+				t.Errorf("allow() = %v, want %v", got, tt.wantAllow)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package songinfo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Где хранятся закэшированные ответы стороннего API
+type CacheBackend string
+
+const (
+	CacheOff     CacheBackend = "off"     // Кэш отключен, каждый запрос уходит в сторонний API
+	CacheLRU     CacheBackend = "lru"     // Кэш в памяти процесса с вытеснением давно не использованных ответов (по умолчанию)
+	CacheStorage CacheBackend = "storage" // Кэш в хранилище приложения (в Postgres переживает перезапуск и общий для всех экземпляров сервера)
+)
+
+// Настройки кэша ответов стороннего API
+type CacheConfig struct {
+	Backend     CacheBackend
+	TTL         time.Duration // Сколько хранится информация о песне
+	NotFoundTTL time.Duration // Сколько хранится ответ "песня не найдена" (обычно меньше TTL, т.к. песня может появиться у стороннего API)
+	Size        int           // Максимальное количество ответов в LRU кэше
+}
+
+// Настройки кэша по умолчанию
+var DefaultCacheConfig = CacheConfig{Backend: CacheLRU, TTL: 24 * time.Hour, NotFoundTTL: time.Hour, Size: 1000}
+
+// Функция, считывающая настройки кэша из переменных окружения
+func CacheConfigFromEnv() (CacheConfig, error) {
+	config := DefaultCacheConfig
+
+	switch backend := CacheBackend(os.Getenv("SONG_INFO_CACHE")); backend {
+	case "":
+	case CacheOff, CacheLRU, CacheStorage:
+		config.Backend = backend
+	default:
+		return config, fmt.Errorf("unknown song info cache: %s", backend)
+	}
+
+	durations := []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"SONG_INFO_CACHE_TTL", &config.TTL},
+		{"SONG_INFO_CACHE_NOT_FOUND_TTL", &config.NotFoundTTL},
+	}
+	for _, d := range durations {
+		value := os.Getenv(d.env)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return config, fmt.Errorf("uncorrected %s %q: must be a positive duration, for example 1h", d.env, value)
+		}
+		*d.value = duration
+	}
+
+	if value := os.Getenv("SONG_INFO_CACHE_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size <= 0 {
+			return config, fmt.Errorf("uncorrected SONG_INFO_CACHE_SIZE %q: must be a positive number", value)
+		}
+		config.Size = size
+	}
+
+	return config, nil
+}
+
+// Интерфейс хранилища закэшированных ответов (реализуется LRU кэшем и репозиторием кэша из пакета storage)
+type CacheStore interface {
+	GetCachedSongInfo(groupKey, songKey string) (*models.SongInfoCacheEntry, error) // Возвращает неистекший ответ (sql.ErrNoRows, если его нет)
+	PutCachedSongInfo(entry *models.SongInfoCacheEntry) error                       // Сохраняет ответ (заменяя предыдущий по тому же ключу)
+}
+
+// Кэш перед источником информации о песнях (сам тоже является источником)
+type Cache struct {
+	provider    Provider
+	store       CacheStore
+	ttl         time.Duration
+	notFoundTTL time.Duration
+}
+
+// Конструктор, возвращающий кэш, хранящий ответы provider в store
+func NewCache(provider Provider, store CacheStore, config CacheConfig) *Cache {
+	return &Cache{provider: provider, store: store, ttl: config.TTL, notFoundTTL: config.NotFoundTTL}
+}
+
+// Ключ контекста, отключающего чтение из кэша
+type bypassCacheKey struct{}
+
+// Функция, возвращающая контекст, с которым информация о песне всегда запрашивается у стороннего API (полученный ответ все равно кэшируется)
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// Функция, приводящая название к ключу кэша (тот же ключ названия, по которому ищутся песни в библиотеке)
+func CacheKey(name string) string {
+	return models.NameKey(name)
+}
+
+// Метод, возвращающий информацию о песне из кэша, а если ее там нет, то из стороннего API
+func (cache *Cache) GetSongInfo(ctx context.Context, group, song string) (*SongDetail, error) {
+	groupKey, songKey := CacheKey(group), CacheKey(song)
+
+	if bypass, _ := ctx.Value(bypassCacheKey{}).(bool); !bypass {
+		entry, err := cache.store.GetCachedSongInfo(groupKey, songKey)
+		switch {
+		case err == nil && entry.Found:
+			metrics.Add("cache_hits", 1)
+			return &SongDetail{ReleaseDate: entry.ReleaseDate, Text: entry.Text, Link: entry.Link}, nil
+		case err == nil:
+			metrics.Add("cache_negative_hits", 1)
+			return nil, fmt.Errorf("%w (cached)", ErrSongNotFound)
+		case err != sql.ErrNoRows:
+			// Недоступность кэша не должна мешать получению информации о песне
+			metrics.Add("cache_errors", 1)
+		}
+	}
+	metrics.Add("cache_misses", 1)
+
+	detail, err := cache.provider.GetSongInfo(ctx, group, song)
+
+	// Кэшируем только окончательные ответы: временную недоступность стороннего API запоминать нельзя
+	entry := &models.SongInfoCacheEntry{GroupKey: groupKey, SongKey: songKey}
+	switch {
+	case err == nil:
+		entry.Found, entry.ReleaseDate, entry.Text, entry.Link = true, detail.ReleaseDate, detail.Text, detail.Link
+		entry.ExpiresAt = time.Now().Add(cache.ttl)
+	case errors.Is(err, ErrSongNotFound):
+		entry.ExpiresAt = time.Now().Add(cache.notFoundTTL)
+	default:
+		return nil, err
+	}
+	if cacheErr := cache.store.PutCachedSongInfo(entry); cacheErr != nil {
+		metrics.Add("cache_errors", 1)
+	}
+
+	return detail, err
+}
@@ -0,0 +1,116 @@
+package songinfo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"mus_lib/internal/app/models"
+	"testing"
+	"time"
+)
+
+// Источник информации о песнях, считающий обращения к нему
+type countingProvider struct {
+	calls  int
+	detail *SongDetail
+	err    error
+}
+
+func (p *countingProvider) GetSongInfo(ctx context.Context, group, song string) (*SongDetail, error) {
+	p.calls++
+	return p.detail, p.err
+}
+
+func TestCache(t *testing.T) {
+	detail := DefaultFakeDetail
+	config := CacheConfig{TTL: time.Hour, NotFoundTTL: time.Hour}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantErr   error
+		wantCalls int // Сколько раз второй запрос той же песни дошел до стороннего API (вместе с первым)
+	}{
+		{"song info is cached", nil, nil, 1},
+		{"not found is cached", ErrSongNotFound, ErrSongNotFound, 1},
+		{"unavailability is not cached", ErrUnavailable, ErrUnavailable, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &countingProvider{detail: &detail, err: tt.err}
+			if tt.err != nil {
+				provider.detail = nil
+			}
+			cache := NewCache(provider, NewLRU(10), config)
+
+			for i := 0; i < 2; i++ {
+				// Ключ кэша не зависит от регистра и лишних пробелов
+				got, err := cache.GetSongInfo(context.Background(), []string{"Muse", " muse "}[i], "Uprising")
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetSongInfo() #%d error = %v, want %v", i+1, err, tt.wantErr)
+				}
+				if err == nil && *got != detail {
+					t.Errorf("GetSongInfo() #%d = %+v, want %+v", i+1, got, detail)
+				}
+			}
+			if provider.calls != tt.wantCalls {
+				t.Errorf("provider calls = %d, want %d", provider.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCacheWithoutCache(t *testing.T) {
+	detail := DefaultFakeDetail
+	provider := &countingProvider{detail: &detail}
+	store := NewLRU(10)
+	cache := NewCache(provider, store, CacheConfig{TTL: time.Hour, NotFoundTTL: time.Hour})
+
+	_, _ = cache.GetSongInfo(context.Background(), "Muse", "Uprising")
+	updated := detail
+	updated.Link = "https://example.com/new"
+	provider.detail = &updated
+
+	// Запрос в обход кэша всегда доходит до стороннего API, а его ответ заменяет закэшированный
+	got, err := cache.GetSongInfo(WithoutCache(context.Background()), "Muse", "Uprising")
+	if err != nil || got.Link != updated.Link || provider.calls != 2 {
+		t.Fatalf("GetSongInfo() without cache = %+v, %v after %d calls", got, err, provider.calls)
+	}
+	got, err = cache.GetSongInfo(context.Background(), "Muse", "Uprising")
+	if err != nil || got.Link != updated.Link || provider.calls != 2 {
+		t.Errorf("GetSongInfo() = %+v, %v after %d calls, want cached updated info", got, err, provider.calls)
+	}
+}
+
+func TestLRU(t *testing.T) {
+	lru := NewLRU(2)
+	put := func(group string, expiresAt time.Time) {
+		_ = lru.PutCachedSongInfo(&models.SongInfoCacheEntry{GroupKey: group, SongKey: "song", Found: true, ExpiresAt: expiresAt})
+	}
+	future := time.Now().Add(time.Hour)
+
+	put("a", future)
+	put("b", future)
+	// Использованный ответ становится самым свежим, поэтому при переполнении сначала вытесняется b, а затем a
+	if _, err := lru.GetCachedSongInfo("a", "song"); err != nil {
+		t.Fatalf("GetCachedSongInfo(a) error = %v", err)
+	}
+	put("c", future)
+	put("d", time.Now().Add(-time.Second))
+
+	tests := []struct {
+		group   string
+		wantErr error
+	}{
+		{"a", sql.ErrNoRows},
+		{"b", sql.ErrNoRows},
+		{"c", nil},
+		{"d", sql.ErrNoRows},
+	}
+	for _, tt := range tests {
+		if _, err := lru.GetCachedSongInfo(tt.group, "song"); err != tt.wantErr {
+			t.Errorf("GetCachedSongInfo(%s) error = %v, want %v", tt.group, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package songinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Таймаут запроса к стороннему API по умолчанию
+const defaultTimeout = 5 * time.Second
+
+// Максимальный размер ответа стороннего API (защита от чрезмерно больших ответов)
+const maxResponseSize = 1 << 20
+
+// Настройки клиента стороннего API
+type Config struct {
+	BaseURL    string        // Адрес метода стороннего API, возвращающего информацию о песне (например, https://example.com/info)
+	Timeout    time.Duration // Таймаут одного запроса
+	AuthHeader string        // Заголовок, в котором передается токен авторизации
+	AuthToken  string        // Токен авторизации (если пустой, заголовок не передается)
+	Retry      RetryPolicy   // Политика повторных запросов
+	Breaker    BreakerConfig // Настройки автоматического выключателя
+}
+
+// Функция, считывающая настройки клиента из переменных окружения
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		BaseURL:    os.Getenv("SONG_INFO_URL"),
+		Timeout:    defaultTimeout,
+		AuthHeader: os.Getenv("SONG_INFO_AUTH_HEADER"),
+		AuthToken:  os.Getenv("SONG_INFO_AUTH_TOKEN"),
+		Retry:      DefaultRetryPolicy,
+		Breaker:    DefaultBreakerConfig,
+	}
+	if config.AuthHeader == "" {
+		config.AuthHeader = "Authorization"
+	}
+
+	durations := []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"SONG_INFO_TIMEOUT", &config.Timeout},
+		{"SONG_INFO_RETRY_BASE_DELAY", &config.Retry.BaseDelay},
+		{"SONG_INFO_RETRY_MAX_DELAY", &config.Retry.MaxDelay},
+		{"SONG_INFO_BREAKER_OPEN_TIMEOUT", &config.Breaker.OpenTimeout},
+	}
+	for _, d := range durations {
+		value := os.Getenv(d.env)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return config, fmt.Errorf("uncorrected %s %q: must be a positive duration, for example 5s", d.env, value)
+		}
+		*d.value = duration
+	}
+
+	numbers := []struct {
+		env   string
+		value *int
+	}{
+		{"SONG_INFO_RETRY_ATTEMPTS", &config.Retry.MaxAttempts},
+		{"SONG_INFO_BREAKER_THRESHOLD", &config.Breaker.FailureThreshold},
+	}
+	for _, n := range numbers {
+		value := os.Getenv(n.env)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number <= 0 {
+			return config, fmt.Errorf("uncorrected %s %q: must be a positive number", n.env, value)
+		}
+		*n.value = number
+	}
+
+	return config, nil
+}
+
+// Клиент стороннего API с информацией о песнях
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	authHeader string
+	authToken  string
+	retry      RetryPolicy
+	breaker    *breaker
+}
+
+// Конструктор, возвращающий клиент стороннего API
+func NewClient(config Config) (*Client, error) {
+	baseURL, err := url.Parse(config.BaseURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return nil, fmt.Errorf("uncorrected song info url %q: must be an absolute http or https url", config.BaseURL)
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		authHeader: config.AuthHeader,
+		authToken:  config.AuthToken,
+		retry:      config.Retry,
+		breaker:    newBreaker(config.Breaker, recordBreakerState),
+	}, nil
+}
+
+// Метод, запрашивающий информацию о песне у стороннего API (с повторами и автоматическим выключателем)
+func (c *Client) GetSongInfo(ctx context.Context, group, song string) (*SongDetail, error) {
+	metrics.Add("requests", 1)
+
+	// Если сторонний API недавно был недоступен, сразу возвращаем ошибку, не дожидаясь таймаутов
+	if !c.breaker.allow() {
+		err := fmt.Errorf("%w: %w", ErrUnavailable, ErrCircuitOpen)
+		recordOutcome(err)
+		return nil, err
+	}
+
+	detail, err := c.getWithRetry(ctx, group, song)
+	switch {
+	case ctx.Err() != nil:
+		// Запрос отменен пользователем, это ничего не говорит о состоянии стороннего API
+		c.breaker.release()
+	case errors.Is(err, ErrRateLimited):
+		// Ограничение частоты запросов означает, что сторонний API работает, поэтому не приближаем открытие выключателя
+		c.breaker.release()
+	case errors.Is(err, ErrUnavailable):
+		c.breaker.failure()
+	default:
+		c.breaker.success()
+	}
+	recordOutcome(err)
+
+	return detail, err
+}
+
+// Метод, выполняющий запрос с повторами при временных ошибках (экспоненциальная задержка со случайным разбросом)
+func (c *Client) getWithRetry(ctx context.Context, group, song string) (*SongDetail, error) {
+	for attempt := 0; ; attempt++ {
+		detail, err := c.get(ctx, group, song)
+
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt+1 >= c.retry.MaxAttempts {
+			return detail, err
+		}
+
+		// Если сторонний API сам указал, когда повторить запрос, ждем именно столько (но не дольше MaxDelay)
+		delay := c.retry.backoff(attempt)
+		if retryable.retryAfter > 0 {
+			if retryable.retryAfter > c.retry.MaxDelay {
+				return nil, err
+			}
+			delay = retryable.retryAfter
+		}
+		metrics.Add("retries", 1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w: %w", ErrUnavailable, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// Метод, выполняющий одну попытку запроса к стороннему API
+func (c *Client) get(ctx context.Context, group, song string) (*SongDetail, error) {
+	// Формируем адрес запроса (параметры кодируются, а не просто заменяются пробелы)
+	requestURL := *c.baseURL
+	query := requestURL.Query()
+	query.Set("group", group)
+	query.Set("song", song)
+	requestURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.authToken != "" {
+		req.Header.Set(c.authHeader, c.authToken)
+	}
+
+	responce, err := c.httpClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrUnavailable, err)
+		if retryableTransportError(err) {
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
+	}
+	defer func() {
+		// Дочитываем ответ, чтобы соединение можно было переиспользовать
+		_, _ = io.Copy(io.Discard, io.LimitReader(responce.Body, maxResponseSize))
+		responce.Body.Close()
+	}()
+
+	// Проверяем статус ответа (400 и 404 означают, что пользователь предоставил данные несуществующей песни)
+	switch {
+	case responce.StatusCode == http.StatusOK:
+	case responce.StatusCode == http.StatusBadRequest || responce.StatusCode == http.StatusNotFound:
+		return nil, ErrSongNotFound
+	case retryableStatus(responce.StatusCode):
+		err := fmt.Errorf("%w: unexpected status %d", ErrUnavailable, responce.StatusCode)
+		if responce.StatusCode == http.StatusTooManyRequests {
+			err = fmt.Errorf("%w: %w", ErrUnavailable, ErrRateLimited)
+		}
+		return nil, &retryableError{err: err, retryAfter: parseRetryAfter(responce.Header.Get("Retry-After"), time.Now())}
+	default:
+		return nil, fmt.Errorf("%w: unexpected status %d", ErrUnavailable, responce.StatusCode)
+	}
+
+	var detail SongDetail
+	err = json.NewDecoder(io.LimitReader(responce.Body, maxResponseSize)).Decode(&detail)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+
+	err = detail.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// Метод, проверяющий, что ответ стороннего API содержит пригодные для сохранения данные
+func (detail *SongDetail) validate() error {
+	if detail.Text == "" && detail.Link == "" && detail.ReleaseDate == "" {
+		return fmt.Errorf("%w: response has no song info", ErrInvalidResponse)
+	}
+	if detail.Link != "" {
+		link, err := url.Parse(detail.Link)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return fmt.Errorf("%w: uncorrected link %q", ErrInvalidResponse, detail.Link)
+		}
+	}
+
+	return nil
+}
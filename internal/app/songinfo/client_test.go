@@ -0,0 +1,156 @@
+package songinfo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Функция, возвращающая клиент тестового HTTP сервера стороннего API
+func newHandlerClient(t *testing.T, handler http.HandlerFunc, config Config) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config.BaseURL = server.URL + "/info"
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://example.com/info", false},
+		{"http://localhost:8081", false},
+		{"", true},
+		{"example.com/info", true},
+		{"ftp://example.com/info", true},
+	}
+
+	for _, tt := range tests {
+		_, err := NewClient(Config{BaseURL: tt.url})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewClient(%q) error = %v, want error %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("SONG_INFO_URL", "https://example.com/info")
+	t.Setenv("SONG_INFO_AUTH_HEADER", "")
+	t.Setenv("SONG_INFO_TIMEOUT", "2s")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv() error = %v", err)
+	}
+	if config.AuthHeader != "Authorization" || config.Timeout != 2*time.Second {
+		t.Errorf("ConfigFromEnv() = %+v, want default auth header and 2s timeout", config)
+	}
+
+	t.Setenv("SONG_INFO_TIMEOUT", "-1s")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("ConfigFromEnv() with negative timeout error = nil")
+	}
+}
+
+func TestClientGetSongInfo(t *testing.T) {
+	fake := NewFake()
+	fake.Add("AC/DC", "Back & Black", SongDetail{Text: "one\n\ntwo", Link: "https://example.com/bib"})
+
+	client := newHandlerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" || r.Header.Get("X-Token") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}, Config{AuthHeader: "X-Token", AuthToken: "secret"})
+
+	// Параметры запроса кодируются, поэтому спецсимволы в названиях не ломают адрес
+	detail, err := client.GetSongInfo(context.Background(), "AC/DC", "Back & Black")
+	if err != nil {
+		t.Fatalf("GetSongInfo() error = %v", err)
+	}
+	if detail.Link != "https://example.com/bib" || len(detail.Verses()) != 2 {
+		t.Errorf("GetSongInfo() = %+v, want registered song", detail)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{"not found", http.StatusNotFound, "", ErrSongNotFound},
+		{"bad request", http.StatusBadRequest, "", ErrSongNotFound},
+		{"server error", http.StatusInternalServerError, "", ErrUnavailable},
+		{"invalid json", http.StatusOK, "{", ErrInvalidResponse},
+		{"empty info", http.StatusOK, `{}`, ErrInvalidResponse},
+		{"invalid link", http.StatusOK, `{"link":"javascript:alert(1)"}`, ErrInvalidResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHandlerClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}, Config{})
+
+			_, err := client.GetSongInfo(context.Background(), "Muse", "Uprising")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetSongInfo() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFakeUnknownSong(t *testing.T) {
+	fake := NewFake()
+	fake.Default = nil
+
+	_, err := fake.GetSongInfo(context.Background(), "Muse", "Unknown")
+	if !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("GetSongInfo() error = %v, want %v", err, ErrSongNotFound)
+	}
+
+	fake.Add(" MUSE ", "Uprising", SongDetail{Link: "https://example.com/uprising"})
+	if detail, err := fake.GetSongInfo(context.Background(), "muse", "uprising"); err != nil || detail.Link != "https://example.com/uprising" {
+		t.Errorf("GetSongInfo() = %+v, %v; want registered song ignoring case", detail, err)
+	}
+}
+
+func TestSongDetailVerses(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"one", 1},
+		{"one\ntwo\n\nthree", 2},
+	}
+
+	for _, tt := range tests {
+		detail := SongDetail{Text: tt.text}
+		if got := detail.Verses(); len(got) != tt.want {
+			t.Errorf("Verses() for %q = %q, want %d verses", tt.text, got, tt.want)
+		}
+	}
+	if verses := (&SongDetail{}).Verses(); verses != nil {
+		t.Errorf("Verses() for empty text = %q, want nil", verses)
+	}
+}
@@ -0,0 +1,123 @@
+package songinfo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Информация, которую фейковый провайдер по умолчанию возвращает для любой песни
+var DefaultFakeDetail = SongDetail{
+	ReleaseDate: "01.01.1990",
+	Text:        "AAAA\nBBBB\nCCCC\n\nDDDD\nEEEE\nFFFF\n\nGGG\nHHH\nKKK",
+	Link:        "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+}
+
+// Фейковый провайдер информации о песнях (для локальной разработки и тестов; может работать как HTTP сервер в формате стороннего API)
+type Fake struct {
+	mu       sync.RWMutex
+	songs    map[string]SongDetail
+	failures []fakeFailure
+	Default  *SongDetail // Информация для незарегистрированных песен (если nil, такие песни считаются несуществующими)
+}
+
+// Ошибка, которую фейковый HTTP сервер вернет вместо ответа (для проверки повторов и автоматического выключателя)
+type fakeFailure struct {
+	status     int
+	retryAfter time.Duration
+}
+
+// Конструктор, возвращающий фейковый провайдер, который знает любую песню (возвращает DefaultFakeDetail)
+func NewFake() *Fake {
+	detail := DefaultFakeDetail
+	return &Fake{songs: make(map[string]SongDetail), Default: &detail}
+}
+
+// Метод, регистрирующий информацию о конкретной песне
+func (f *Fake) Add(group, song string, detail SongDetail) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.songs[fakeKey(group, song)] = detail
+}
+
+// Метод, заставляющий фейковый HTTP сервер ответить статусом status на следующие times запросов (retryAfter > 0 добавляет заголовок Retry-After)
+func (f *Fake) FailNext(times, status int, retryAfter time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := 0; i < times; i++ {
+		f.failures = append(f.failures, fakeFailure{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Метод, возвращающий информацию о песне без обращения по сети
+func (f *Fake) GetSongInfo(ctx context.Context, group, song string) (*SongDetail, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	detail, ok := f.songs[fakeKey(group, song)]
+	if !ok {
+		if f.Default == nil {
+			return nil, ErrSongNotFound
+		}
+		detail = *f.Default
+	}
+
+	return &detail, nil
+}
+
+// Метод, обрабатывающий HTTP запрос так же, как сторонний API (GET ?group=...&song=...)
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if failure, ok := f.nextFailure(); ok {
+		if failure.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(failure.retryAfter.Seconds())))
+		}
+		http.Error(w, http.StatusText(failure.status), failure.status)
+		return
+	}
+
+	group, song := r.URL.Query().Get("group"), r.URL.Query().Get("song")
+	if group == "" || song == "" {
+		http.Error(w, "group and song value must be not empty", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := f.GetSongInfo(r.Context(), group, song)
+	if err != nil {
+		http.Error(w, "song not found", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(detail)
+}
+
+// Метод, извлекающий очередную запланированную ошибку
+func (f *Fake) nextFailure() (fakeFailure, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.failures) == 0 {
+		return fakeFailure{}, false
+	}
+	failure := f.failures[0]
+	f.failures = f.failures[1:]
+
+	return failure, true
+}
+
+// Метод, запускающий фейковый провайдер как HTTP сервер на случайном порту (адрес сервера - в поле URL, остановка - Close)
+func (f *Fake) Start() *httptest.Server {
+	return httptest.NewServer(f)
+}
+
+// Функция, возвращающая ключ песни без учета регистра и пробелов по краям
+func fakeKey(group, song string) string {
+	return strings.ToLower(strings.TrimSpace(group)) + "\x00" + strings.ToLower(strings.TrimSpace(song))
+}
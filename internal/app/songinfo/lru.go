@@ -0,0 +1,70 @@
+package songinfo
+
+import (
+	"container/list"
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"sync"
+	"time"
+)
+
+// Кэш ответов в памяти процесса, вытесняющий давно не использованные ответы при переполнении
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List                  // Ответы от последнего использованного к давно не использованному
+	entries map[[2]string]*list.Element // Элементы order по ключу
+}
+
+// Конструктор, возвращающий LRU кэш на size ответов
+func NewLRU(size int) *LRU {
+	return &LRU{size: size, order: list.New(), entries: make(map[[2]string]*list.Element)}
+}
+
+// Метод, возвращающий неистекший ответ по ключу (sql.ErrNoRows, если его нет)
+func (lru *LRU) GetCachedSongInfo(groupKey, songKey string) (*models.SongInfoCacheEntry, error) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	element, ok := lru.entries[[2]string{groupKey, songKey}]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	entry := element.Value.(*models.SongInfoCacheEntry)
+	if !entry.ExpiresAt.After(time.Now()) {
+		lru.remove(element)
+		return nil, sql.ErrNoRows
+	}
+	lru.order.MoveToFront(element)
+
+	out := *entry
+	return &out, nil
+}
+
+// Метод, сохраняющий ответ (при переполнении вытесняется самый давно не использованный)
+func (lru *LRU) PutCachedSongInfo(entry *models.SongInfoCacheEntry) error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	in := *entry
+	key := [2]string{entry.GroupKey, entry.SongKey}
+	if element, ok := lru.entries[key]; ok {
+		element.Value = &in
+		lru.order.MoveToFront(element)
+		return nil
+	}
+
+	lru.entries[key] = lru.order.PushFront(&in)
+	if lru.order.Len() > lru.size {
+		lru.remove(lru.order.Back())
+		metrics.Add("cache_evictions", 1)
+	}
+
+	return nil
+}
+
+// Метод, удаляющий ответ из кэша
+func (lru *LRU) remove(element *list.Element) {
+	entry := lru.order.Remove(element).(*models.SongInfoCacheEntry)
+	delete(lru.entries, [2]string{entry.GroupKey, entry.SongKey})
+}
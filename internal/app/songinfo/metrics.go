@@ -0,0 +1,42 @@
+package songinfo
+
+import (
+	"errors"
+	"expvar"
+)
+
+// Метрики обращений к стороннему API (доступны по /debug/vars в разделе song_info)
+var metrics = expvar.NewMap("song_info")
+
+// Текущее состояние автоматического выключателя
+var circuitState expvar.String
+
+func init() {
+	metrics.Set("circuit_state", &circuitState)
+}
+
+// Функция, учитывающая итог запроса информации о песне в метриках
+func recordOutcome(err error) {
+	switch {
+	case err == nil:
+		metrics.Add("success", 1)
+	case errors.Is(err, ErrCircuitOpen):
+		metrics.Add("circuit_rejected", 1)
+	case errors.Is(err, ErrSongNotFound):
+		metrics.Add("not_found", 1)
+	case errors.Is(err, ErrInvalidResponse):
+		metrics.Add("invalid_response", 1)
+	case errors.Is(err, ErrRateLimited):
+		metrics.Add("rate_limited", 1)
+	default:
+		metrics.Add("unavailable", 1)
+	}
+}
+
+// Функция, учитывающая смену состояния автоматического выключателя в метриках
+func recordBreakerState(state breakerState) {
+	circuitState.Set(string(state))
+	if state == breakerOpen {
+		metrics.Add("circuit_opened", 1)
+	}
+}
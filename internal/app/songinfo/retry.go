@@ -0,0 +1,88 @@
+package songinfo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Политика повторных запросов к стороннему API
+type RetryPolicy struct {
+	MaxAttempts int           // Максимальное количество попыток (включая первую)
+	BaseDelay   time.Duration // Задержка перед первым повтором (дальше удваивается с каждой попыткой)
+	MaxDelay    time.Duration // Максимальная задержка (если Retry-After просит ждать дольше, повтор не выполняется)
+}
+
+// Политика повторных запросов по умолчанию
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+// Метод, возвращающий задержку перед повтором номер attempt (экспоненциальный рост со случайным разбросом, чтобы клиенты не повторяли запросы одновременно)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	// Половина задержки фиксирована, вторая половина выбирается случайно
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// Ошибка попытки запроса, после которой запрос можно повторить
+type retryableError struct {
+	err        error
+	retryAfter time.Duration // Задержка, которую попросил сторонний API в заголовке Retry-After (0, если заголовка нет)
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// Функция, проверяющая, стоит ли повторять запрос с таким статусом ответа
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// Функция, проверяющая, стоит ли повторять запрос после сетевой ошибки (повторяются только таймауты и обрывы соединения)
+func retryableTransportError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// Функция, разбирающая заголовок Retry-After (количество секунд или HTTP дата)
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,229 @@
+package songinfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{4, time.Second},
+		{100, time.Second},
+	}
+
+	for _, tt := range tests {
+		// Задержка случайна, но всегда не меньше половины и не больше полной задержки попытки
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(tt.attempt)
+			if delay < tt.max/2 || delay > tt.max {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.attempt, delay, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetryableStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusGatewayTimeout, true},
+		{http.StatusInternalServerError, false},
+		{http.StatusNotFound, false},
+		{http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		if got := retryableStatus(tt.status); got != tt.want {
+			t.Errorf("retryableStatus(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+// Ошибка сети с таймаутом (реализует net.Error)
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryableTransportError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", fmt.Errorf("%w: %w", ErrUnavailable, timeoutError{}), true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"connection reset", syscall.ECONNRESET, true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"canceled", fmt.Errorf("%w: %w", ErrUnavailable, context.Canceled), false},
+		{"other", errors.New("tls: bad certificate"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryableTransportError(tt.err); got != tt.want {
+				t.Errorf("retryableTransportError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"0", 0},
+		{"-3", 0},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+// Функция, возвращающая клиент фейкового HTTP сервера с короткими задержками повторов
+func newTestClient(t *testing.T, fake *Fake, breaker BreakerConfig) *Client {
+	t.Helper()
+
+	server := fake.Start()
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(Config{
+		BaseURL: server.URL,
+		Timeout: time.Second,
+		Retry:   RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+		Breaker: breaker,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	return client
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		status     int
+		retryAfter time.Duration
+		wantErr    error
+		wantLeft   int // Сколько запланированных ошибок фейковый сервер не вернул
+	}{
+		{"success after retries", 2, http.StatusServiceUnavailable, 0, nil, 0},
+		{"attempts exhausted", 4, http.StatusBadGateway, 0, ErrUnavailable, 1},
+		{"not retryable status", 2, http.StatusInternalServerError, 0, ErrUnavailable, 1},
+		{"retry after longer than max delay", 2, http.StatusTooManyRequests, time.Second, ErrUnavailable, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFake()
+			client := newTestClient(t, fake, DefaultBreakerConfig)
+			fake.FailNext(tt.failures, tt.status, tt.retryAfter)
+
+			detail, err := client.GetSongInfo(context.Background(), "Muse", "Uprising")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetSongInfo() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && *detail != DefaultFakeDetail {
+				t.Errorf("GetSongInfo() = %+v, want %+v", detail, DefaultFakeDetail)
+			}
+			if left := len(fake.failures); left != tt.wantLeft {
+				t.Errorf("failures left = %d, want %d", left, tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestClientSongNotFound(t *testing.T) {
+	fake := NewFake()
+	fake.Default = nil
+	client := newTestClient(t, fake, DefaultBreakerConfig)
+
+	_, err := client.GetSongInfo(context.Background(), "Muse", "Unknown")
+	if !errors.Is(err, ErrSongNotFound) {
+		t.Fatalf("GetSongInfo() error = %v, want %v", err, ErrSongNotFound)
+	}
+	// Неизвестная песня не считается недоступностью стороннего API
+	if client.breaker.failures != 0 {
+		t.Errorf("breaker failures = %d, want 0", client.breaker.failures)
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	fake := NewFake()
+	client := newTestClient(t, fake, BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})
+	fake.FailNext(6, http.StatusServiceUnavailable, 0)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetSongInfo(context.Background(), "Muse", "Uprising")
+		if !errors.Is(err, ErrUnavailable) || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("GetSongInfo() #%d error = %v, want %v", i+1, err, ErrUnavailable)
+		}
+	}
+
+	// Выключатель открыт: запрос не доходит до стороннего API
+	_, err := client.GetSongInfo(context.Background(), "Muse", "Uprising")
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("GetSongInfo() with open breaker error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if left := len(fake.failures); left != 0 {
+		t.Errorf("failures left = %d, want 0", left)
+	}
+}
+
+func TestClientRateLimitedKeepsBreakerClosed(t *testing.T) {
+	fake := NewFake()
+	client := newTestClient(t, fake, BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})
+
+	// Ответы 429 повторяются, но не считаются отказами стороннего API
+	fake.FailNext(9, http.StatusTooManyRequests, 0)
+	for i := 0; i < 3; i++ {
+		_, err := client.GetSongInfo(context.Background(), "Muse", "Uprising")
+		if !errors.Is(err, ErrRateLimited) || !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("GetSongInfo() #%d error = %v, want %v", i+1, err, ErrRateLimited)
+		}
+	}
+	if left := len(fake.failures); left != 0 {
+		t.Errorf("failures left = %d, want 0 (all attempts retried)", left)
+	}
+	if client.breaker.state != breakerClosed || client.breaker.failures != 0 {
+		t.Fatalf("breaker state = %s, failures = %d; want closed without failures", client.breaker.state, client.breaker.failures)
+	}
+
+	// После 429 запрос с Retry-After, укладывающимся в MaxDelay, повторяется и завершается успешно
+	fake.FailNext(1, http.StatusTooManyRequests, time.Millisecond)
+	if _, err := client.GetSongInfo(context.Background(), "Muse", "Uprising"); err != nil {
+		t.Errorf("GetSongInfo() after rate limit error = %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package songinfo
+
+import (
+	"context"
+	"errors"
+	"mus_lib/internal/app/models"
+	"strings"
+)
+
+var (
+	ErrSongNotFound    = errors.New("song not found by song info provider")         // Сторонний API не знает такой песни
+	ErrUnavailable     = errors.New("song info provider is unavailable")            // Сторонний API не отвечает или отвечает ошибкой сервера
+	ErrInvalidResponse = errors.New("song info provider returned invalid response") // Ответ стороннего API не соответствует ожидаемому формату
+	ErrCircuitOpen     = errors.New("song info provider circuit breaker is open")   // Запрос не выполнялся, т.к. сторонний API недавно был недоступен (вместе с ErrUnavailable)
+	ErrRateLimited     = errors.New("song info provider rate limit exceeded")       // Сторонний API ответил 429 (вместе с ErrUnavailable; не считается отказом API)
+)
+
+// Детальная информация о песне в формате стороннего API
+type SongDetail struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"` // Куплеты разделены пустой строкой
+	Link        string `json:"link"`
+}
+
+// Интерфейс источника детальной информации о песне (реализуется HTTP клиентом стороннего API и фейковым провайдером)
+type Provider interface {
+	GetSongInfo(ctx context.Context, group, song string) (*SongDetail, error) // Возвращает информацию о песне (ErrSongNotFound, если песня неизвестна)
+}
+
+// Метод, разбивающий текст песни на куплеты (пустой текст означает, что куплетов нет)
+func (detail *SongDetail) Verses() []string {
+	if detail.Text == "" {
+		return nil
+	}
+
+	return strings.Split(detail.Text, "\n\n")
+}
+
+// Метод, переносящий информацию из ответа стороннего API в песню (дата релиза в неизвестном формате не сохраняется, а возвращается как ошибка, чтобы ее можно было залогировать)
+func (detail *SongDetail) ApplyTo(song *models.Song) error {
+	song.Text = detail.Verses()
+	song.Link = detail.Link
+	song.ReleaseDate = ""
+
+	if detail.ReleaseDate == "" {
+		return nil
+	}
+	_, _, err := models.ParseReleaseDate(detail.ReleaseDate)
+	if err != nil {
+		return err
+	}
+	song.ReleaseDate = detail.ReleaseDate
+
+	return nil
+}
+
+// Метод, заполняющий из ответа стороннего API только те поля песни, которые еще пусты (ошибка - как у ApplyTo)
+func (detail *SongDetail) FillMissing(song *models.Song) error {
+	filled := *song
+	err := detail.ApplyTo(&filled)
+
+	if len(song.Text) == 0 {
+		song.Text = filled.Text
+	}
+	if song.Link == "" {
+		song.Link = filled.Link
+	}
+	if song.ReleaseDate == "" {
+		song.ReleaseDate = filled.ReleaseDate
+	}
+
+	return err
+}
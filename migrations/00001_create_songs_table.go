@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, создающая таблицу песен в БД (накатывающая миграция)
+func upCreateSongsTable(ctx context.Context, tx *sql.Tx) error {
+	// IF NOT EXISTS оставлен для БД, в которых таблица была создана до появления версионных миграций
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s("group" text, song text, releaseDate text, text text[], link text)`, os.Getenv("TABLE_NAME"))
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+// Функция, удаляющая таблицу песен из БД (откатывающая миграция)
+func downCreateSongsTable(ctx context.Context, tx *sql.Tx) error {
+	query := fmt.Sprintf("DROP TABLE IF EXISTS %s", os.Getenv("TABLE_NAME"))
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
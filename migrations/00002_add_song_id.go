@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, добавляющая суррогатный идентификатор песни (накатывающая миграция)
+func upAddSongID(ctx context.Context, tx *sql.Tx) error {
+	query := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS id bigserial PRIMARY KEY`, os.Getenv("TABLE_NAME"))
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+// Функция, удаляющая идентификатор песни (откатывающая миграция)
+func downAddSongID(ctx context.Context, tx *sql.Tx) error {
+	query := fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS id`, os.Getenv("TABLE_NAME"))
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, выносящая исполнителей в отдельную таблицу artists и связывающая с ней песни (накатывающая миграция)
+func upCreateArtistsTable(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		`CREATE TABLE artists(
+			id bigserial PRIMARY KEY,
+			name text NOT NULL UNIQUE,
+			display_name text NOT NULL,
+			country text NOT NULL DEFAULT '',
+			formed_year integer,
+			members text[] NOT NULL DEFAULT '{}'
+		)`,
+		// Переносим уже существующих исполнителей из песен
+		fmt.Sprintf(`INSERT INTO artists (name, display_name) SELECT DISTINCT COALESCE("group", ''), COALESCE("group", '') FROM %s`, os.Getenv("TABLE_NAME")),
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN artist_id bigint REFERENCES artists(id)`, os.Getenv("TABLE_NAME")),
+		fmt.Sprintf(`UPDATE %s s SET artist_id=a.id FROM artists a WHERE a.name=COALESCE(s."group", '')`, os.Getenv("TABLE_NAME")),
+		fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN artist_id SET NOT NULL`, os.Getenv("TABLE_NAME")),
+		fmt.Sprintf(`ALTER TABLE %s DROP COLUMN "group"`, os.Getenv("TABLE_NAME")),
+		fmt.Sprintf(`CREATE INDEX %[1]s_artist_id_idx ON %[1]s(artist_id)`, os.Getenv("TABLE_NAME")),
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, возвращающая название исполнителя в таблицу песен и удаляющая таблицу artists (откатывающая миграция)
+func downCreateArtistsTable(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN "group" text`, os.Getenv("TABLE_NAME")),
+		fmt.Sprintf(`UPDATE %s s SET "group"=a.name FROM artists a WHERE a.id=s.artist_id`, os.Getenv("TABLE_NAME")),
+		fmt.Sprintf(`ALTER TABLE %s DROP COLUMN artist_id`, os.Getenv("TABLE_NAME")),
+		`DROP TABLE artists`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
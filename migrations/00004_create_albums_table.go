@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, создающая таблицу альбомов и добавляющая песням ссылку на альбом и номер трека (накатывающая миграция)
+func upCreateAlbumsTable(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		`CREATE TABLE albums(
+			id bigserial PRIMARY KEY,
+			artist_id bigint NOT NULL REFERENCES artists(id),
+			title text NOT NULL,
+			release_date text NOT NULL DEFAULT '',
+			cover_link text NOT NULL DEFAULT '',
+			UNIQUE (artist_id, title)
+		)`,
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN album_id bigint REFERENCES albums(id) ON DELETE SET NULL, ADD COLUMN disc_number integer, ADD COLUMN track_number integer`, os.Getenv("TABLE_NAME")),
+		// Один и тот же номер трека не может повторяться на одном диске альбома
+		fmt.Sprintf(`CREATE UNIQUE INDEX %[1]s_album_track_idx ON %[1]s(album_id, disc_number, track_number) WHERE album_id IS NOT NULL`, os.Getenv("TABLE_NAME")),
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая альбомы и ссылки на них из песен (откатывающая миграция)
+func downCreateAlbumsTable(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		fmt.Sprintf(`ALTER TABLE %s DROP COLUMN album_id, DROP COLUMN disc_number, DROP COLUMN track_number`, os.Getenv("TABLE_NAME")),
+		`DROP TABLE albums`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
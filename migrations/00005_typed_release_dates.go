@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Временная функция, разбирающая текстовую дату релиза (нераспознанные значения превращаются в NULL, а не прерывают миграцию)
+const parseReleaseDateFunc = `CREATE FUNCTION pg_temp.parse_release_date(value text, format text) RETURNS date AS $$
+BEGIN
+	RETURN to_date(value, format);
+EXCEPTION WHEN others THEN
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`
+
+// Функция, переводящая даты релиза песен и альбомов из текста в DATE с точностью (накатывающая миграция)
+func upTypedReleaseDates(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{parseReleaseDateFunc}
+	for _, table := range []struct{ name, column string }{{os.Getenv("TABLE_NAME"), "releaseDate"}, {"albums", "release_date"}} {
+		queries = append(queries,
+			fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN %s TO release_date_text`, table.name, table.column),
+			fmt.Sprintf(`ALTER TABLE %s ADD COLUMN release_date date, ADD COLUMN release_date_precision text`, table.name),
+			fmt.Sprintf(`UPDATE %s SET
+				release_date = CASE
+					WHEN release_date_text ~ '^\d{1,2}\.\d{1,2}\.\d{4}$' THEN pg_temp.parse_release_date(release_date_text, 'DD.MM.YYYY')
+					WHEN release_date_text ~ '^\d{4}-\d{2}-\d{2}$' THEN pg_temp.parse_release_date(release_date_text, 'YYYY-MM-DD')
+					WHEN release_date_text ~ '^\d{1,2}\.\d{4}$' THEN pg_temp.parse_release_date(release_date_text, 'MM.YYYY')
+					WHEN release_date_text ~ '^\d{4}$' THEN pg_temp.parse_release_date(release_date_text, 'YYYY')
+				END,
+				release_date_precision = CASE
+					WHEN release_date_text ~ '^(\d{1,2}\.\d{1,2}\.\d{4}|\d{4}-\d{2}-\d{2})$' THEN 'day'
+					WHEN release_date_text ~ '^\d{1,2}\.\d{4}$' THEN 'month'
+					WHEN release_date_text ~ '^\d{4}$' THEN 'year'
+				END`, table.name),
+			fmt.Sprintf(`UPDATE %s SET release_date_precision=NULL WHERE release_date IS NULL`, table.name),
+			fmt.Sprintf(`ALTER TABLE %s DROP COLUMN release_date_text`, table.name),
+			fmt.Sprintf(`CREATE INDEX %[1]s_release_date_idx ON %[1]s(release_date)`, table.name),
+		)
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, возвращающая даты релиза в текстовом виде (откатывающая миграция)
+func downTypedReleaseDates(ctx context.Context, tx *sql.Tx) error {
+	var queries []string
+	for _, table := range []struct{ name, column, definition string }{{os.Getenv("TABLE_NAME"), "releaseDate", "text"}, {"albums", "release_date_text", "text NOT NULL DEFAULT ''"}} {
+		queries = append(queries,
+			fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table.name, table.column, table.definition),
+			fmt.Sprintf(`UPDATE %s SET %s = CASE release_date_precision
+					WHEN 'year' THEN to_char(release_date, 'YYYY')
+					WHEN 'month' THEN to_char(release_date, 'MM.YYYY')
+					ELSE COALESCE(to_char(release_date, 'DD.MM.YYYY'), '')
+				END`, table.name, table.column),
+			fmt.Sprintf(`ALTER TABLE %s DROP COLUMN release_date, DROP COLUMN release_date_precision`, table.name),
+		)
+	}
+	queries = append(queries, `ALTER TABLE albums RENAME COLUMN release_date_text TO release_date`)
+
+	return execAll(ctx, tx, queries)
+}
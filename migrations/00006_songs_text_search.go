@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, добавляющая песням tsvector колонку для полнотекстового поиска по тексту, триггер для ее обновления и GIN индекс (накатывающая миграция)
+func upSongsTextSearch(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN text_search tsvector`, table),
+		// Конфигурация simple не использует стемминг и стоп-слова, поэтому одинаково работает для текстов на любом языке.
+		// Куплеты разделяются переводом строки, чтобы слова соседних куплетов не склеивались
+		fmt.Sprintf(`CREATE FUNCTION %[1]s_text_search_update() RETURNS trigger AS $$
+		BEGIN
+			NEW.text_search := to_tsvector('simple', coalesce(array_to_string(NEW.text, E'\n'), ''));
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`, table),
+		fmt.Sprintf(`CREATE TRIGGER %[1]s_text_search_trigger BEFORE INSERT OR UPDATE OF text ON %[1]s
+		FOR EACH ROW EXECUTE FUNCTION %[1]s_text_search_update()`, table),
+		fmt.Sprintf(`UPDATE %s SET text_search = to_tsvector('simple', coalesce(array_to_string(text, E'\n'), ''))`, table),
+		fmt.Sprintf(`CREATE INDEX %[1]s_text_search_idx ON %[1]s USING GIN (text_search)`, table),
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая колонку для полнотекстового поиска вместе с триггером (откатывающая миграция)
+func downSongsTextSearch(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		fmt.Sprintf(`DROP TRIGGER %[1]s_text_search_trigger ON %[1]s`, table),
+		fmt.Sprintf(`DROP FUNCTION %s_text_search_update()`, table),
+		fmt.Sprintf(`ALTER TABLE %s DROP COLUMN text_search`, table),
+	}
+
+	return execAll(ctx, tx, queries)
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, подключающая расширение pg_trgm и создающая триграммные индексы по названиям исполнителей и песен (накатывающая миграция)
+func upTrigramIndexes(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`CREATE INDEX artists_name_trgm_idx ON artists USING GIN (name gin_trgm_ops)`,
+		fmt.Sprintf(`CREATE INDEX %[1]s_song_trgm_idx ON %[1]s USING GIN (song gin_trgm_ops)`, os.Getenv("TABLE_NAME")),
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая триграммные индексы (откатывающая миграция; расширение остается, т.к. им могут пользоваться и другие схемы)
+func downTrigramIndexes(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		fmt.Sprintf(`DROP INDEX %s_song_trgm_idx`, os.Getenv("TABLE_NAME")),
+		`DROP INDEX artists_name_trgm_idx`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, создающая таблицу заданий на заполнение информации о песнях из стороннего API (накатывающая миграция)
+func upCreateEnrichmentJobsTable(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		fmt.Sprintf(`CREATE TABLE enrichment_jobs(
+			id bigserial PRIMARY KEY,
+			song_id bigint NOT NULL REFERENCES %s(id) ON DELETE CASCADE,
+			status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done', 'dead')),
+			attempts integer NOT NULL DEFAULT 0,
+			last_error text NOT NULL DEFAULT '',
+			run_at timestamptz NOT NULL DEFAULT now(),
+			locked_until timestamptz,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			updated_at timestamptz NOT NULL DEFAULT now()
+		)`, os.Getenv("TABLE_NAME")),
+		// Воркеры выбирают задания, готовые к выполнению, в порядке run_at
+		`CREATE INDEX enrichment_jobs_queue_idx ON enrichment_jobs(run_at) WHERE status IN ('pending', 'running')`,
+		`CREATE INDEX enrichment_jobs_song_id_idx ON enrichment_jobs(song_id)`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая таблицу заданий (откатывающая миграция)
+func downCreateEnrichmentJobsTable(ctx context.Context, tx *sql.Tx) error {
+	return execAll(ctx, tx, []string{`DROP TABLE enrichment_jobs`})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Функция, создающая таблицу с кэшем ответов стороннего API (накатывающая миграция)
+func upCreateSongInfoCacheTable(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		// Ключ - названия исполнителя и песни в нижнем регистре с одиночными пробелами; found=false означает, что сторонний API не знает песни
+		`CREATE TABLE song_info_cache(
+			group_key text NOT NULL,
+			song_key text NOT NULL,
+			found boolean NOT NULL,
+			release_date text NOT NULL DEFAULT '',
+			text text NOT NULL DEFAULT '',
+			link text NOT NULL DEFAULT '',
+			expires_at timestamptz NOT NULL,
+			PRIMARY KEY (group_key, song_key)
+		)`,
+		`CREATE INDEX song_info_cache_expires_at_idx ON song_info_cache(expires_at)`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая таблицу с кэшем ответов стороннего API (откатывающая миграция)
+func downCreateSongInfoCacheTable(ctx context.Context, tx *sql.Tx) error {
+	return execAll(ctx, tx, []string{`DROP TABLE song_info_cache`})
+}
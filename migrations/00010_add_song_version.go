@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, добавляющая песням номер версии, который увеличивается триггером при каждом изменении строки (накатывающая миграция)
+func upAddSongVersion(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN version bigint NOT NULL DEFAULT 1`, table),
+		// Версия меняется при любом изменении песни (в том числе куплетов, альбома и заполнении информации воркером), а не только через API песен
+		fmt.Sprintf(`CREATE FUNCTION %[1]s_version_update() RETURNS trigger AS $$
+		BEGIN
+			NEW.version := OLD.version + 1;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`, table),
+		fmt.Sprintf(`CREATE TRIGGER %[1]s_version_trigger BEFORE UPDATE ON %[1]s
+		FOR EACH ROW EXECUTE FUNCTION %[1]s_version_update()`, table),
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая номер версии песен вместе с триггером (откатывающая миграция)
+func downAddSongVersion(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		fmt.Sprintf(`DROP TRIGGER %[1]s_version_trigger ON %[1]s`, table),
+		fmt.Sprintf(`DROP FUNCTION %s_version_update()`, table),
+		fmt.Sprintf(`ALTER TABLE %s DROP COLUMN version`, table),
+	}
+
+	return execAll(ctx, tx, queries)
+}
@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, создающая таблицу истории изменений песен и триггер, который записывает в нее каждое изменение (накатывающая миграция)
+func upCreateSongRevisionsTable(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		// Номер ревизии совпадает с версией песни после изменения (у удаления - следующая версия); внешнего ключа на песню нет,
+		// чтобы история оставалась после удаления песни. old и new - строка песни до и после изменения вместе с названием исполнителя
+		`CREATE TABLE song_revisions(
+			song_id bigint NOT NULL,
+			revision bigint NOT NULL,
+			action text NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+			author text,
+			changed_at timestamptz NOT NULL DEFAULT now(),
+			old jsonb,
+			new jsonb,
+			PRIMARY KEY (song_id, revision)
+		)`,
+		// Автора изменения приложение передает через локальную для транзакции настройку music_library.author
+		fmt.Sprintf(`CREATE FUNCTION %[1]s_revision_log() RETURNS trigger AS $$
+		DECLARE
+			old_row jsonb;
+			new_row jsonb;
+		BEGIN
+			IF TG_OP <> 'INSERT' THEN
+				old_row := (to_jsonb(OLD) - 'text_search') || jsonb_build_object('group', (SELECT name FROM artists WHERE id = OLD.artist_id));
+			END IF;
+			IF TG_OP <> 'DELETE' THEN
+				new_row := (to_jsonb(NEW) - 'text_search') || jsonb_build_object('group', (SELECT name FROM artists WHERE id = NEW.artist_id));
+			END IF;
+
+			INSERT INTO song_revisions (song_id, revision, action, author, old, new) VALUES (
+				CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END,
+				CASE WHEN TG_OP = 'DELETE' THEN OLD.version + 1 ELSE NEW.version END,
+				CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+				nullif(current_setting('music_library.author', true), ''),
+				old_row,
+				new_row
+			);
+			RETURN NULL;
+		END
+		$$ LANGUAGE plpgsql`, table),
+		fmt.Sprintf(`CREATE TRIGGER %[1]s_revision_trigger AFTER INSERT OR UPDATE OR DELETE ON %[1]s
+		FOR EACH ROW EXECUTE FUNCTION %[1]s_revision_log()`, table),
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая таблицу истории изменений песен вместе с триггером (откатывающая миграция)
+func downCreateSongRevisionsTable(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		fmt.Sprintf(`DROP TRIGGER %[1]s_revision_trigger ON %[1]s`, table),
+		fmt.Sprintf(`DROP FUNCTION %s_revision_log()`, table),
+		`DROP TABLE song_revisions`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
@@ -0,0 +1,92 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, добавляющая песням отметку об удалении в корзину (накатывающая миграция)
+func upAddSongSoftDelete(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN deleted_at timestamptz`, table),
+		fmt.Sprintf(`CREATE INDEX %[1]s_deleted_at_idx ON %[1]s(deleted_at) WHERE deleted_at IS NOT NULL`, table),
+		// Песня в корзине не занимает номер трека в альбоме (он может достаться другой песне)
+		fmt.Sprintf(`DROP INDEX %s_album_track_idx`, table),
+		fmt.Sprintf(`CREATE UNIQUE INDEX %[1]s_album_track_idx ON %[1]s(album_id, disc_number, track_number) WHERE album_id IS NOT NULL AND deleted_at IS NULL`, table),
+		// В истории удаление в корзину выглядит как удаление, а возвращение из корзины - как добавление.
+		// Окончательное удаление песни из корзины и изменения песни, лежащей в корзине, в историю не попадают
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION %[1]s_revision_log() RETURNS trigger AS $$
+		DECLARE
+			old_row jsonb;
+			new_row jsonb;
+			action text;
+		BEGIN
+			IF TG_OP <> 'INSERT' AND OLD.deleted_at IS NOT NULL AND (TG_OP = 'DELETE' OR NEW.deleted_at IS NOT NULL) THEN
+				RETURN NULL;
+			END IF;
+
+			IF TG_OP <> 'INSERT' AND (OLD.deleted_at IS NULL OR NEW.deleted_at IS NOT NULL) THEN
+				old_row := (to_jsonb(OLD) - 'text_search' - 'deleted_at') || jsonb_build_object('group', (SELECT name FROM artists WHERE id = OLD.artist_id));
+			END IF;
+			IF TG_OP <> 'DELETE' AND (NEW.deleted_at IS NULL OR OLD.deleted_at IS NOT NULL) THEN
+				new_row := (to_jsonb(NEW) - 'text_search' - 'deleted_at') || jsonb_build_object('group', (SELECT name FROM artists WHERE id = NEW.artist_id));
+			END IF;
+			action := CASE WHEN old_row IS NULL THEN 'create' WHEN new_row IS NULL THEN 'delete' ELSE 'update' END;
+
+			INSERT INTO song_revisions (song_id, revision, action, author, old, new) VALUES (
+				CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END,
+				CASE WHEN TG_OP = 'DELETE' THEN OLD.version + 1 ELSE NEW.version END,
+				action,
+				nullif(current_setting('music_library.author', true), ''),
+				old_row,
+				new_row
+			);
+			RETURN NULL;
+		END
+		$$ LANGUAGE plpgsql`, table),
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая отметку об удалении в корзину вместе с песнями, которые в ней лежат (откатывающая миграция)
+func downAddSongSoftDelete(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		fmt.Sprintf(`DELETE FROM %s WHERE deleted_at IS NOT NULL`, table),
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION %[1]s_revision_log() RETURNS trigger AS $$
+		DECLARE
+			old_row jsonb;
+			new_row jsonb;
+		BEGIN
+			IF TG_OP <> 'INSERT' THEN
+				old_row := (to_jsonb(OLD) - 'text_search') || jsonb_build_object('group', (SELECT name FROM artists WHERE id = OLD.artist_id));
+			END IF;
+			IF TG_OP <> 'DELETE' THEN
+				new_row := (to_jsonb(NEW) - 'text_search') || jsonb_build_object('group', (SELECT name FROM artists WHERE id = NEW.artist_id));
+			END IF;
+
+			INSERT INTO song_revisions (song_id, revision, action, author, old, new) VALUES (
+				CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END,
+				CASE WHEN TG_OP = 'DELETE' THEN OLD.version + 1 ELSE NEW.version END,
+				CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+				nullif(current_setting('music_library.author', true), ''),
+				old_row,
+				new_row
+			);
+			RETURN NULL;
+		END
+		$$ LANGUAGE plpgsql`, table),
+		fmt.Sprintf(`DROP INDEX %s_album_track_idx`, table),
+		fmt.Sprintf(`CREATE UNIQUE INDEX %[1]s_album_track_idx ON %[1]s(album_id, disc_number, track_number) WHERE album_id IS NOT NULL`, table),
+		fmt.Sprintf(`DROP INDEX %s_deleted_at_idx`, table),
+		fmt.Sprintf(`ALTER TABLE %s DROP COLUMN deleted_at`, table),
+	}
+
+	return execAll(ctx, tx, queries)
+}
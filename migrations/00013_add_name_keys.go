@@ -0,0 +1,184 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Функция, добавляющая исполнителям и песням ключи названий с уникальными индексами и сохраняющая регистр названий (накатывающая миграция)
+func upAddNameKeys(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	err := execAll(ctx, tx, []string{
+		`ALTER TABLE artists ADD COLUMN name_key text`,
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN song_key text`, table),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Ключи считаются тем же кодом, что и в приложении (lower и normalize в Postgres зависят от локали и версии сервера)
+	err = fillArtistNameKeys(ctx, tx)
+	if err != nil {
+		return err
+	}
+	// Заполнение ключа не меняет песни, поэтому не должно увеличивать их версии и попадать в историю изменений
+	err = execAll(ctx, tx, []string{fmt.Sprintf(`ALTER TABLE %[1]s DISABLE TRIGGER %[1]s_version_trigger, DISABLE TRIGGER %[1]s_revision_trigger`, table)})
+	if err != nil {
+		return err
+	}
+	err = fillSongKeys(ctx, tx, table)
+	if err != nil {
+		return err
+	}
+
+	return execAll(ctx, tx, []string{
+		fmt.Sprintf(`ALTER TABLE %[1]s ENABLE TRIGGER %[1]s_version_trigger, ENABLE TRIGGER %[1]s_revision_trigger`, table),
+		`ALTER TABLE artists ALTER COLUMN name_key SET NOT NULL`,
+		`ALTER TABLE artists DROP CONSTRAINT artists_name_key`,
+		`CREATE UNIQUE INDEX artists_name_key_idx ON artists(name_key)`,
+		fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN song_key SET NOT NULL`, table),
+		fmt.Sprintf(`CREATE UNIQUE INDEX %[1]s_song_key_idx ON %[1]s(artist_id, song_key) WHERE deleted_at IS NULL`, table),
+	})
+}
+
+// Функция, заполняющая ключи названий исполнителей (название берется из display_name, если они отличаются только регистром)
+func fillArtistNameKeys(ctx context.Context, tx *sql.Tx) error {
+	res, err := tx.QueryContext(ctx, `SELECT id, name, display_name FROM artists ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	var (
+		ids         []int64
+		names, keys []string
+		owners      = make(map[string]string)
+	)
+	for res.Next() {
+		var (
+			id                int64
+			name, displayName string
+		)
+		err := res.Scan(&id, &name, &displayName)
+		if err != nil {
+			return err
+		}
+		if strings.ToLower(displayName) == name {
+			name = displayName
+		}
+
+		// Исполнителей, названия которых совпадают с точностью до пробелов и написания символов, нельзя объединить автоматически
+		key := models.NameKey(name)
+		if owner, ok := owners[key]; ok {
+			return fmt.Errorf("artists %q and %q have the same name key %q: rename one of them and run the migration again", owner, name, key)
+		}
+		owners[key] = name
+
+		ids, names, keys = append(ids, id), append(names, name), append(keys, key)
+	}
+	if err = res.Err(); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE artists SET name=k.name, name_key=k.key FROM unnest($1::bigint[], $2::text[], $3::text[]) AS k(id, name, key) WHERE artists.id=k.id`,
+		pq.Array(ids), pq.Array(names), pq.Array(keys))
+	return err
+}
+
+// Функция, заполняющая ключи названий песен (прежние названия хранились в нижнем регистре, поэтому их регистр не восстановить)
+func fillSongKeys(ctx context.Context, tx *sql.Tx, table string) error {
+	res, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT id, artist_id, song, deleted_at IS NOT NULL FROM %s ORDER BY id`, table))
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	type songKey struct {
+		artistID int64
+		key      string
+	}
+
+	var (
+		ids        []int64
+		keys       []string
+		duplicates = make(map[songKey][]int64)
+		conflicts  []songKey
+	)
+	for res.Next() {
+		var (
+			id, artistID int64
+			song         string
+			deleted      bool
+		)
+		err := res.Scan(&id, &artistID, &song, &deleted)
+		if err != nil {
+			return err
+		}
+
+		key := models.NameKey(song)
+		ids, keys = append(ids, id), append(keys, key)
+
+		// Песни в корзине не мешают друг другу (уникальный индекс их не учитывает)
+		if deleted {
+			continue
+		}
+		k := songKey{artistID, key}
+		duplicates[k] = append(duplicates[k], id)
+		if len(duplicates[k]) == 2 {
+			conflicts = append(conflicts, k)
+		}
+	}
+	if err = res.Err(); err != nil {
+		return err
+	}
+
+	// Песни, названия которых совпадают с точностью до регистра и пробелов, нельзя выбрать автоматически: какую оставить, решает администратор
+	if len(conflicts) > 0 {
+		descriptions := make([]string, 0, len(conflicts))
+		for _, k := range conflicts {
+			descriptions = append(descriptions, fmt.Sprintf("%q of artist %d: %s", k.key, k.artistID, joinIDs(duplicates[k])))
+		}
+		return fmt.Errorf("songs have the same name key, move all but one song of each group to trash (UPDATE %s SET deleted_at=now() WHERE id IN (...)) and run the migration again: %s", table, strings.Join(descriptions, "; "))
+	}
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`UPDATE %[1]s SET song_key=k.key FROM unnest($1::bigint[], $2::text[]) AS k(id, key) WHERE %[1]s.id=k.id`, table),
+		pq.Array(ids), pq.Array(keys))
+	return err
+}
+
+// Функция, перечисляющая идентификаторы через запятую
+func joinIDs(ids []int64) string {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.FormatInt(id, 10))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// Функция, удаляющая ключи названий и возвращающая названия в нижнем регистре (откатывающая миграция)
+func downAddNameKeys(ctx context.Context, tx *sql.Tx) error {
+	table := os.Getenv("TABLE_NAME")
+
+	queries := []string{
+		fmt.Sprintf(`DROP INDEX %s_song_key_idx`, table),
+		fmt.Sprintf(`ALTER TABLE %s DROP COLUMN song_key`, table),
+		fmt.Sprintf(`ALTER TABLE %[1]s DISABLE TRIGGER %[1]s_version_trigger, DISABLE TRIGGER %[1]s_revision_trigger`, table),
+		fmt.Sprintf(`UPDATE %s SET song=lower(song)`, table),
+		fmt.Sprintf(`ALTER TABLE %[1]s ENABLE TRIGGER %[1]s_version_trigger, ENABLE TRIGGER %[1]s_revision_trigger`, table),
+		`DROP INDEX artists_name_key_idx`,
+		`ALTER TABLE artists DROP COLUMN name_key`,
+		`UPDATE artists SET name=lower(name)`,
+		`ALTER TABLE artists ADD CONSTRAINT artists_name_key UNIQUE (name)`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
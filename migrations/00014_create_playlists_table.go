@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Функция, создающая таблицы плейлистов и их записей (накатывающая миграция)
+func upCreatePlaylistsTable(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		`CREATE TABLE playlists(
+			id bigserial PRIMARY KEY,
+			name text NOT NULL,
+			description text NOT NULL DEFAULT '',
+			owner text NOT NULL,
+			public boolean NOT NULL DEFAULT false,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			updated_at timestamptz NOT NULL DEFAULT now()
+		)`,
+		`CREATE INDEX playlists_owner_idx ON playlists(owner)`,
+		// Записи удаляются вместе с плейлистом и с окончательно удаленной песней (номера записей при этом пересчитываются по порядку)
+		fmt.Sprintf(`CREATE TABLE playlist_entries(
+			playlist_id bigint NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+			position integer NOT NULL,
+			song_id bigint NOT NULL REFERENCES %s(id) ON DELETE CASCADE,
+			PRIMARY KEY (playlist_id, position)
+		)`, os.Getenv("TABLE_NAME")),
+		`CREATE INDEX playlist_entries_song_id_idx ON playlist_entries(song_id)`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая таблицы плейлистов (откатывающая миграция)
+func downCreatePlaylistsTable(ctx context.Context, tx *sql.Tx) error {
+	return execAll(ctx, tx, []string{`DROP TABLE playlist_entries`, `DROP TABLE playlists`})
+}
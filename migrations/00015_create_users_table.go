@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Функция, создающая таблицы пользователей и их API-ключей (накатывающая миграция)
+func upCreateUsersTable(ctx context.Context, tx *sql.Tx) error {
+	queries := []string{
+		// Имя пользователя хранится в нижнем регистре, поэтому уникальность проверяется обычным индексом
+		`CREATE TABLE users(
+			id bigserial PRIMARY KEY,
+			name text NOT NULL UNIQUE,
+			password_hash text NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now()
+		)`,
+		// Хранится только хэш ключа, поиск при аутентификации идет по нему
+		`CREATE TABLE api_keys(
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name text NOT NULL,
+			prefix text NOT NULL,
+			key_hash text NOT NULL UNIQUE,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			last_used_at timestamptz
+		)`,
+		`CREATE INDEX api_keys_user_id_idx ON api_keys(user_id)`,
+	}
+
+	return execAll(ctx, tx, queries)
+}
+
+// Функция, удаляющая таблицы пользователей (откатывающая миграция)
+func downCreateUsersTable(ctx context.Context, tx *sql.Tx) error {
+	return execAll(ctx, tx, []string{`DROP TABLE api_keys`, `DROP TABLE users`})
+}
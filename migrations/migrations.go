@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Описание версионной миграции (номер версии отслеживается goose в таблице goose_db_version)
+type migration struct {
+	version int64                                       // Номер версии (совпадает с префиксом имени файла)
+	name    string                                      // Название миграции (выводится в статусе)
+	up      func(ctx context.Context, tx *sql.Tx) error // Накатывающая часть
+	down    func(ctx context.Context, tx *sql.Tx) error // Откатывающая часть
+}
+
+// Список всех миграций приложения в порядке возрастания версий
+var migrations = []migration{
+	{1, "create_songs_table", upCreateSongsTable, downCreateSongsTable},
+	{2, "add_song_id", upAddSongID, downAddSongID},
+	{3, "create_artists_table", upCreateArtistsTable, downCreateArtistsTable},
+	{4, "create_albums_table", upCreateAlbumsTable, downCreateAlbumsTable},
+	{5, "typed_release_dates", upTypedReleaseDates, downTypedReleaseDates},
+	{6, "songs_text_search", upSongsTextSearch, downSongsTextSearch},
+	{7, "trigram_indexes", upTrigramIndexes, downTrigramIndexes},
+	{8, "create_enrichment_jobs_table", upCreateEnrichmentJobsTable, downCreateEnrichmentJobsTable},
+	{9, "create_song_info_cache_table", upCreateSongInfoCacheTable, downCreateSongInfoCacheTable},
+	{10, "add_song_version", upAddSongVersion, downAddSongVersion},
+	{11, "create_song_revisions_table", upCreateSongRevisionsTable, downCreateSongRevisionsTable},
+	{12, "add_song_soft_delete", upAddSongSoftDelete, downAddSongSoftDelete},
+	{13, "add_name_keys", upAddNameKeys, downAddNameKeys},
+	{14, "create_playlists_table", upCreatePlaylistsTable, downCreatePlaylistsTable},
+	{15, "create_users_table", upCreateUsersTable, downCreateUsersTable},
+}
+
+// Функция, создающая goose провайдер, через который накатываются и откатываются миграции приложения
+func NewProvider(db *sql.DB) (*goose.Provider, error) {
+	goMigrations := make([]*goose.Migration, 0, len(migrations))
+	for _, m := range migrations {
+		goMigrations = append(goMigrations, goose.NewGoMigration(m.version, &goose.GoFunc{RunTx: m.up}, &goose.GoFunc{RunTx: m.down}))
+	}
+
+	return goose.NewProvider(goose.DialectPostgres, db, nil,
+		goose.WithGoMigrations(goMigrations...),
+		goose.WithDisableGlobalRegistry(true),
+	)
+}
+
+// Функция, возвращающая название миграции по номеру ее версии
+func Name(version int64) string {
+	for _, m := range migrations {
+		if m.version == version {
+			return m.name
+		}
+	}
+
+	return ""
+}
+
+// Функция, последовательно выполняющая запросы миграции в рамках одной транзакции
+func execAll(ctx context.Context, tx *sql.Tx, queries []string) error {
+	for _, query := range queries {
+		_, err := tx.ExecContext(ctx, query)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+func TestMigrationsOrder(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != int64(i+1) {
+			t.Errorf("migration %q has version %d, want %d", m.name, m.version, i+1)
+		}
+		if m.name == "" || m.up == nil || m.down == nil {
+			t.Errorf("migration %d must have name, up and down parts", m.version)
+		}
+		if got := Name(m.version); got != m.name {
+			t.Errorf("Name(%d) = %q, want %q", m.version, got, m.name)
+		}
+	}
+
+	if got := Name(int64(len(migrations) + 1)); got != "" {
+		t.Errorf("Name() of unknown version = %q, want empty", got)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	// Соединение не открывается, пока к БД не обратились, поэтому Postgres для проверки не нужен
+	db, err := sql.Open("postgres", "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	provider, err := NewProvider(db)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	if sources := provider.ListSources(); len(sources) != len(migrations) {
+		t.Errorf("provider has %d migrations, want %d", len(sources), len(migrations))
+	}
+}
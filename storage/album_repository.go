@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+)
+
+// Сущность модельного репозитория альбомов
+type AlbumRepository struct {
+	storage *Storage // Хранит в себе БД, т.к. общение с БД реализовано посредством репозитория
+}
+
+// Колонки альбома, извлекаемые из БД (порядок соответствует scanAlbum)
+const albumColumns = `al.id, al.artist_id, a.name, al.title, al.release_date, al.release_date_precision, al.cover_link`
+
+// Источник данных для выборки альбомов (альбомы вместе с их исполнителями)
+const albumsTable = `albums al JOIN artists a ON a.id=al.artist_id`
+
+// Функция, считывающая альбом из строки результата запроса
+func scanAlbum(row scanner) (*models.Album, error) {
+	album := models.Album{}
+
+	var releaseDate releaseDateColumns
+	err := row.Scan(&album.ID, &album.ArtistID, &album.Group, &album.Title, &releaseDate.date, &releaseDate.precision, &album.CoverLink)
+	if err != nil {
+		return nil, err
+	}
+	album.ReleaseDate = releaseDate.String()
+
+	return &album, nil
+}
+
+// Метод для получения альбомов из БД с учетом пагинации (без списка треков)
+func (r *AlbumRepository) GetAlbums(offset, limit int) ([]*models.Album, error) {
+	res, err := r.storage.db.Query(`SELECT `+albumColumns+` FROM `+albumsTable+` ORDER BY al.id OFFSET $1 LIMIT $2`, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	albums := make([]*models.Album, 0)
+
+	for res.Next() {
+		album, err := scanAlbum(res)
+		if err != nil {
+			return nil, err
+		}
+
+		albums = append(albums, album)
+	}
+
+	return albums, res.Err()
+}
+
+// Метод для получения альбома из БД по идентификатору (вместе со списком треков)
+func (r *AlbumRepository) GetAlbum(id int64) (*models.Album, error) {
+	album, err := scanAlbum(r.storage.db.QueryRow(`SELECT `+albumColumns+` FROM `+albumsTable+` WHERE al.id=$1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.storage.db.Query(`SELECT id, song, coalesce(disc_number, 0), coalesce(track_number, 0) FROM `+songsTableName()+` WHERE album_id=$1 AND deleted_at IS NULL ORDER BY disc_number, track_number, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	album.Tracks = make([]*models.AlbumTrack, 0)
+
+	for res.Next() {
+		track := models.AlbumTrack{}
+		err := res.Scan(&track.SongID, &track.Song, &track.DiscNumber, &track.TrackNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		album.Tracks = append(album.Tracks, &track)
+	}
+
+	return album, res.Err()
+}
+
+// Метод для добавления альбома в БД (исполнитель создается, если его еще нет; песни из списка треков привязываются к альбому)
+func (r *AlbumRepository) AddAlbum(album *models.Album) error {
+	releaseDate, precision, err := releaseDateArgs(album.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	return r.storage.inTx(func(tx *sql.Tx) error {
+		err := tx.QueryRow(upsertArtistQuery, models.NameKey(album.Group), album.Group).Scan(&album.ArtistID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(`INSERT INTO albums (artist_id, title, release_date, release_date_precision, cover_link) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			album.ArtistID, album.Title, releaseDate, precision, album.CoverLink).Scan(&album.ID)
+		if err != nil {
+			return convertError(err)
+		}
+
+		return setAlbumTracks(tx, album.ID, album.Tracks)
+	})
+}
+
+// Метод для изменения альбома в БД (список треков заменяется, только если album.Tracks не nil; sql.ErrNoRows, если альбом не найден)
+func (r *AlbumRepository) UpdateAlbum(album *models.Album) error {
+	releaseDate, precision, err := releaseDateArgs(album.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	return r.storage.inTx(func(tx *sql.Tx) error {
+		err := tx.QueryRow(upsertArtistQuery, models.NameKey(album.Group), album.Group).Scan(&album.ArtistID)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(`UPDATE albums SET artist_id=$1, title=$2, release_date=$3, release_date_precision=$4, cover_link=$5 WHERE id=$6`,
+			album.ArtistID, album.Title, releaseDate, precision, album.CoverLink, album.ID)
+		if err != nil {
+			return convertError(err)
+		}
+		err = checkAffected(res)
+		if err != nil || album.Tracks == nil {
+			return err
+		}
+
+		// Отвязываем от альбома старые треки, чтобы заменить их новым списком
+		_, err = tx.Exec(`UPDATE `+songsTableName()+` SET album_id=NULL, disc_number=NULL, track_number=NULL WHERE album_id=$1 AND deleted_at IS NULL`, album.ID)
+		if err != nil {
+			return err
+		}
+
+		return setAlbumTracks(tx, album.ID, album.Tracks)
+	})
+}
+
+// Метод для удаления альбома из БД (песни альбома остаются, но теряют ссылку на него; sql.ErrNoRows, если альбом не найден)
+func (r *AlbumRepository) DeleteAlbum(id int64) error {
+	res, err := r.storage.db.Exec(`DELETE FROM albums WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+// Функция, привязывающая песни к альбому с заданными номерами дисков и треков (ErrBrokenReference, если песни нет)
+func setAlbumTracks(tx *sql.Tx, albumID int64, tracks []*models.AlbumTrack) error {
+	for _, track := range tracks {
+		res, err := tx.Exec(`UPDATE `+songsTableName()+` SET album_id=$1, disc_number=$2, track_number=$3 WHERE id=$4 AND deleted_at IS NULL`,
+			albumID, track.DiscNumber, track.TrackNumber, track.SongID)
+		if err != nil {
+			return convertError(err)
+		}
+		if checkAffected(res) == sql.ErrNoRows {
+			return ErrBrokenReference
+		}
+	}
+
+	return nil
+}
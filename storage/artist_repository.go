@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Сущность модельного репозитория исполнителей
+type ArtistRepository struct {
+	storage *Storage // Хранит в себе БД, т.к. общение с БД реализовано посредством репозитория
+}
+
+// Колонки исполнителя, извлекаемые из БД (порядок соответствует scanArtist)
+const artistColumns = `id, name, display_name, country, formed_year, members`
+
+// Функция, считывающая исполнителя из строки результата запроса
+func scanArtist(row scanner) (*models.Artist, error) {
+	artist := models.Artist{}
+
+	var formedYear sql.NullInt64
+	err := row.Scan(&artist.ID, &artist.Name, &artist.DisplayName, &artist.Country, &formedYear, pq.Array(&artist.Members))
+	if err != nil {
+		return nil, err
+	}
+	if formedYear.Valid {
+		year := int(formedYear.Int64)
+		artist.FormedYear = &year
+	}
+
+	return &artist, nil
+}
+
+// Метод для получения исполнителей из БД с учетом пагинации
+func (r *ArtistRepository) GetArtists(offset, limit int) ([]*models.Artist, error) {
+	res, err := r.storage.db.Query(`SELECT `+artistColumns+` FROM artists ORDER BY id OFFSET $1 LIMIT $2`, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	artists := make([]*models.Artist, 0)
+
+	for res.Next() {
+		artist, err := scanArtist(res)
+		if err != nil {
+			return nil, err
+		}
+
+		artists = append(artists, artist)
+	}
+
+	return artists, res.Err()
+}
+
+// Метод для получения исполнителя из БД по идентификатору
+func (r *ArtistRepository) GetArtist(id int64) (*models.Artist, error) {
+	return scanArtist(r.storage.db.QueryRow(`SELECT `+artistColumns+` FROM artists WHERE id=$1`, id))
+}
+
+// Метод для добавления исполнителя в БД (ErrAlreadyExists, если исполнитель с таким же ключом названия уже есть)
+func (r *ArtistRepository) AddArtist(artist *models.Artist) error {
+	normalizeArtist(artist)
+
+	err := r.storage.db.QueryRow(`INSERT INTO artists (name, name_key, display_name, country, formed_year, members) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		artist.Name, models.NameKey(artist.Name), artist.DisplayName, artist.Country, artist.FormedYear, pq.Array(artist.Members)).Scan(&artist.ID)
+	return convertError(err)
+}
+
+// Метод для изменения исполнителя в БД (изменение затрагивает все его песни; sql.ErrNoRows, если исполнитель не найден)
+func (r *ArtistRepository) UpdateArtist(artist *models.Artist) error {
+	normalizeArtist(artist)
+
+	res, err := r.storage.db.Exec(`UPDATE artists SET name=$1, name_key=$2, display_name=$3, country=$4, formed_year=$5, members=$6 WHERE id=$7`,
+		artist.Name, models.NameKey(artist.Name), artist.DisplayName, artist.Country, artist.FormedYear, pq.Array(artist.Members), artist.ID)
+	if err != nil {
+		return convertError(err)
+	}
+
+	return checkAffected(res)
+}
+
+// Метод для удаления исполнителя из БД (ErrInUse, если у исполнителя есть песни или альбомы; sql.ErrNoRows, если исполнитель не найден)
+func (r *ArtistRepository) DeleteArtist(id int64) error {
+	res, err := r.storage.db.Exec(`DELETE FROM artists WHERE id=$1`, id)
+	if err != nil {
+		return convertError(err)
+	}
+
+	return checkAffected(res)
+}
+
+// Функция, приводящая данные исполнителя к виду, в котором они хранятся (название сохраняет регистр, а ищется по ключу названия)
+func normalizeArtist(artist *models.Artist) {
+	artist.Name = strings.TrimSpace(artist.Name)
+	if artist.DisplayName == "" {
+		artist.DisplayName = artist.Name
+	}
+	if artist.Members == nil {
+		artist.Members = []string{}
+	}
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrAlreadyExists      = errors.New("record already exists")                      // Запись с такими уникальными данными уже существует
+	ErrInUse              = errors.New("record is referenced by other records")      // Запись нельзя удалить, пока на нее ссылаются другие записи
+	ErrBrokenReference    = errors.New("referenced record does not exist")           // Запись ссылается на несуществующую запись
+	ErrInvalidReleaseDate = errors.New("invalid release date")                       // Дата релиза не соответствует ни одному из поддерживаемых форматов
+	ErrWrongState         = errors.New("record state does not allow this operation") // Запись находится в состоянии, в котором операция невозможна
+	ErrLeaseLost          = errors.New("job lease was lost")                         // Аренда задания истекла, и его забрал другой воркер
+	ErrOutOfRange         = errors.New("position is out of range")                   // Номер куплета или записи плейлиста выходит за их пределы
+	ErrStaleVersion       = errors.New("record version does not match")              // Запись успели изменить после того, как клиент получил ее версию
+)
+
+// Функция, преобразующая ошибки Postgres в ошибки хранилища, на которые могут реагировать хэндлеры
+func convertError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		return ErrAlreadyExists
+	case "23503": // foreign_key_violation
+		return ErrInUse
+	default:
+		return err
+	}
+}
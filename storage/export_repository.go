@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+)
+
+// Сколько песен читается из курсора за один запрос
+const exportFetchSize = 500
+
+// Метод для выгрузки всех песен, удовлетворяющих фильтру (без пагинации): песни читаются из курсора на стороне БД порциями, поэтому память не зависит от размера библиотеки
+func (s *SongRepository) ExportSongs(filter SongFilter, fn func(song *models.Song) error) error {
+	tx, err := s.storage.db.Begin()
+	if err != nil {
+		return err
+	}
+	// Транзакция только читает данные, поэтому всегда откатывается (курсор закрывается вместе с ней)
+	defer tx.Rollback()
+
+	where, args := filter.whereSQL()
+	_, err = tx.Exec(fmt.Sprintf(`DECLARE songs_export NO SCROLL CURSOR FOR SELECT %s FROM %s %s %s`, songColumns, songsTable(), where, filter.orderSQL()), args...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		fetched, err := fetchExported(tx, fn)
+		if err != nil {
+			return err
+		}
+		if fetched < exportFetchSize {
+			return nil
+		}
+	}
+}
+
+// Функция, читающая из курсора очередную порцию песен, передающая их fn и возвращающая их количество
+func fetchExported(tx *sql.Tx, fn func(song *models.Song) error) (int, error) {
+	res, err := tx.Query(fmt.Sprintf(`FETCH FORWARD %d FROM songs_export`, exportFetchSize))
+	if err != nil {
+		return 0, err
+	}
+	defer res.Close()
+
+	fetched := 0
+	for res.Next() {
+		song, err := scanSong(res)
+		if err != nil {
+			return fetched, err
+		}
+		fetched++
+
+		err = fn(song)
+		if err != nil {
+			return fetched, err
+		}
+	}
+
+	return fetched, res.Err()
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"os"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Результат импорта одной песни
+type ImportedSong struct {
+	Created bool  // Песня добавлена (false - такая песня уже есть в библиотеке или раньше в этом же импорте)
+	JobID   int64 // Идентификатор задания на заполнение недостающей информации (0, если задание не создавалось)
+}
+
+// Функция, проверяющая, не хватает ли песне информации, которую может заполнить сторонний API
+func missingInfo(song *models.Song) bool {
+	return song.ReleaseDate == "" || len(song.Text) == 0 || song.Link == ""
+}
+
+// Метод для добавления песен в БД одним запросом (уже существующие песни пропускаются; идентификаторы и версии добавленных песен записываются в песни)
+func (s *SongRepository) ImportSongs(songs []*models.Song, enrich bool) ([]ImportedSong, error) {
+	results := make([]ImportedSong, len(songs))
+	if len(songs) == 0 {
+		return results, nil
+	}
+
+	err := s.storage.inTxAs(s.author, func(tx *sql.Tx) error {
+		artistIDs, err := upsertArtists(tx, songs)
+		if err != nil {
+			return err
+		}
+
+		// Песни с одинаковым ключом внутри одного запроса добавляются только один раз (первая из них)
+		type songKey struct {
+			artistID int64
+			key      string
+		}
+		var (
+			q      queryArgs
+			values []string
+			byKey  = make(map[songKey]int)
+		)
+		for i, song := range songs {
+			song.ArtistID = artistIDs[models.NameKey(song.Group)]
+			key := songKey{song.ArtistID, models.NameKey(song.Song)}
+			if _, ok := byKey[key]; ok {
+				continue
+			}
+			byKey[key] = i
+
+			releaseDate, precision, err := releaseDateArgs(song.ReleaseDate)
+			if err != nil {
+				return err
+			}
+			values = append(values, fmt.Sprintf("(%s, %s, %s, %s, %s, %s, %s)",
+				q.add(song.ArtistID), q.add(song.Song), q.add(key.key), q.add(releaseDate), q.add(precision), q.add(pq.Array(song.Text)), q.add(song.Link)))
+		}
+
+		// Песни, которые уже есть в библиотеке, отсеивает уникальный индекс по ключу названия
+		query := fmt.Sprintf(`INSERT INTO %s (artist_id, song, song_key, release_date, release_date_precision, text, link) VALUES %s
+			ON CONFLICT (artist_id, song_key) WHERE deleted_at IS NULL DO NOTHING RETURNING id, artist_id, song_key, version`, os.Getenv("TABLE_NAME"), strings.Join(values, ", "))
+
+		res, err := tx.Query(query, q.args...)
+		if err != nil {
+			return err
+		}
+		defer res.Close()
+
+		var created []int64
+		for res.Next() {
+			var (
+				key         songKey
+				id, version int64
+			)
+			err := res.Scan(&id, &key.artistID, &key.key, &version)
+			if err != nil {
+				return err
+			}
+
+			i := byKey[key]
+			songs[i].ID, songs[i].Version = id, version
+			results[i].Created = true
+			if enrich && missingInfo(songs[i]) {
+				created = append(created, id)
+			}
+		}
+		if err = res.Err(); err != nil {
+			return err
+		}
+
+		return enqueueImported(tx, created, songs, results)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Функция, возвращающая идентификаторы исполнителей песен по ключам их названий (недостающие исполнители создаются)
+func upsertArtists(tx *sql.Tx, songs []*models.Song) (map[string]int64, error) {
+	var names, keys []string
+	ids := make(map[string]int64)
+	for _, song := range songs {
+		key := models.NameKey(song.Group)
+		if _, ok := ids[key]; ok {
+			continue
+		}
+		ids[key] = 0
+		names, keys = append(names, song.Group), append(keys, key)
+	}
+
+	// Каждый ключ встречается в запросе один раз, иначе ON CONFLICT DO UPDATE не сможет изменить строку дважды
+	res, err := tx.Query(`INSERT INTO artists (name, name_key, display_name) SELECT n.name, n.key, n.name FROM unnest($1::text[], $2::text[]) AS n(name, key)
+		ON CONFLICT (name_key) DO UPDATE SET name_key=EXCLUDED.name_key RETURNING id, name_key`, pq.Array(names), pq.Array(keys))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var (
+			id  int64
+			key string
+		)
+		err := res.Scan(&id, &key)
+		if err != nil {
+			return nil, err
+		}
+		ids[key] = id
+	}
+
+	return ids, res.Err()
+}
+
+// Функция, создающая задания на заполнение информации добавленных песен и записывающая их идентификаторы в результаты импорта
+func enqueueImported(tx *sql.Tx, songIDs []int64, songs []*models.Song, results []ImportedSong) error {
+	if len(songIDs) == 0 {
+		return nil
+	}
+
+	res, err := tx.Query(`INSERT INTO enrichment_jobs (song_id) SELECT unnest($1::bigint[]) RETURNING id, song_id`, pq.Array(songIDs))
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	jobIDs := make(map[int64]int64)
+	for res.Next() {
+		var jobID, songID int64
+		err := res.Scan(&jobID, &songID)
+		if err != nil {
+			return err
+		}
+		jobIDs[songID] = jobID
+	}
+	if err = res.Err(); err != nil {
+		return err
+	}
+
+	for i, song := range songs {
+		if results[i].Created {
+			results[i].JobID = jobIDs[song.ID]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"time"
+)
+
+// Сущность модельного репозитория заданий
+type JobRepository struct {
+	storage *Storage
+	author  string // Автор изменений песен, который записывается в историю изменений
+}
+
+// Метод, возвращающий репозиторий, изменения песен через который записываются в историю от имени author
+func (r *JobRepository) WithAuthor(author string) JobStore {
+	return &JobRepository{storage: r.storage, author: author}
+}
+
+// Колонки задания, извлекаемые из БД (порядок соответствует scanJob)
+const jobColumns = `id, song_id, status, attempts, last_error, run_at, created_at, updated_at`
+
+// Функция, считывающая задание из строки результата запроса
+func scanJob(row scanner) (*models.Job, error) {
+	job := models.Job{}
+
+	err := row.Scan(&job.ID, &job.SongID, &job.Status, &job.Attempts, &job.LastError, &job.RunAt, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Метод для добавления песни вместе с заданием на заполнение ее информации (песня без задания не должна появиться в БД)
+func (r *JobRepository) EnqueueSong(song *models.Song) (*models.Job, error) {
+	var job *models.Job
+
+	err := r.storage.inTxAs(r.author, func(tx *sql.Tx) error {
+		err := addSong(tx, song)
+		if err != nil {
+			return err
+		}
+
+		job, err = scanJob(tx.QueryRow(fmt.Sprintf(`INSERT INTO enrichment_jobs (song_id) VALUES ($1) RETURNING %s`, jobColumns), song.ID))
+		return err
+	})
+
+	return job, err
+}
+
+// Метод для получения задания из БД по его идентификатору
+func (r *JobRepository) GetJob(id int64) (*models.Job, error) {
+	return scanJob(r.storage.db.QueryRow(fmt.Sprintf(`SELECT %s FROM enrichment_jobs WHERE id=$1`, jobColumns), id))
+}
+
+// Метод для получения заданий из БД (сначала новые)
+func (r *JobRepository) GetJobs(status models.JobStatus, offset, limit int) ([]*models.Job, error) {
+	query := fmt.Sprintf(`SELECT %s FROM enrichment_jobs WHERE $1='' OR status=$1 ORDER BY id DESC OFFSET %d LIMIT %d`, jobColumns, offset, limit)
+
+	res, err := r.storage.db.Query(query, string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	jobs := make([]*models.Job, 0)
+
+	for res.Next() {
+		job, err := scanJob(res)
+		if err != nil {
+			continue
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// Метод, забирающий готовое к выполнению задание (задания, которые уже забрали другие воркеры, пропускаются; задание упавшего воркера забирается снова после окончания аренды)
+func (r *JobRepository) ClaimJob(lease time.Duration) (*models.Job, error) {
+	query := fmt.Sprintf(`UPDATE enrichment_jobs SET status='running', attempts=attempts+1, locked_until=now()+make_interval(secs => $1), updated_at=now()
+		WHERE id = (
+			SELECT id FROM enrichment_jobs
+			WHERE (status='pending' AND run_at<=now()) OR (status='running' AND locked_until<now())
+			ORDER BY run_at, id LIMIT 1 FOR UPDATE SKIP LOCKED
+		) RETURNING %s`, jobColumns)
+
+	return scanJob(r.storage.db.QueryRow(query, lease.Seconds()))
+}
+
+// Метод, сохраняющий информацию о песне и завершающий задание (в одной транзакции; песня изменяется, только если ее версия равна song.Version)
+func (r *JobRepository) CompleteJob(job *models.Job, song *models.Song) error {
+	return r.storage.inTxAs(r.author, func(tx *sql.Tx) error {
+		err := updateSongInfo(tx, song)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(`UPDATE enrichment_jobs SET status='done', last_error='', locked_until=NULL, updated_at=now() WHERE id=$1 AND status='running' AND attempts=$2`, job.ID, job.Attempts)
+		if err != nil {
+			return err
+		}
+
+		return checkLease(res)
+	})
+}
+
+// Метод, возвращающий задание в очередь для повторной попытки
+func (r *JobRepository) RescheduleJob(job *models.Job, lastError string, runAt time.Time) error {
+	res, err := r.storage.db.Exec(`UPDATE enrichment_jobs SET status='pending', last_error=$1, run_at=$2, locked_until=NULL, updated_at=now() WHERE id=$3 AND status='running' AND attempts=$4`, lastError, runAt, job.ID, job.Attempts)
+	if err != nil {
+		return err
+	}
+
+	return checkLease(res)
+}
+
+// Метод, переводящий задание в dead
+func (r *JobRepository) BuryJob(job *models.Job, lastError string) error {
+	res, err := r.storage.db.Exec(`UPDATE enrichment_jobs SET status='dead', last_error=$1, locked_until=NULL, updated_at=now() WHERE id=$2 AND status='running' AND attempts=$3`, lastError, job.ID, job.Attempts)
+	if err != nil {
+		return err
+	}
+
+	return checkLease(res)
+}
+
+// Функция, проверяющая, что задание изменено воркером, который его арендовал (ни одной измененной строки - задание забрал другой воркер или его удалили)
+func checkLease(res sql.Result) error {
+	err := checkAffected(res)
+	if err == sql.ErrNoRows {
+		return ErrLeaseLost
+	}
+
+	return err
+}
+
+// Метод, возвращающий dead задание в очередь (счетчик попыток сбрасывается; sql.ErrNoRows, если задание не найдено)
+func (r *JobRepository) RetryJob(id int64) error {
+	res, err := r.storage.db.Exec(`UPDATE enrichment_jobs SET status='pending', attempts=0, run_at=now(), updated_at=now() WHERE id=$1 AND status='dead'`, id)
+	if err != nil {
+		return err
+	}
+	err = checkAffected(res)
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	// Различаем несуществующее задание и задание, которое еще не dead
+	var exists bool
+	err = r.storage.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM enrichment_jobs WHERE id=$1)`, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrWrongState
+	}
+
+	return sql.ErrNoRows
+}
@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"slices"
+)
+
+// Сущность модельного репозитория альбомов для хранилища в памяти
+type MemoryAlbumRepository struct {
+	storage *MemoryStorage // Хранит в себе хранилище, т.к. общение с ним реализовано посредством репозитория
+}
+
+// Метод для получения альбомов из хранилища с учетом пагинации (без списка треков)
+func (r *MemoryAlbumRepository) GetAlbums(offset, limit int) ([]*models.Album, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	albums := make([]*models.Album, 0)
+	for _, album := range paginate(r.storage.albums, offset, limit) {
+		albums = append(albums, r.storage.albumOut(album))
+	}
+
+	return albums, nil
+}
+
+// Метод для получения альбома из хранилища по идентификатору (вместе со списком треков)
+func (r *MemoryAlbumRepository) GetAlbum(id int64) (*models.Album, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	stored := r.storage.albumByID(id)
+	if stored == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	album := r.storage.albumOut(stored)
+	songs := make([]*models.Song, 0)
+	for _, record := range r.storage.songs {
+		if record.song.AlbumID != nil && *record.song.AlbumID == id {
+			songs = append(songs, &record.song)
+		}
+	}
+	slices.SortStableFunc(songs, compareTracks)
+
+	album.Tracks = make([]*models.AlbumTrack, 0, len(songs))
+	for _, song := range songs {
+		album.Tracks = append(album.Tracks, &models.AlbumTrack{
+			SongID:      song.ID,
+			Song:        song.Song,
+			DiscNumber:  intOrZero(song.DiscNumber),
+			TrackNumber: intOrZero(song.TrackNumber),
+		})
+	}
+
+	return album, nil
+}
+
+// Метод для добавления альбома в хранилище (исполнитель создается, если его еще нет; песни из списка треков привязываются к альбому)
+func (r *MemoryAlbumRepository) AddAlbum(album *models.Album) error {
+	releaseDate, err := normalizeReleaseDate(album.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if r.storage.albumByTitle(album.Group, album.Title) != nil {
+		return ErrAlreadyExists
+	}
+	err = r.storage.checkAlbumTracks(album.Tracks)
+	if err != nil {
+		return err
+	}
+
+	r.storage.lastAlbumID++
+	album.ID = r.storage.lastAlbumID
+	album.ArtistID = r.storage.upsertArtist(album.Group).ID
+
+	r.storage.albums = append(r.storage.albums, &models.Album{ID: album.ID, ArtistID: album.ArtistID, Title: album.Title, ReleaseDate: releaseDate, CoverLink: album.CoverLink})
+	r.storage.setAlbumTracks(album.ID, album.Tracks)
+
+	return nil
+}
+
+// Метод для изменения альбома в хранилище (список треков заменяется, только если album.Tracks не nil; sql.ErrNoRows, если альбом не найден)
+func (r *MemoryAlbumRepository) UpdateAlbum(album *models.Album) error {
+	releaseDate, err := normalizeReleaseDate(album.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	stored := r.storage.albumByID(album.ID)
+	if stored == nil {
+		return sql.ErrNoRows
+	}
+
+	if other := r.storage.albumByTitle(album.Group, album.Title); other != nil && other.ID != album.ID {
+		return ErrAlreadyExists
+	}
+	if album.Tracks != nil {
+		err := r.storage.checkAlbumTracks(album.Tracks)
+		if err != nil {
+			return err
+		}
+	}
+
+	album.ArtistID = r.storage.upsertArtist(album.Group).ID
+	stored.ArtistID = album.ArtistID
+	stored.Title = album.Title
+	stored.ReleaseDate = releaseDate
+	stored.CoverLink = album.CoverLink
+
+	if album.Tracks != nil {
+		r.storage.clearAlbumTracks(album.ID)
+		r.storage.setAlbumTracks(album.ID, album.Tracks)
+	}
+
+	return nil
+}
+
+// Метод для удаления альбома из хранилища (песни альбома остаются, но теряют ссылку на него; sql.ErrNoRows, если альбом не найден)
+func (r *MemoryAlbumRepository) DeleteAlbum(id int64) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if r.storage.albumByID(id) == nil {
+		return sql.ErrNoRows
+	}
+
+	r.storage.clearAlbumTracks(id)
+	// Песни в корзине тоже теряют ссылку на альбом (как ON DELETE SET NULL), но в историю это не попадает
+	for _, record := range r.storage.trash {
+		if record.song.AlbumID != nil && *record.song.AlbumID == id {
+			record.song.AlbumID, record.song.DiscNumber, record.song.TrackNumber = nil, nil, nil
+			record.song.Version++
+		}
+	}
+	r.storage.albums = slices.DeleteFunc(r.storage.albums, func(album *models.Album) bool {
+		return album.ID == id
+	})
+
+	return nil
+}
+
+// Метод для поиска альбома по идентификатору (вызывается под блокировкой)
+func (storage *MemoryStorage) albumByID(id int64) *models.Album {
+	for _, album := range storage.albums {
+		if album.ID == id {
+			return album
+		}
+	}
+
+	return nil
+}
+
+// Метод для поиска альбома исполнителя по названиям исполнителя и альбома (вызывается под блокировкой)
+func (storage *MemoryStorage) albumByTitle(group, title string) *models.Album {
+	artist := storage.artistByName(group)
+	if artist == nil {
+		return nil
+	}
+
+	for _, album := range storage.albums {
+		if album.ArtistID == artist.ID && album.Title == title {
+			return album
+		}
+	}
+
+	return nil
+}
+
+// Метод, возвращающий копию альбома вместе с названием его исполнителя (вызывается под блокировкой)
+func (storage *MemoryStorage) albumOut(album *models.Album) *models.Album {
+	albumCopy := *album
+	albumCopy.Tracks = nil
+	if artist := storage.artistByID(album.ArtistID); artist != nil {
+		albumCopy.Group = artist.Name
+	}
+
+	return &albumCopy
+}
+
+// Метод, проверяющий, что треки можно привязать к альбому (песни существуют, номера не повторяются; вызывается под блокировкой)
+func (storage *MemoryStorage) checkAlbumTracks(tracks []*models.AlbumTrack) error {
+	type position struct{ disc, track int }
+	used := make(map[position]bool)
+
+	for _, track := range tracks {
+		if storage.songByID(track.SongID) == nil {
+			return ErrBrokenReference
+		}
+		if used[position{track.DiscNumber, track.TrackNumber}] {
+			return ErrAlreadyExists
+		}
+		used[position{track.DiscNumber, track.TrackNumber}] = true
+	}
+
+	return nil
+}
+
+// Метод, привязывающий песни к альбому с заданными номерами дисков и треков (вызывается под блокировкой)
+func (storage *MemoryStorage) setAlbumTracks(albumID int64, tracks []*models.AlbumTrack) {
+	for _, track := range tracks {
+		record := storage.songByID(track.SongID)
+		old := storage.songOut(record)
+		id, disc, number := albumID, track.DiscNumber, track.TrackNumber
+		record.song.AlbumID, record.song.DiscNumber, record.song.TrackNumber = &id, &disc, &number
+		record.song.Version++
+		storage.addRevision(old, storage.songOut(record), "")
+	}
+}
+
+// Метод, отвязывающий от альбома все его песни (вызывается под блокировкой)
+func (storage *MemoryStorage) clearAlbumTracks(albumID int64) {
+	for _, record := range storage.songs {
+		if record.song.AlbumID != nil && *record.song.AlbumID == albumID {
+			old := storage.songOut(record)
+			record.song.AlbumID, record.song.DiscNumber, record.song.TrackNumber = nil, nil, nil
+			record.song.Version++
+			storage.addRevision(old, storage.songOut(record), "")
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"slices"
+)
+
+// Сущность модельного репозитория исполнителей для хранилища в памяти
+type MemoryArtistRepository struct {
+	storage *MemoryStorage // Хранит в себе хранилище, т.к. общение с ним реализовано посредством репозитория
+}
+
+// Метод для получения исполнителей из хранилища с учетом пагинации
+func (r *MemoryArtistRepository) GetArtists(offset, limit int) ([]*models.Artist, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	artists := make([]*models.Artist, 0)
+	for _, artist := range paginate(r.storage.artists, offset, limit) {
+		artists = append(artists, copyArtist(artist))
+	}
+
+	return artists, nil
+}
+
+// Метод для получения исполнителя из хранилища по идентификатору
+func (r *MemoryArtistRepository) GetArtist(id int64) (*models.Artist, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	artist := r.storage.artistByID(id)
+	if artist == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return copyArtist(artist), nil
+}
+
+// Метод для добавления исполнителя в хранилище (ErrAlreadyExists, если исполнитель с таким же ключом названия уже есть)
+func (r *MemoryArtistRepository) AddArtist(artist *models.Artist) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	normalizeArtist(artist)
+	if r.storage.artistByName(artist.Name) != nil {
+		return ErrAlreadyExists
+	}
+
+	r.storage.lastArtistID++
+	artist.ID = r.storage.lastArtistID
+	r.storage.artists = append(r.storage.artists, copyArtist(artist))
+
+	return nil
+}
+
+// Метод для изменения исполнителя в хранилище (изменение затрагивает все его песни; sql.ErrNoRows, если исполнитель не найден)
+func (r *MemoryArtistRepository) UpdateArtist(artist *models.Artist) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	normalizeArtist(artist)
+	stored := r.storage.artistByID(artist.ID)
+	if stored == nil {
+		return sql.ErrNoRows
+	}
+	if other := r.storage.artistByName(artist.Name); other != nil && other.ID != artist.ID {
+		return ErrAlreadyExists
+	}
+
+	*stored = *copyArtist(artist)
+
+	return nil
+}
+
+// Метод для удаления исполнителя из хранилища (ErrInUse, если у исполнителя есть песни или альбомы; sql.ErrNoRows, если исполнитель не найден)
+func (r *MemoryArtistRepository) DeleteArtist(id int64) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if r.storage.artistByID(id) == nil {
+		return sql.ErrNoRows
+	}
+	// Песни в корзине тоже ссылаются на исполнителя (как внешний ключ в Postgres)
+	for _, record := range slices.Concat(r.storage.songs, r.storage.trash) {
+		if record.song.ArtistID == id {
+			return ErrInUse
+		}
+	}
+	for _, album := range r.storage.albums {
+		if album.ArtistID == id {
+			return ErrInUse
+		}
+	}
+
+	r.storage.artists = slices.DeleteFunc(r.storage.artists, func(artist *models.Artist) bool {
+		return artist.ID == id
+	})
+
+	return nil
+}
+
+// Метод для поиска исполнителя по идентификатору (вызывается под блокировкой)
+func (storage *MemoryStorage) artistByID(id int64) *models.Artist {
+	for _, artist := range storage.artists {
+		if artist.ID == id {
+			return artist
+		}
+	}
+
+	return nil
+}
+
+// Метод для поиска исполнителя по ключу названия (вызывается под блокировкой)
+func (storage *MemoryStorage) artistByName(name string) *models.Artist {
+	key := models.NameKey(name)
+	for _, artist := range storage.artists {
+		if models.NameKey(artist.Name) == key {
+			return artist
+		}
+	}
+
+	return nil
+}
+
+// Метод, возвращающий исполнителя по названию и создающий его, если его еще нет (вызывается под блокировкой)
+func (storage *MemoryStorage) upsertArtist(name string) *models.Artist {
+	if artist := storage.artistByName(name); artist != nil {
+		return artist
+	}
+
+	storage.lastArtistID++
+	artist := &models.Artist{ID: storage.lastArtistID, Name: name}
+	normalizeArtist(artist)
+	storage.artists = append(storage.artists, artist)
+
+	return artist
+}
+
+// Функция, возвращающая копию исполнителя (чтобы хэндлеры не изменяли данные хранилища напрямую)
+func copyArtist(artist *models.Artist) *models.Artist {
+	artistCopy := *artist
+	artistCopy.Members = slices.Clone(artist.Members)
+	if artist.FormedYear != nil {
+		year := *artist.FormedYear
+		artistCopy.FormedYear = &year
+	}
+
+	return &artistCopy
+}
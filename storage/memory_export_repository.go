@@ -0,0 +1,27 @@
+package storage
+
+import "mus_lib/internal/app/models"
+
+// Метод для выгрузки всех песен, удовлетворяющих фильтру (без пагинации)
+func (s *MemorySongRepository) ExportSongs(filter SongFilter, fn func(song *models.Song) error) error {
+	// Песни копируются под блокировкой, а передаются fn уже без нее, чтобы медленный клиент не задерживал изменения хранилища
+	s.storage.mu.RLock()
+	songs := make([]*models.Song, 0)
+	for _, record := range s.storage.songs {
+		song := s.storage.songOut(record)
+		if filter.matches(song) {
+			songs = append(songs, song)
+		}
+	}
+	s.storage.mu.RUnlock()
+	filter.sort(songs)
+
+	for _, song := range songs {
+		err := fn(song)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package storage
+
+import "mus_lib/internal/app/models"
+
+// Метод для добавления песен в хранилище (уже существующие песни пропускаются; идентификаторы и версии добавленных песен записываются в песни)
+func (s *MemorySongRepository) ImportSongs(songs []*models.Song, enrich bool) ([]ImportedSong, error) {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	// Как и в Postgres, песни проверяются до изменений, чтобы некорректная песня не оставила импорт добавленным наполовину
+	for _, song := range songs {
+		_, err := normalizeReleaseDate(song.ReleaseDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]ImportedSong, len(songs))
+	for i, song := range songs {
+		err := s.storage.addSong(song, s.author)
+		if err == ErrAlreadyExists {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		results[i].Created = true
+		if enrich && missingInfo(song) {
+			results[i].JobID = s.storage.addJob(song.ID).job.ID
+		}
+	}
+
+	return results, nil
+}
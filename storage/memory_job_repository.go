@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"time"
+)
+
+// Запись задания в хранилище в памяти
+type jobRecord struct {
+	job         models.Job
+	lockedUntil time.Time // До какого времени задание арендовано воркером
+}
+
+// Сущность модельного репозитория заданий для хранилища в памяти
+type MemoryJobRepository struct {
+	storage *MemoryStorage
+	author  string // Автор изменений песен, который записывается в историю изменений
+}
+
+// Метод, возвращающий репозиторий, изменения песен через который записываются в историю от имени author
+func (r *MemoryJobRepository) WithAuthor(author string) JobStore {
+	return &MemoryJobRepository{storage: r.storage, author: author}
+}
+
+// Метод для добавления песни вместе с заданием на заполнение ее информации
+func (r *MemoryJobRepository) EnqueueSong(song *models.Song) (*models.Job, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	err := r.storage.addSong(song, r.author)
+	if err != nil {
+		return nil, err
+	}
+
+	job := r.storage.addJob(song.ID).job
+	return &job, nil
+}
+
+// Метод, создающий задание на заполнение информации о песне (вызывается под блокировкой на запись)
+func (storage *MemoryStorage) addJob(songID int64) *jobRecord {
+	now := time.Now()
+	storage.lastJobID++
+	record := &jobRecord{job: models.Job{ID: storage.lastJobID, SongID: songID, Status: models.JobPending, RunAt: now, CreatedAt: now, UpdatedAt: now}}
+	storage.jobs = append(storage.jobs, record)
+
+	return record
+}
+
+// Метод для получения задания из хранилища по его идентификатору
+func (r *MemoryJobRepository) GetJob(id int64) (*models.Job, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	record := r.storage.jobByID(id)
+	if record == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	job := record.job
+	return &job, nil
+}
+
+// Метод для получения заданий из хранилища (сначала новые)
+func (r *MemoryJobRepository) GetJobs(status models.JobStatus, offset, limit int) ([]*models.Job, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	jobs := make([]*models.Job, 0)
+	for i := len(r.storage.jobs) - 1; i >= 0; i-- {
+		record := r.storage.jobs[i]
+		if status == "" || record.job.Status == status {
+			job := record.job
+			jobs = append(jobs, &job)
+		}
+	}
+
+	return paginate(jobs, offset, limit), nil
+}
+
+// Метод, забирающий готовое к выполнению задание (задание упавшего воркера забирается снова после окончания аренды)
+func (r *MemoryJobRepository) ClaimJob(lease time.Duration) (*models.Job, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	now := time.Now()
+
+	var claimed *jobRecord
+	for _, record := range r.storage.jobs {
+		ready := (record.job.Status == models.JobPending && !record.job.RunAt.After(now)) ||
+			(record.job.Status == models.JobRunning && record.lockedUntil.Before(now))
+		if ready && (claimed == nil || record.job.RunAt.Before(claimed.job.RunAt)) {
+			claimed = record
+		}
+	}
+	if claimed == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	claimed.job.Status = models.JobRunning
+	claimed.job.Attempts++
+	claimed.job.UpdatedAt = now
+	claimed.lockedUntil = now.Add(lease)
+
+	job := claimed.job
+	return &job, nil
+}
+
+// Метод, сохраняющий информацию о песне и завершающий задание (песня изменяется, только если ее версия равна song.Version)
+func (r *MemoryJobRepository) CompleteJob(job *models.Job, song *models.Song) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	record, err := r.storage.leasedJob(job)
+	if err != nil {
+		return err
+	}
+	err = r.storage.updateSongInfo(song, r.author)
+	if err != nil {
+		return err
+	}
+
+	record.job.Status = models.JobDone
+	record.job.LastError = ""
+	record.job.UpdatedAt = time.Now()
+	record.lockedUntil = time.Time{}
+
+	return nil
+}
+
+// Метод, возвращающий задание в очередь для повторной попытки
+func (r *MemoryJobRepository) RescheduleJob(job *models.Job, lastError string, runAt time.Time) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	record, err := r.storage.leasedJob(job)
+	if err != nil {
+		return err
+	}
+
+	record.job.Status = models.JobPending
+	record.job.LastError = lastError
+	record.job.RunAt = runAt
+	record.job.UpdatedAt = time.Now()
+	record.lockedUntil = time.Time{}
+
+	return nil
+}
+
+// Метод, переводящий задание в dead
+func (r *MemoryJobRepository) BuryJob(job *models.Job, lastError string) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	record, err := r.storage.leasedJob(job)
+	if err != nil {
+		return err
+	}
+
+	record.job.Status = models.JobDead
+	record.job.LastError = lastError
+	record.job.UpdatedAt = time.Now()
+	record.lockedUntil = time.Time{}
+
+	return nil
+}
+
+// Метод, возвращающий dead задание в очередь (счетчик попыток сбрасывается)
+func (r *MemoryJobRepository) RetryJob(id int64) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	record := r.storage.jobByID(id)
+	if record == nil {
+		return sql.ErrNoRows
+	}
+	if record.job.Status != models.JobDead {
+		return ErrWrongState
+	}
+
+	now := time.Now()
+	record.job.Status = models.JobPending
+	record.job.Attempts = 0
+	record.job.RunAt = now
+	record.job.UpdatedAt = now
+
+	return nil
+}
+
+// Метод, возвращающий задание, если его все еще выполняет воркер, который его арендовал (ErrLeaseLost, если задание забрал другой воркер или его удалили; вызывается под блокировкой)
+func (storage *MemoryStorage) leasedJob(job *models.Job) (*jobRecord, error) {
+	record := storage.jobByID(job.ID)
+	if record == nil || record.job.Status != models.JobRunning || record.job.Attempts != job.Attempts {
+		return nil, ErrLeaseLost
+	}
+
+	return record, nil
+}
+
+// Метод, возвращающий задание по идентификатору (вызывается под блокировкой)
+func (storage *MemoryStorage) jobByID(id int64) *jobRecord {
+	for _, record := range storage.jobs {
+		if record.job.ID == id {
+			return record
+		}
+	}
+
+	return nil
+}
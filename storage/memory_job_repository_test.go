@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"mus_lib/internal/app/models"
+	"testing"
+	"time"
+)
+
+// Функция, добавляющая песню с заданием и возвращающая задание, забранное воркером
+func claimTestJob(t *testing.T, store *MemoryStorage, song string) *models.Job {
+	t.Helper()
+
+	_, err := store.Job().EnqueueSong(&models.Song{Group: "Muse", Song: song})
+	if err != nil {
+		t.Fatalf("EnqueueSong() error = %v", err)
+	}
+	job, err := store.Job().ClaimJob(time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimJob() error = %v", err)
+	}
+
+	return job
+}
+
+func TestMemoryCompleteJob(t *testing.T) {
+	store := NewMemory()
+	job := claimTestJob(t, store, "Uprising")
+
+	old, err := store.Song().GetSong(job.SongID)
+	if err != nil {
+		t.Fatalf("GetSong() error = %v", err)
+	}
+	filled := *old
+	filled.ReleaseDate, filled.Text, filled.Link = "1991", []string{"one"}, "https://example.com"
+
+	// Песню изменили после того, как ее прочитали
+	changed := *old
+	changed.Link = "https://example.com/other"
+	if err := store.Song().UpdateSongInfo(&changed); err != nil {
+		t.Fatalf("UpdateSongInfo() error = %v", err)
+	}
+	if err := store.Job().CompleteJob(job, &filled); !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("CompleteJob() with changed song error = %v, want %v", err, ErrStaleVersion)
+	}
+
+	filled.Version = changed.Version
+	if err := store.Job().CompleteJob(job, &filled); err != nil {
+		t.Fatalf("CompleteJob() error = %v", err)
+	}
+	if song, _ := store.Song().GetSong(job.SongID); song.ReleaseDate != "1991" || song.Link != "https://example.com" {
+		t.Errorf("song = %+v, want filled info", song)
+	}
+	if stored, _ := store.Job().GetJob(job.ID); stored.Status != models.JobDone {
+		t.Errorf("job status = %s, want %s", stored.Status, models.JobDone)
+	}
+
+	// Завершенное задание больше не арендовано
+	if err := store.Job().CompleteJob(job, &filled); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("CompleteJob() of done job error = %v, want %v", err, ErrLeaseLost)
+	}
+
+	// Песню удалили, пока задание было арендовано
+	next := claimTestJob(t, store, "Starlight")
+	filled.ID = 100
+	if err := store.Job().CompleteJob(next, &filled); err != sql.ErrNoRows {
+		t.Errorf("CompleteJob() of missing song error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestMemoryJobLease(t *testing.T) {
+	store := NewMemory()
+	_, err := store.Job().EnqueueSong(&models.Song{Group: "Muse", Song: "Uprising"})
+	if err != nil {
+		t.Fatalf("EnqueueSong() error = %v", err)
+	}
+
+	// Аренда первого воркера истекла, и задание забрал второй
+	first, err := store.Job().ClaimJob(-time.Second)
+	if err != nil {
+		t.Fatalf("ClaimJob() error = %v", err)
+	}
+	second, err := store.Job().ClaimJob(time.Minute)
+	if err != nil || second.ID != first.ID || second.Attempts != first.Attempts+1 {
+		t.Fatalf("ClaimJob() = %+v, %v; want the same job with next attempt", second, err)
+	}
+
+	if err := store.Job().RescheduleJob(first, "timeout", time.Now()); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("RescheduleJob() by first worker error = %v, want %v", err, ErrLeaseLost)
+	}
+	if err := store.Job().BuryJob(first, "timeout"); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("BuryJob() by first worker error = %v, want %v", err, ErrLeaseLost)
+	}
+	if stored, _ := store.Job().GetJob(second.ID); stored.Status != models.JobRunning {
+		t.Errorf("job status = %s, want %s", stored.Status, models.JobRunning)
+	}
+
+	if err := store.Job().RescheduleJob(second, "timeout", time.Now()); err != nil {
+		t.Errorf("RescheduleJob() by second worker error = %v", err)
+	}
+	if stored, _ := store.Job().GetJob(second.ID); stored.Status != models.JobPending || stored.LastError != "timeout" {
+		t.Errorf("job = %+v, want pending job with last error", stored)
+	}
+}
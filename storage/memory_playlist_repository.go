@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"slices"
+	"time"
+)
+
+// Запись плейлиста в хранилище в памяти (записи плейлиста хранятся как идентификаторы песен по порядку)
+type playlistRecord struct {
+	playlist models.Playlist
+	songIDs  []int64
+}
+
+// Сущность модельного репозитория плейлистов для хранилища в памяти
+type MemoryPlaylistRepository struct {
+	storage *MemoryStorage // Хранит в себе хранилище, т.к. общение с ним реализовано посредством репозитория
+}
+
+// Метод для получения публичных плейлистов и плейлистов viewer из хранилища с учетом пагинации (без записей)
+func (r *MemoryPlaylistRepository) GetPlaylists(viewer string, offset, limit int) ([]*models.Playlist, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	playlists := make([]*models.Playlist, 0)
+	for _, record := range r.storage.playlists {
+		if record.playlist.Public || record.playlist.Owner == viewer {
+			playlist := record.playlist
+			playlists = append(playlists, &playlist)
+		}
+	}
+
+	return paginate(playlists, offset, limit), nil
+}
+
+// Метод для получения плейлиста из хранилища по идентификатору (вместе с записями по порядку)
+func (r *MemoryPlaylistRepository) GetPlaylist(id int64) (*models.Playlist, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	record := r.storage.playlistByID(id)
+	if record == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	playlist := record.playlist
+	playlist.Entries = make([]*models.PlaylistEntry, 0, len(record.songIDs))
+	for _, songID := range record.songIDs {
+		// Песни в корзине остаются в плейлисте, чтобы вернуться на свое место вместе с песней
+		song := r.storage.songByID(songID)
+		if song == nil {
+			song = r.storage.trashByID(songID)
+		}
+		if song == nil {
+			continue
+		}
+		out := r.storage.songOut(song)
+
+		playlist.Entries = append(playlist.Entries, &models.PlaylistEntry{
+			Position:   len(playlist.Entries),
+			SongID:     songID,
+			Group:      out.Group,
+			Song:       out.Song,
+			Link:       out.Link,
+			VerseCount: len(out.Text),
+			Deleted:    out.DeletedAt != nil,
+		})
+	}
+
+	return &playlist, nil
+}
+
+// Метод для добавления плейлиста в хранилище
+func (r *MemoryPlaylistRepository) AddPlaylist(playlist *models.Playlist) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	r.storage.lastPlaylistID++
+	playlist.ID = r.storage.lastPlaylistID
+	playlist.CreatedAt = time.Now()
+	playlist.UpdatedAt = playlist.CreatedAt
+
+	stored := *playlist
+	stored.Entries = nil
+	r.storage.playlists = append(r.storage.playlists, &playlistRecord{playlist: stored})
+
+	return nil
+}
+
+// Метод для изменения названия, описания и видимости плейлиста (sql.ErrNoRows, если плейлист не найден)
+func (r *MemoryPlaylistRepository) UpdatePlaylist(playlist *models.Playlist) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	record := r.storage.playlistByID(playlist.ID)
+	if record == nil {
+		return sql.ErrNoRows
+	}
+
+	record.playlist.Name = playlist.Name
+	record.playlist.Description = playlist.Description
+	record.playlist.Public = playlist.Public
+	record.playlist.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Метод для удаления плейлиста вместе с записями (sql.ErrNoRows, если плейлист не найден)
+func (r *MemoryPlaylistRepository) DeletePlaylist(id int64) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if r.storage.playlistByID(id) == nil {
+		return sql.ErrNoRows
+	}
+
+	r.storage.playlists = slices.DeleteFunc(r.storage.playlists, func(record *playlistRecord) bool {
+		return record.playlist.ID == id
+	})
+
+	return nil
+}
+
+// Метод, вставляющий песню перед записью position (в конец, если position < 0) и возвращающий номер вставленной записи
+func (r *MemoryPlaylistRepository) AddEntry(id, songID int64, position int) (int, error) {
+	err := r.editEntries(id, func(songIDs []int64) ([]int64, error) {
+		if position < 0 {
+			position = len(songIDs)
+		}
+		if position > len(songIDs) {
+			return nil, ErrOutOfRange
+		}
+		if r.storage.songByID(songID) == nil {
+			return nil, ErrBrokenReference
+		}
+
+		return slices.Insert(songIDs, position, songID), nil
+	})
+
+	return position, err
+}
+
+// Метод, удаляющий запись position
+func (r *MemoryPlaylistRepository) DeleteEntry(id int64, position int) error {
+	return r.editEntries(id, func(songIDs []int64) ([]int64, error) {
+		if position < 0 || position >= len(songIDs) {
+			return nil, ErrOutOfRange
+		}
+
+		return slices.Delete(songIDs, position, position+1), nil
+	})
+}
+
+// Метод, перемещающий запись from так, чтобы она получила номер to
+func (r *MemoryPlaylistRepository) MoveEntry(id int64, from, to int) error {
+	return r.editEntries(id, func(songIDs []int64) ([]int64, error) {
+		return moveEntry(songIDs, from, to)
+	})
+}
+
+// Метод, передающий в edit копию записей плейлиста и сохраняющий записи, которые вернул edit (sql.ErrNoRows, если плейлист не найден)
+func (r *MemoryPlaylistRepository) editEntries(id int64, edit func(songIDs []int64) ([]int64, error)) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	record := r.storage.playlistByID(id)
+	if record == nil {
+		return sql.ErrNoRows
+	}
+
+	songIDs, err := edit(slices.Clone(record.songIDs))
+	if err != nil {
+		return err
+	}
+	record.songIDs = songIDs
+	record.playlist.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Метод для поиска плейлиста по идентификатору (вызывается под блокировкой)
+func (storage *MemoryStorage) playlistByID(id int64) *playlistRecord {
+	for _, record := range storage.playlists {
+		if record.playlist.ID == id {
+			return record
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"errors"
+	"mus_lib/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Функция, возвращающая идентификаторы песен плейлиста по порядку
+func playlistSongIDs(t *testing.T, store *MemoryStorage, id int64) []int64 {
+	t.Helper()
+
+	playlist, err := store.Playlist().GetPlaylist(id)
+	if err != nil {
+		t.Fatalf("GetPlaylist() error = %v", err)
+	}
+
+	songIDs := make([]int64, 0, len(playlist.Entries))
+	for _, entry := range playlist.Entries {
+		songIDs = append(songIDs, entry.SongID)
+	}
+	return songIDs
+}
+
+func TestMoveEntry(t *testing.T) {
+	tests := []struct {
+		from    int
+		to      int
+		want    []int64
+		wantErr error
+	}{
+		{0, 2, []int64{2, 3, 1}, nil},
+		{2, 0, []int64{3, 1, 2}, nil},
+		{1, 1, []int64{1, 2, 3}, nil},
+		{3, 0, nil, ErrOutOfRange},
+		{0, 3, nil, ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		got, err := moveEntry([]int64{1, 2, 3}, tt.from, tt.to)
+		if !errors.Is(err, tt.wantErr) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("moveEntry(%d, %d) = %v, %v; want %v, %v", tt.from, tt.to, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMemoryPlaylistEntries(t *testing.T) {
+	store, song := newMemoryWithSong(t)
+	other := &models.Song{Group: "Queen", Song: "Bohemian Rhapsody"}
+	if err := store.Song().AddSong(other); err != nil {
+		t.Fatalf("AddSong() error = %v", err)
+	}
+
+	playlist := &models.Playlist{Name: "Morning", Owner: "listener"}
+	if err := store.Playlist().AddPlaylist(playlist); err != nil {
+		t.Fatalf("AddPlaylist() error = %v", err)
+	}
+	for _, songID := range []int64{song.ID, other.ID, song.ID} {
+		if _, err := store.Playlist().AddEntry(playlist.ID, songID, -1); err != nil {
+			t.Fatalf("AddEntry() error = %v", err)
+		}
+	}
+	if _, err := store.Playlist().AddEntry(playlist.ID, other.ID+1, -1); err != ErrBrokenReference {
+		t.Errorf("AddEntry() of missing song error = %v, want %v", err, ErrBrokenReference)
+	}
+
+	// Песня из корзины остается в плейлисте, а после окончательного удаления пропадает из него
+	if err := store.Song().DeleteSong(song.ID, 0); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+	if got := playlistSongIDs(t, store, playlist.ID); !reflect.DeepEqual(got, []int64{song.ID, other.ID, song.ID}) {
+		t.Errorf("songs with deleted song = %v", got)
+	}
+	if _, err := store.Song().PurgeDeletedSongs(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("PurgeDeletedSongs() error = %v", err)
+	}
+	if got := playlistSongIDs(t, store, playlist.ID); !reflect.DeepEqual(got, []int64{other.ID}) {
+		t.Errorf("songs after purge = %v, want [%d]", got, other.ID)
+	}
+
+	// Чужие непубличные плейлисты не возвращаются
+	playlists, err := store.Playlist().GetPlaylists("stranger", 0, 10)
+	if err != nil || len(playlists) != 0 {
+		t.Errorf("GetPlaylists() of stranger = %v, %v; want none", playlists, err)
+	}
+}
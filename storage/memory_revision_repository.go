@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"time"
+)
+
+// Сущность модельного репозитория истории изменений песен для хранилища в памяти
+type MemoryRevisionRepository struct {
+	storage *MemoryStorage
+}
+
+// Метод для получения ревизий песни из хранилища (последние первыми)
+func (r *MemoryRevisionRepository) GetRevisions(songID int64, offset, limit int) ([]*models.SongRevision, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	revisions := make([]*models.SongRevision, 0)
+	for i := len(r.storage.revisions) - 1; i >= 0; i-- {
+		if revision := r.storage.revisions[i]; revision.SongID == songID {
+			revisions = append(revisions, copyRevision(revision))
+		}
+	}
+
+	return paginate(revisions, offset, limit), nil
+}
+
+// Метод для получения ревизии песни из хранилища (sql.ErrNoRows, если ее нет)
+func (r *MemoryRevisionRepository) GetRevision(songID, revision int64) (*models.SongRevision, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	for _, stored := range r.storage.revisions {
+		if stored.SongID == songID && stored.Revision == revision {
+			return copyRevision(stored), nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+// Метод, записывающий изменение песни в историю (повторяет триггер Postgres: номер ревизии - версия песни после изменения, у удаления - следующая версия; вызывается под блокировкой на запись)
+func (storage *MemoryStorage) addRevision(before, after *models.Song, author string) {
+	revision := &models.SongRevision{Author: author, ChangedAt: time.Now(), Old: before, New: after}
+	switch {
+	case before == nil:
+		revision.SongID, revision.Revision, revision.Action = after.ID, after.Version, models.RevisionCreate
+	case after == nil:
+		revision.SongID, revision.Revision, revision.Action = before.ID, before.Version+1, models.RevisionDelete
+	default:
+		revision.SongID, revision.Revision, revision.Action = after.ID, after.Version, models.RevisionUpdate
+	}
+
+	storage.revisions = append(storage.revisions, revision)
+}
+
+// Метод, возвращающий номер последней ревизии песни (0, если истории нет; вызывается под блокировкой)
+func (storage *MemoryStorage) lastRevision(songID int64) int64 {
+	var last int64
+	for _, revision := range storage.revisions {
+		if revision.SongID == songID {
+			last = max(last, revision.Revision)
+		}
+	}
+
+	return last
+}
+
+// Функция, возвращающая копию ревизии (чтобы хэндлеры не изменяли данные хранилища напрямую)
+func copyRevision(revision *models.SongRevision) *models.SongRevision {
+	revisionCopy := *revision
+	if revision.Old != nil {
+		revisionCopy.Old = copySong(revision.Old)
+	}
+	if revision.New != nil {
+		revisionCopy.New = copySong(revision.New)
+	}
+
+	return &revisionCopy
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"testing"
+	"time"
+)
+
+func TestMemoryRevisions(t *testing.T) {
+	store := NewMemory()
+	songs := store.Song().WithAuthor("alice")
+
+	song := &models.Song{Group: "Muse", Song: "Uprising", Text: []string{"one"}}
+	if err := songs.AddSong(song); err != nil {
+		t.Fatalf("AddSong() error = %v", err)
+	}
+	if _, err := songs.ReplaceVerse(song.ID, 0, 0, "ONE"); err != nil {
+		t.Fatalf("ReplaceVerse() error = %v", err)
+	}
+	if err := store.Song().DeleteSong(song.ID, 0); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	// Номер ревизии удаления - следующая версия песни, а автор берется из репозитория, через который изменили песню
+	revisions, err := store.Revision().GetRevisions(song.ID, 0, 10)
+	if err != nil || len(revisions) != 3 {
+		t.Fatalf("GetRevisions() = %v, %v; want 3 revisions", revisions, err)
+	}
+	want := []struct {
+		revision int64
+		action   models.RevisionAction
+		author   string
+	}{
+		{3, models.RevisionDelete, ""},
+		{2, models.RevisionUpdate, "alice"},
+		{1, models.RevisionCreate, "alice"},
+	}
+	for i, tt := range want {
+		if got := revisions[i]; got.Revision != tt.revision || got.Action != tt.action || got.Author != tt.author {
+			t.Errorf("revision %d = %d %s %q, want %d %s %q", i, got.Revision, got.Action, got.Author, tt.revision, tt.action, tt.author)
+		}
+	}
+	if update := revisions[1]; update.Old.Text[0] != "one" || update.New.Text[0] != "ONE" {
+		t.Errorf("update revision = %v -> %v, want verse change", update.Old.Text, update.New.Text)
+	}
+
+	// Песню из корзины нельзя добавить заново, а окончательно удаленная возвращается с прежним id и версией после последней ревизии
+	restored := revisions[1].New
+	if err := songs.RestoreSong(restored); err != ErrWrongState {
+		t.Errorf("RestoreSong() of trashed song error = %v, want %v", err, ErrWrongState)
+	}
+	if _, err := store.Song().PurgeDeletedSongs(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("PurgeDeletedSongs() error = %v", err)
+	}
+	if err := songs.RestoreSong(restored); err != nil {
+		t.Fatalf("RestoreSong() error = %v", err)
+	}
+	if restored.Version != 4 {
+		t.Errorf("restored version = %d, want 4", restored.Version)
+	}
+	if err := songs.RestoreSong(restored); err != ErrAlreadyExists {
+		t.Errorf("RestoreSong() of existing song error = %v, want %v", err, ErrAlreadyExists)
+	}
+	if _, err := store.Revision().GetRevision(song.ID, 5); err != sql.ErrNoRows {
+		t.Errorf("GetRevision() of missing revision error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"time"
+)
+
+// Сущность модельного репозитория кэша ответов стороннего API для хранилища в памяти
+type MemorySongInfoCacheRepository struct {
+	storage *MemoryStorage
+}
+
+// Метод, возвращающий неистекший ответ стороннего API по ключу (sql.ErrNoRows, если его нет)
+func (r *MemorySongInfoCacheRepository) GetCachedSongInfo(groupKey, songKey string) (*models.SongInfoCacheEntry, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	entry, ok := r.storage.songInfo[[2]string{groupKey, songKey}]
+	if !ok || !entry.ExpiresAt.After(time.Now()) {
+		return nil, sql.ErrNoRows
+	}
+
+	out := *entry
+	return &out, nil
+}
+
+// Метод, сохраняющий ответ стороннего API (если ответ по этому ключу уже есть, он заменяется)
+func (r *MemorySongInfoCacheRepository) PutCachedSongInfo(entry *models.SongInfoCacheEntry) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if r.storage.songInfo == nil {
+		r.storage.songInfo = make(map[[2]string]*models.SongInfoCacheEntry)
+	}
+	in := *entry
+	r.storage.songInfo[[2]string{entry.GroupKey, entry.SongKey}] = &in
+
+	return nil
+}
+
+// Метод, удаляющий истекшие ответы стороннего API
+func (r *MemorySongInfoCacheRepository) DeleteExpiredSongInfo() (int64, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	var deleted int64
+	now := time.Now()
+	for key, entry := range r.storage.songInfo {
+		if !entry.ExpiresAt.After(now) {
+			delete(r.storage.songInfo, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,454 @@
+package storage
+
+import (
+	"cmp"
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Запись песни в хранилище в памяти (название исполнителя берется из записи исполнителя по song.ArtistID)
+type songRecord struct {
+	song models.Song
+}
+
+// Сущность модельного репозитория для хранилища в памяти
+type MemorySongRepository struct {
+	storage *MemoryStorage // Хранит в себе хранилище, т.к. общение с ним реализовано посредством репозитория
+	author  string         // Автор изменений, который записывается в историю изменений песен
+}
+
+// Метод, возвращающий репозиторий, изменения через который записываются в историю от имени author
+func (s *MemorySongRepository) WithAuthor(author string) SongStore {
+	return &MemorySongRepository{storage: s.storage, author: author}
+}
+
+// Метод для получения всех песен из хранилища
+func (s *MemorySongRepository) GetSongs(filter SongFilter) ([]*models.Song, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	songs := make([]*models.Song, 0)
+	for _, record := range s.storage.songs {
+		song := s.storage.songOut(record)
+		if filter.matches(song) {
+			songs = append(songs, song)
+		}
+	}
+	filter.sort(songs)
+
+	return paginate(songs, filter.Offset, filter.Limit), nil
+}
+
+// Метод для полнотекстового поиска песен по тексту (повторяет поведение Postgres: совпадение ищется в каждом куплете отдельно)
+func (s *MemorySongRepository) SearchSongs(query string, offset, limit int) ([]*models.SongSearchResult, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	textQuery := parseTextQuery(query)
+
+	results := make([]*models.SongSearchResult, 0)
+	for _, record := range s.storage.songs {
+		words := textWords(strings.Join(record.song.Text, "\n"))
+		if !textQuery.matches(words) {
+			continue
+		}
+
+		var matches []models.VerseMatch
+		for i, verse := range record.song.Text {
+			if textQuery.matches(textWords(verse)) {
+				matches = append(matches, models.VerseMatch{Verse: i, Snippet: textQuery.highlight(verse)})
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		results = append(results, &models.SongSearchResult{Song: s.storage.songOut(record), Rank: textQuery.rank(words), Matches: matches})
+	}
+	slices.SortStableFunc(results, func(a, b *models.SongSearchResult) int {
+		return cmp.Or(cmp.Compare(b.Rank, a.Rank), cmp.Compare(a.Song.ID, b.Song.ID))
+	})
+
+	return paginate(results, offset, limit), nil
+}
+
+// Метод для нечеткого поиска песен по названию исполнителя или песни (порог сходства такой же, как у pg_trgm по умолчанию)
+func (s *MemorySongRepository) SuggestSongs(query string, offset, limit int) ([]*models.SongSuggestion, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	suggestions := make([]*models.SongSuggestion, 0)
+	for _, record := range s.storage.songs {
+		song := s.storage.songOut(record)
+		songSimilarity, groupSimilarity := trigramSimilarity(song.Song, query), trigramSimilarity(song.Group, query)
+		if songSimilarity >= defaultSimilarityThreshold || groupSimilarity >= defaultSimilarityThreshold {
+			suggestions = append(suggestions, &models.SongSuggestion{Song: song, Similarity: max(songSimilarity, groupSimilarity)})
+		}
+	}
+	sortSuggestions(suggestions)
+
+	return paginate(suggestions, offset, limit), nil
+}
+
+// Метод для поиска песен, похожих на добавляемую (и исполнитель, и название должны быть похожи не меньше порога)
+func (s *MemorySongRepository) FindSimilarSongs(group, song string, threshold float64, limit int) ([]*models.SongSuggestion, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	suggestions := make([]*models.SongSuggestion, 0)
+	for _, record := range s.storage.songs {
+		stored := s.storage.songOut(record)
+		songSimilarity, groupSimilarity := trigramSimilarity(stored.Song, song), trigramSimilarity(stored.Group, group)
+		if songSimilarity >= threshold && groupSimilarity >= threshold {
+			suggestions = append(suggestions, &models.SongSuggestion{Song: stored, Similarity: min(songSimilarity, groupSimilarity)})
+		}
+	}
+	sortSuggestions(suggestions)
+
+	return paginate(suggestions, 0, limit), nil
+}
+
+// Функция, сортирующая похожие песни по убыванию сходства (как ORDER BY score DESC, s.id)
+func sortSuggestions(suggestions []*models.SongSuggestion) {
+	slices.SortStableFunc(suggestions, func(a, b *models.SongSuggestion) int {
+		return cmp.Or(cmp.Compare(b.Similarity, a.Similarity), cmp.Compare(a.Song.ID, b.Song.ID))
+	})
+}
+
+// Метод для получения песни из хранилища по ее идентификатору
+func (s *MemorySongRepository) GetSong(id int64) (*models.Song, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	record := s.storage.songByID(id)
+	if record == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.storage.songOut(record), nil
+}
+
+// Метод для получения текста песни из хранилища вместе с версией песни
+func (s *MemorySongRepository) GetSongText(id int64, offset, limit int) ([]string, int64, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	record := s.storage.songByID(id)
+	if record == nil {
+		return nil, 0, sql.ErrNoRows
+	}
+
+	return sliceVerses(record.song.Text, offset, limit), record.song.Version, nil
+}
+
+// Метод для изменения всех полей песни song.ID в хранилище (исполнитель создается, если его еще нет; sql.ErrNoRows, если песня не найдена; ErrStaleVersion, если версия не равна song.Version)
+func (s *MemorySongRepository) UpdateSong(song *models.Song) error {
+	releaseDate, err := normalizeReleaseDate(song.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	record := s.storage.songByID(song.ID)
+	if record == nil {
+		return sql.ErrNoRows
+	}
+	if song.Version != 0 && song.Version != record.song.Version {
+		return ErrStaleVersion
+	}
+	if s.storage.hasSong(song.Group, song.Song, song.ID) {
+		return ErrAlreadyExists
+	}
+
+	// Проверяем ссылку на альбом и уникальность номера трека в нем (как внешний ключ и уникальный индекс в Postgres)
+	if song.AlbumID != nil {
+		if s.storage.albumByID(*song.AlbumID) == nil {
+			return ErrBrokenReference
+		}
+		for _, other := range s.storage.songs {
+			if other != record && other.song.AlbumID != nil && *other.song.AlbumID == *song.AlbumID &&
+				equalIntPtr(other.song.DiscNumber, song.DiscNumber) && equalIntPtr(other.song.TrackNumber, song.TrackNumber) {
+				return ErrAlreadyExists
+			}
+		}
+	}
+
+	old := s.storage.songOut(record)
+	updated := copySong(song)
+	updated.ArtistID = s.storage.upsertArtist(song.Group).ID
+	updated.Group = ""
+	updated.ReleaseDate = releaseDate
+	updated.AlbumID, updated.DiscNumber, updated.TrackNumber = clonePtr(song.AlbumID), clonePtr(song.DiscNumber), clonePtr(song.TrackNumber)
+	updated.Version = record.song.Version + 1
+	updated.DeletedAt = nil
+	record.song = *updated
+	song.Version = updated.Version
+	s.storage.addRevision(old, s.storage.songOut(record), s.author)
+
+	return nil
+}
+
+// Функция, возвращающая указатель на копию значения (чтобы хранилище не делило память с вызывающим)
+func clonePtr[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+
+	valueCopy := *value
+	return &valueCopy
+}
+
+// Функция, сравнивающая nullable числа (NULL равен только NULL)
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// Метод для изменения даты релиза, текста и ссылки песни в хранилище (sql.ErrNoRows, если песня не найдена; ErrStaleVersion, если версия не равна song.Version)
+func (s *MemorySongRepository) UpdateSongInfo(song *models.Song) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	return s.storage.updateSongInfo(song, s.author)
+}
+
+// Метод, изменяющий дату релиза, текст и ссылку песни от имени author, если ее версия равна song.Version (вызывающий должен держать блокировку на запись)
+func (storage *MemoryStorage) updateSongInfo(song *models.Song, author string) error {
+	releaseDate, err := normalizeReleaseDate(song.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	record := storage.songByID(song.ID)
+	if record == nil {
+		return sql.ErrNoRows
+	}
+	if song.Version != 0 && song.Version != record.song.Version {
+		return ErrStaleVersion
+	}
+
+	old := storage.songOut(record)
+	record.song.ReleaseDate = releaseDate
+	record.song.Text = slices.Clone(song.Text)
+	record.song.Link = song.Link
+	record.song.Version++
+	song.Version = record.song.Version
+	storage.addRevision(old, storage.songOut(record), author)
+
+	return nil
+}
+
+// Метод для удаления песни в корзину (sql.ErrNoRows, если песня не найдена; ErrStaleVersion, если версия не равна version)
+func (s *MemorySongRepository) DeleteSong(id int64, version int64) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	record := s.storage.songByID(id)
+	if record == nil {
+		return sql.ErrNoRows
+	}
+	if version != 0 && version != record.song.Version {
+		return ErrStaleVersion
+	}
+
+	s.storage.addRevision(s.storage.songOut(record), nil, s.author)
+	s.storage.songs = slices.DeleteFunc(s.storage.songs, func(record *songRecord) bool {
+		return record.song.ID == id
+	})
+	// Задания остаются до окончательного удаления песни из корзины (как в Postgres)
+	deletedAt := time.Now()
+	record.song.DeletedAt = &deletedAt
+	record.song.Version++
+	s.storage.trash = append(s.storage.trash, record)
+
+	return nil
+}
+
+// Метод для добавления песни в хранилище (ErrAlreadyExists, если такая песня уже есть; исполнитель создается, если его еще нет; идентификаторы и версия записываются в song.ID, song.ArtistID и song.Version)
+func (s *MemorySongRepository) AddSong(song *models.Song) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	return s.storage.addSong(song, s.author)
+}
+
+// Метод, добавляющий песню в хранилище от имени author (вызывается под блокировкой на запись)
+func (storage *MemoryStorage) addSong(song *models.Song, author string) error {
+	releaseDate, err := normalizeReleaseDate(song.ReleaseDate)
+	if err != nil {
+		return err
+	}
+	if storage.hasSong(song.Group, song.Song, 0) {
+		return ErrAlreadyExists
+	}
+
+	storage.lastSongID++
+	song.ID = storage.lastSongID
+	song.ArtistID = storage.upsertArtist(song.Group).ID
+	song.Version = 1
+
+	record := &songRecord{song: *copySong(song)}
+	record.song.Group = ""
+	record.song.ReleaseDate = releaseDate
+	record.song.DeletedAt = nil
+
+	storage.songs = append(storage.songs, record)
+	storage.addRevision(nil, storage.songOut(record), author)
+
+	return nil
+}
+
+// Метод для повторного добавления удаленной песни в хранилище с прежним идентификатором (версия продолжает историю изменений песни)
+func (s *MemorySongRepository) RestoreSong(song *models.Song) error {
+	releaseDate, err := normalizeReleaseDate(song.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	if s.storage.songByID(song.ID) != nil {
+		return ErrAlreadyExists
+	}
+	if s.storage.trashByID(song.ID) != nil {
+		return ErrWrongState
+	}
+	if s.storage.hasSong(song.Group, song.Song, 0) {
+		return ErrAlreadyExists
+	}
+	// Проверяем ссылку на альбом и уникальность номера трека в нем (как внешний ключ и уникальный индекс в Postgres)
+	if song.AlbumID != nil {
+		if s.storage.albumByID(*song.AlbumID) == nil {
+			return ErrBrokenReference
+		}
+		for _, other := range s.storage.songs {
+			if other.song.AlbumID != nil && *other.song.AlbumID == *song.AlbumID &&
+				equalIntPtr(other.song.DiscNumber, song.DiscNumber) && equalIntPtr(other.song.TrackNumber, song.TrackNumber) {
+				return ErrAlreadyExists
+			}
+		}
+	}
+
+	song.ArtistID = s.storage.upsertArtist(song.Group).ID
+	song.Version = s.storage.lastRevision(song.ID) + 1
+
+	record := &songRecord{song: *copySong(song)}
+	record.song.Group = ""
+	record.song.ReleaseDate = releaseDate
+	record.song.AlbumID, record.song.DiscNumber, record.song.TrackNumber = clonePtr(song.AlbumID), clonePtr(song.DiscNumber), clonePtr(song.TrackNumber)
+	record.song.DeletedAt = nil
+
+	s.storage.insertSong(record)
+	s.storage.addRevision(nil, s.storage.songOut(record), s.author)
+
+	return nil
+}
+
+// Метод, вставляющий песню на место по ее идентификатору (песни хранятся в порядке добавления; вызывается под блокировкой на запись)
+func (storage *MemoryStorage) insertSong(record *songRecord) {
+	position, _ := slices.BinarySearchFunc(storage.songs, record.song.ID, func(record *songRecord, id int64) int {
+		return cmp.Compare(record.song.ID, id)
+	})
+	storage.songs = slices.Insert(storage.songs, position, record)
+}
+
+// Метод для проверки наличия песни в хранилище
+func (s *MemorySongRepository) CheckSong(group string, song string) error {
+	_, err := s.FindSongID(group, song)
+	return err
+}
+
+// Метод для получения идентификатора песни по названиям исполнителя и песни
+func (s *MemorySongRepository) FindSongID(group string, song string) (int64, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	record := s.storage.findSong(group, song)
+	if record == nil {
+		return 0, sql.ErrNoRows
+	}
+
+	return record.song.ID, nil
+}
+
+// Метод для поиска песни по идентификатору (вызывается под блокировкой)
+func (storage *MemoryStorage) songByID(id int64) *songRecord {
+	for _, record := range storage.songs {
+		if record.song.ID == id {
+			return record
+		}
+	}
+
+	return nil
+}
+
+// Метод для поиска песни по ключам названий исполнителя и песни (вызывается под блокировкой)
+func (storage *MemoryStorage) findSong(group, song string) *songRecord {
+	artist := storage.artistByName(group)
+	if artist == nil {
+		return nil
+	}
+
+	key := models.NameKey(song)
+	for _, record := range storage.songs {
+		if record.song.ArtistID == artist.ID && models.NameKey(record.song.Song) == key {
+			return record
+		}
+	}
+
+	return nil
+}
+
+// Метод, проверяющий, есть ли в хранилище другая песня (не exceptID) с такими же ключами названий (как уникальный индекс в Postgres; вызывается под блокировкой)
+func (storage *MemoryStorage) hasSong(group, song string, exceptID int64) bool {
+	record := storage.findSong(group, song)
+	return record != nil && record.song.ID != exceptID
+}
+
+// Метод, возвращающий копию песни вместе с названием ее исполнителя (вызывается под блокировкой)
+func (storage *MemoryStorage) songOut(record *songRecord) *models.Song {
+	song := copySong(&record.song)
+	if artist := storage.artistByID(song.ArtistID); artist != nil {
+		song.Group = artist.Name
+	}
+
+	return song
+}
+
+// Функция, возвращающая копию песни (чтобы хэндлеры не изменяли данные хранилища напрямую)
+func copySong(song *models.Song) *models.Song {
+	songCopy := *song
+	songCopy.Text = slices.Clone(song.Text)
+
+	return &songCopy
+}
+
+// Функция, возвращающая часть списка в пределах смещения и лимита (аналог OFFSET и LIMIT в Postgres)
+func paginate[T any](items []T, offset, limit int) []T {
+	offset = min(max(offset, 0), len(items))
+	limit = min(max(limit, 0), len(items)-offset)
+
+	return items[offset : offset+limit]
+}
+
+// Функция, возвращающая куплеты в пределах смещения и лимита (аналог text[offset+1:offset+limit] в Postgres)
+func sliceVerses(verses []string, offset, limit int) []string {
+	if offset < 0 {
+		limit += offset
+		offset = 0
+	}
+	if offset >= len(verses) || limit <= 0 {
+		return nil
+	}
+
+	return slices.Clone(verses[offset:min(offset+limit, len(verses))])
+}
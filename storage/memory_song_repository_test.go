@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"mus_lib/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+// Функция, возвращающая хранилище в памяти с одной песней
+func newMemoryWithSong(t *testing.T) (*MemoryStorage, *models.Song) {
+	t.Helper()
+
+	store := NewMemory()
+	song := &models.Song{Group: "Muse", Song: "Uprising", Text: []string{"first", "second"}}
+	err := store.Song().AddSong(song)
+	if err != nil {
+		t.Fatalf("AddSong() error = %v", err)
+	}
+
+	return store, song
+}
+
+func TestMemorySongRepository(t *testing.T) {
+	store := NewMemory().Song()
+
+	song := &models.Song{Group: "Muse", Song: "Uprising", Text: []string{"one", "two", "three"}}
+	err := store.AddSong(song)
+	if err != nil || song.ID == 0 {
+		t.Fatalf("AddSong() id = %d, error = %v", song.ID, err)
+	}
+
+	// Названия сравниваются по ключу названия, поэтому поиск не зависит от регистра и пробелов
+	id, err := store.FindSongID(" MUSE ", "uprising")
+	if err != nil || id != song.ID {
+		t.Fatalf("FindSongID() = %d, %v; want %d", id, err, song.ID)
+	}
+	verses, _, err := store.GetSongText(id, 1, 5)
+	if err != nil || !reflect.DeepEqual(verses, []string{"two", "three"}) {
+		t.Errorf("GetSongText() = %v, %v; want [two three]", verses, err)
+	}
+
+	// Песни, возвращенные хранилищем, можно менять, не затрагивая хранимые
+	stored, err := store.GetSong(id)
+	if err != nil {
+		t.Fatalf("GetSong() error = %v", err)
+	}
+	stored.Text[0] = "changed"
+
+	renamed := *stored
+	renamed.Text = []string{"one", "two", "three"}
+	renamed.Group, renamed.Song = "Muse", "Starlight"
+	err = store.UpdateSong(&renamed)
+	if err != nil {
+		t.Fatalf("UpdateSong() error = %v", err)
+	}
+	if err = store.CheckSong("muse", "uprising"); err != sql.ErrNoRows {
+		t.Errorf("CheckSong() of renamed song error = %v, want %v", err, sql.ErrNoRows)
+	}
+	stored, err = store.GetSong(id)
+	if err != nil || stored.Song != "Starlight" || stored.Text[0] != "one" {
+		t.Errorf("GetSong() of renamed song = %+v, %v", stored, err)
+	}
+
+	err = store.DeleteSong(id, 0)
+	if err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+	if _, err = store.GetSong(id); err != sql.ErrNoRows {
+		t.Errorf("GetSong() of deleted song error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	// Идентификаторы удаленных песен не переиспользуются
+	other := &models.Song{Group: "Muse", Song: "Uprising"}
+	if err = store.AddSong(other); err != nil || other.ID <= id {
+		t.Errorf("AddSong() after delete id = %d, %v; want greater than %d", other.ID, err, id)
+	}
+}
+
+func TestMemoryUpdateSongInfoChecksVersion(t *testing.T) {
+	store, song := newMemoryWithSong(t)
+
+	tests := []struct {
+		name    string
+		version int64
+		wantErr error
+	}{
+		{"stale version", song.Version + 1, ErrStaleVersion},
+		{"actual version", song.Version, nil},
+		{"without check", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := store.Song().GetSong(song.ID)
+			if err != nil {
+				t.Fatalf("GetSong() error = %v", err)
+			}
+
+			update := *stored
+			update.Version = tt.version
+			update.Link = "https://example.com/" + tt.name
+			err = store.Song().UpdateSongInfo(&update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateSongInfo() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && update.Version != stored.Version+1 {
+				t.Errorf("version = %d, want %d", update.Version, stored.Version+1)
+			}
+		})
+	}
+}
+
+func TestMemoryVerseEditsCheckVersion(t *testing.T) {
+	store, song := newMemoryWithSong(t)
+
+	_, err := store.Song().ReplaceVerse(song.ID, song.Version+1, 0, "changed")
+	if !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("ReplaceVerse() with stale version error = %v, want %v", err, ErrStaleVersion)
+	}
+
+	version, err := store.Song().ReplaceVerse(song.ID, song.Version, 0, "changed")
+	if err != nil {
+		t.Fatalf("ReplaceVerse() error = %v", err)
+	}
+	if version != song.Version+1 {
+		t.Errorf("version = %d, want %d", version, song.Version+1)
+	}
+
+	_, err = store.Song().DeleteVerse(song.ID, version, 5)
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("DeleteVerse() of missing verse error = %v, want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestMemoryUpdateSongInfoOfMissingSong(t *testing.T) {
+	store, song := newMemoryWithSong(t)
+
+	missing := *song
+	missing.ID = song.ID + 1
+	if err := store.Song().UpdateSongInfo(&missing); err != sql.ErrNoRows {
+		t.Errorf("UpdateSongInfo() of missing song error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestSliceVerses(t *testing.T) {
+	verses := []string{"one", "two", "three"}
+
+	tests := []struct {
+		offset int
+		limit  int
+		want   []string
+	}{
+		{0, 2, []string{"one", "two"}},
+		{1, 10, []string{"two", "three"}},
+		{3, 1, nil},
+		{0, 0, nil},
+		{-1, 2, []string{"one"}},
+	}
+
+	for _, tt := range tests {
+		if got := sliceVerses(verses, tt.offset, tt.limit); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sliceVerses(%d, %d) = %v, want %v", tt.offset, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestMemorySoftDeleteAndRestore(t *testing.T) {
+	store, song := newMemoryWithSong(t)
+
+	err := store.Song().DeleteSong(song.ID, song.Version+1)
+	if !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("DeleteSong() with stale version error = %v, want %v", err, ErrStaleVersion)
+	}
+	err = store.Song().DeleteSong(song.ID, song.Version)
+	if err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	_, err = store.Song().GetSong(song.ID)
+	if err != sql.ErrNoRows {
+		t.Errorf("GetSong() of deleted song error = %v, want %v", err, sql.ErrNoRows)
+	}
+	trash, err := store.Song().GetTrash(0, 10)
+	if err != nil || len(trash) != 1 || trash[0].ID != song.ID {
+		t.Fatalf("GetTrash() = %v, %v; want deleted song", trash, err)
+	}
+
+	// Пока песня в корзине, такую же песню можно добавить заново, но тогда вернуть удаленную нельзя
+	duplicate := &models.Song{Group: "MUSE", Song: "UPRISING"}
+	err = store.Song().AddSong(duplicate)
+	if err != nil {
+		t.Fatalf("AddSong() of deleted song error = %v", err)
+	}
+	err = store.Song().UndeleteSong(song.ID)
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("UndeleteSong() error = %v, want %v", err, ErrAlreadyExists)
+	}
+
+	err = store.Song().DeleteSong(duplicate.ID, 0)
+	if err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+	err = store.Song().UndeleteSong(song.ID)
+	if err != nil {
+		t.Fatalf("UndeleteSong() error = %v", err)
+	}
+	restored, err := store.Song().GetSong(song.ID)
+	if err != nil || restored.Song != "Uprising" || len(restored.Text) != 2 {
+		t.Errorf("GetSong() of restored song = %+v, %v", restored, err)
+	}
+
+	err = store.Song().UndeleteSong(song.ID)
+	if err != sql.ErrNoRows {
+		t.Errorf("UndeleteSong() of song not in trash error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestMemoryNameKeys(t *testing.T) {
+	store := NewMemory()
+
+	song := &models.Song{Group: "AC/DC", Song: "Back In Black"}
+	if err := store.Song().AddSong(song); err != nil {
+		t.Fatalf("AddSong() error = %v", err)
+	}
+
+	// Названия, которые отличаются только регистром и пробелами, - одна и та же песня
+	err := store.Song().AddSong(&models.Song{Group: " ac/dc", Song: "BACK  IN BLACK "})
+	if err != ErrAlreadyExists {
+		t.Errorf("AddSong() of duplicate error = %v, want %v", err, ErrAlreadyExists)
+	}
+
+	// Название хранится в том виде, в котором его передали
+	stored, err := store.Song().GetSong(song.ID)
+	if err != nil || stored.Group != "AC/DC" || stored.Song != "Back In Black" {
+		t.Errorf("GetSong() = %+v, %v; want names as added", stored, err)
+	}
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"mus_lib/internal/app/models"
+	"sync"
+)
+
+// Инстанс хранилища, держащего все данные в памяти процесса (используется для запуска без Postgres)
+type MemoryStorage struct {
+	// Поля неэкспортируемые (конфендициальная информация)
+	mu                 sync.RWMutex                             // Защищает данные хранилища от одновременного доступа из разных хэндлеров
+	songs              []*songRecord                            // Песни в порядке их добавления
+	trash              []*songRecord                            // Песни в корзине в порядке их удаления
+	lastSongID         int64                                    // Последний выданный идентификатор песни (аналог bigserial)
+	artists            []*models.Artist                         // Исполнители в порядке их добавления
+	lastArtistID       int64                                    // Последний выданный идентификатор исполнителя
+	albums             []*models.Album                          // Альбомы в порядке их добавления (треки хранятся в самих песнях)
+	lastAlbumID        int64                                    // Последний выданный идентификатор альбома
+	jobs               []*jobRecord                             // Задания на заполнение информации о песнях в порядке их добавления
+	lastJobID          int64                                    // Последний выданный идентификатор задания
+	songRepository     *MemorySongRepository                    // Модельный репозиторий, через который будет проводиться работа с хранилищем
+	artistRepository   *MemoryArtistRepository                  // Модельный репозиторий исполнителей
+	albumRepository    *MemoryAlbumRepository                   // Модельный репозиторий альбомов
+	jobRepository      *MemoryJobRepository                     // Модельный репозиторий заданий
+	songInfo           map[[2]string]*models.SongInfoCacheEntry // Кэш ответов стороннего API по названиям исполнителя и песни
+	songInfoCache      *MemorySongInfoCacheRepository           // Модельный репозиторий кэша ответов стороннего API
+	revisions          []*models.SongRevision                   // История изменений песен в порядке их выполнения
+	revisionRepository *MemoryRevisionRepository                // Модельный репозиторий истории изменений песен
+	playlists          []*playlistRecord                        // Плейлисты в порядке их добавления
+	lastPlaylistID     int64                                    // Последний выданный идентификатор плейлиста
+	playlistRepository *MemoryPlaylistRepository                // Модельный репозиторий плейлистов
+	users              []*models.User                           // Пользователи в порядке регистрации
+	lastUserID         int64                                    // Последний выданный идентификатор пользователя
+	apiKeys            []*models.APIKey                         // API-ключи пользователей в порядке создания
+	lastAPIKeyID       int64                                    // Последний выданный идентификатор API-ключа
+	userRepository     *MemoryUserRepository                    // Модельный репозиторий пользователей
+}
+
+// Конструктор, возвращающий инстанс хранилища в памяти
+func NewMemory() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+// Метод, открывающий хранилище (соединение не требуется, поэтому ничего не делает)
+func (storage *MemoryStorage) Open() error {
+	return nil
+}
+
+// Метод, закрывающий хранилище (все данные при этом сохраняются до завершения процесса)
+func (storage *MemoryStorage) Close() {}
+
+// Метод, проверяющий готовность хранилища к работе (миграции в памяти не требуются)
+func (storage *MemoryStorage) CheckMigrations() error {
+	return nil
+}
+
+// Метод, создающий публичный репозиторий для Song
+func (storage *MemoryStorage) Song() SongStore {
+	if storage.songRepository != nil {
+		return storage.songRepository
+	}
+
+	storage.songRepository = &MemorySongRepository{
+		storage: storage,
+	}
+
+	return storage.songRepository
+}
+
+// Метод, создающий публичный репозиторий для Artist
+func (storage *MemoryStorage) Artist() ArtistStore {
+	if storage.artistRepository != nil {
+		return storage.artistRepository
+	}
+
+	storage.artistRepository = &MemoryArtistRepository{
+		storage: storage,
+	}
+
+	return storage.artistRepository
+}
+
+// Метод, создающий публичный репозиторий для Album
+func (storage *MemoryStorage) Album() AlbumStore {
+	if storage.albumRepository != nil {
+		return storage.albumRepository
+	}
+
+	storage.albumRepository = &MemoryAlbumRepository{
+		storage: storage,
+	}
+
+	return storage.albumRepository
+}
+
+// Метод, создающий публичный репозиторий для Job
+func (storage *MemoryStorage) Job() JobStore {
+	if storage.jobRepository != nil {
+		return storage.jobRepository
+	}
+
+	storage.jobRepository = &MemoryJobRepository{
+		storage: storage,
+	}
+
+	return storage.jobRepository
+}
+
+// Метод, создающий публичный репозиторий для кэша ответов стороннего API
+func (storage *MemoryStorage) SongInfoCache() SongInfoCacheStore {
+	if storage.songInfoCache != nil {
+		return storage.songInfoCache
+	}
+
+	storage.songInfoCache = &MemorySongInfoCacheRepository{
+		storage: storage,
+	}
+
+	return storage.songInfoCache
+}
+
+// Метод, создающий публичный репозиторий для истории изменений песен
+func (storage *MemoryStorage) Revision() RevisionStore {
+	if storage.revisionRepository != nil {
+		return storage.revisionRepository
+	}
+
+	storage.revisionRepository = &MemoryRevisionRepository{
+		storage: storage,
+	}
+
+	return storage.revisionRepository
+}
+
+// Метод, создающий публичный репозиторий для Playlist
+func (storage *MemoryStorage) Playlist() PlaylistStore {
+	if storage.playlistRepository != nil {
+		return storage.playlistRepository
+	}
+
+	storage.playlistRepository = &MemoryPlaylistRepository{
+		storage: storage,
+	}
+
+	return storage.playlistRepository
+}
+
+// Метод, создающий публичный репозиторий для User
+func (storage *MemoryStorage) User() UserStore {
+	if storage.userRepository != nil {
+		return storage.userRepository
+	}
+
+	storage.userRepository = &MemoryUserRepository{
+		storage: storage,
+	}
+
+	return storage.userRepository
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"slices"
+	"time"
+)
+
+// Метод для получения песен из корзины с учетом пагинации (недавно удаленные первыми)
+func (s *MemorySongRepository) GetTrash(offset, limit int) ([]*models.Song, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	songs := make([]*models.Song, 0)
+	for i := len(s.storage.trash) - 1; i >= 0; i-- {
+		songs = append(songs, s.storage.songOut(s.storage.trash[i]))
+	}
+
+	return paginate(songs, offset, limit), nil
+}
+
+// Метод для возвращения песни из корзины (sql.ErrNoRows, если песни в корзине нет; ErrAlreadyExists, если такая песня уже есть или ее номер трека занят)
+func (s *MemorySongRepository) UndeleteSong(id int64) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	record := s.storage.trashByID(id)
+	if record == nil {
+		return sql.ErrNoRows
+	}
+	// Пока песня лежала в корзине, могли добавить такую же песню или занять ее номер трека в альбоме
+	if s.storage.hasSong(s.storage.songOut(record).Group, record.song.Song, 0) {
+		return ErrAlreadyExists
+	}
+	for _, other := range s.storage.songs {
+		if record.song.AlbumID != nil && other.song.AlbumID != nil && *other.song.AlbumID == *record.song.AlbumID &&
+			equalIntPtr(other.song.DiscNumber, record.song.DiscNumber) && equalIntPtr(other.song.TrackNumber, record.song.TrackNumber) {
+			return ErrAlreadyExists
+		}
+	}
+
+	s.storage.trash = slices.DeleteFunc(s.storage.trash, func(record *songRecord) bool {
+		return record.song.ID == id
+	})
+	record.song.DeletedAt = nil
+	record.song.Version++
+	s.storage.insertSong(record)
+	s.storage.addRevision(nil, s.storage.songOut(record), s.author)
+
+	return nil
+}
+
+// Метод для окончательного удаления песен, которые лежат в корзине с момента раньше before, и возвращающий их количество
+func (s *MemorySongRepository) PurgeDeletedSongs(before time.Time) (int64, error) {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	purged := make(map[int64]bool)
+	s.storage.trash = slices.DeleteFunc(s.storage.trash, func(record *songRecord) bool {
+		if record.song.DeletedAt.Before(before) {
+			purged[record.song.ID] = true
+		}
+		return purged[record.song.ID]
+	})
+	// Задания удаляются вместе с песней (как ON DELETE CASCADE в Postgres)
+	s.storage.jobs = slices.DeleteFunc(s.storage.jobs, func(record *jobRecord) bool {
+		return purged[record.job.SongID]
+	})
+	// Записи плейлистов тоже удаляются вместе с песней
+	for _, playlist := range s.storage.playlists {
+		playlist.songIDs = slices.DeleteFunc(playlist.songIDs, func(songID int64) bool {
+			return purged[songID]
+		})
+	}
+
+	return int64(len(purged)), nil
+}
+
+// Метод для поиска песни в корзине по идентификатору (вызывается под блокировкой)
+func (storage *MemoryStorage) trashByID(id int64) *songRecord {
+	for _, record := range storage.trash {
+		if record.song.ID == id {
+			return record
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"slices"
+	"time"
+)
+
+// Сущность модельного репозитория пользователей и их API-ключей для хранилища в памяти
+type MemoryUserRepository struct {
+	storage *MemoryStorage // Хранит в себе хранилище, т.к. общение с ним реализовано посредством репозитория
+}
+
+// Метод для добавления пользователя в хранилище (ErrAlreadyExists, если имя занято)
+func (r *MemoryUserRepository) AddUser(user *models.User) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if r.storage.userByName(user.Name) != nil {
+		return ErrAlreadyExists
+	}
+
+	r.storage.lastUserID++
+	user.ID = r.storage.lastUserID
+	user.CreatedAt = time.Now()
+
+	stored := *user
+	r.storage.users = append(r.storage.users, &stored)
+
+	return nil
+}
+
+// Метод для получения пользователя из хранилища по имени (sql.ErrNoRows, если его нет)
+func (r *MemoryUserRepository) GetUserByName(name string) (*models.User, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	user := r.storage.userByName(name)
+	if user == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	found := *user
+	return &found, nil
+}
+
+// Метод для получения владельца API-ключа по хэшу ключа с отметкой времени использования ключа (sql.ErrNoRows, если ключа нет)
+func (r *MemoryUserRepository) FindUserByAPIKey(keyHash string) (*models.User, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	for _, key := range r.storage.apiKeys {
+		if key.KeyHash != keyHash {
+			continue
+		}
+
+		for _, user := range r.storage.users {
+			if user.ID == key.UserID {
+				usedAt := time.Now()
+				key.LastUsedAt = &usedAt
+
+				found := *user
+				return &found, nil
+			}
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+// Метод для добавления API-ключа в хранилище
+func (r *MemoryUserRepository) AddAPIKey(key *models.APIKey) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	r.storage.lastAPIKeyID++
+	key.ID = r.storage.lastAPIKeyID
+	key.CreatedAt = time.Now()
+
+	stored := *key
+	r.storage.apiKeys = append(r.storage.apiKeys, &stored)
+
+	return nil
+}
+
+// Метод для получения API-ключей пользователя из хранилища в порядке создания (без хэшей ключей)
+func (r *MemoryUserRepository) GetAPIKeys(userID int64) ([]*models.APIKey, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	keys := make([]*models.APIKey, 0)
+	for _, stored := range r.storage.apiKeys {
+		if stored.UserID == userID {
+			key := *stored
+			key.KeyHash = ""
+			keys = append(keys, &key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Метод для отзыва API-ключа пользователя (sql.ErrNoRows, если у пользователя нет такого ключа)
+func (r *MemoryUserRepository) DeleteAPIKey(userID, id int64) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	count := len(r.storage.apiKeys)
+	r.storage.apiKeys = slices.DeleteFunc(r.storage.apiKeys, func(key *models.APIKey) bool {
+		return key.ID == id && key.UserID == userID
+	})
+	if len(r.storage.apiKeys) == count {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Метод для поиска пользователя по имени (вызывается под блокировкой)
+func (storage *MemoryStorage) userByName(name string) *models.User {
+	for _, user := range storage.users {
+		if user.Name == name {
+			return user
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"database/sql"
+	"slices"
+)
+
+// Метод, вставляющий куплет перед куплетом position (в конец текста, если position < 0) и возвращающий номер вставленного куплета и новую версию песни
+func (s *MemorySongRepository) InsertVerse(id, version int64, position int, verse string) (int, int64, error) {
+	version, err := s.editVerses(id, version, func(text []string) ([]string, error) {
+		if position < 0 {
+			position = len(text)
+		}
+		if position > len(text) {
+			return nil, ErrOutOfRange
+		}
+
+		return slices.Insert(text, position, verse), nil
+	})
+
+	return position, version, err
+}
+
+// Метод, заменяющий куплет position и возвращающий новую версию песни
+func (s *MemorySongRepository) ReplaceVerse(id, version int64, position int, verse string) (int64, error) {
+	return s.editVerses(id, version, func(text []string) ([]string, error) {
+		if position < 0 || position >= len(text) {
+			return nil, ErrOutOfRange
+		}
+
+		text[position] = verse
+		return text, nil
+	})
+}
+
+// Метод, удаляющий куплет position и возвращающий новую версию песни
+func (s *MemorySongRepository) DeleteVerse(id, version int64, position int) (int64, error) {
+	return s.editVerses(id, version, func(text []string) ([]string, error) {
+		if position < 0 || position >= len(text) {
+			return nil, ErrOutOfRange
+		}
+
+		return slices.Delete(text, position, position+1), nil
+	})
+}
+
+// Метод, переставляющий куплеты в порядке order (order[i] - прежний номер куплета, который станет i-м) и возвращающий новую версию песни
+func (s *MemorySongRepository) ReorderVerses(id, version int64, order []int) (int64, error) {
+	return s.editVerses(id, version, func(text []string) ([]string, error) {
+		if !isPermutation(order, len(text)) {
+			return nil, ErrOutOfRange
+		}
+
+		reordered := make([]string, 0, len(text))
+		for _, position := range order {
+			reordered = append(reordered, text[position])
+		}
+		return reordered, nil
+	})
+}
+
+// Метод, изменяющий копию текста песни под блокировкой на запись, если версия песни равна version (0 - без проверки; ErrStaleVersion, если версия другая),
+// и возвращающий новую версию песни (текст заменяется, только если edit завершился без ошибки)
+func (s *MemorySongRepository) editVerses(id, version int64, edit func(text []string) ([]string, error)) (int64, error) {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	record := s.storage.songByID(id)
+	if record == nil {
+		return 0, sql.ErrNoRows
+	}
+	if version != 0 && record.song.Version != version {
+		return 0, ErrStaleVersion
+	}
+
+	text, err := edit(slices.Clone(record.song.Text))
+	if err != nil {
+		return 0, err
+	}
+	old := s.storage.songOut(record)
+	record.song.Text = text
+	record.song.Version++
+	s.storage.addRevision(old, s.storage.songOut(record), s.author)
+
+	return record.song.Version, nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func TestIsPermutation(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []int
+		count int
+		want  bool
+	}{
+		{"identity", []int{0, 1, 2}, 3, true},
+		{"swap", []int{1, 0, 2}, 3, true},
+		{"empty text", []int{}, 0, true},
+		{"missing verse", []int{0, 1}, 3, false},
+		{"repeated verse", []int{0, 0, 1}, 3, false},
+		{"out of range", []int{0, 1, 3}, 3, false},
+		{"negative", []int{-1, 0, 1}, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermutation(tt.order, tt.count); got != tt.want {
+				t.Errorf("isPermutation(%v, %d) = %t, want %t", tt.order, tt.count, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryVerseEdits(t *testing.T) {
+	store := NewMemory().Song()
+	song := &models.Song{Group: "Muse", Song: "Uprising", Text: []string{"one", "two"}}
+	if err := store.AddSong(song); err != nil {
+		t.Fatalf("AddSong() error = %v", err)
+	}
+
+	position, _, err := store.InsertVerse(song.ID, 0, -1, "three")
+	if err != nil || position != 2 {
+		t.Fatalf("InsertVerse() to the end = %d, %v; want 2", position, err)
+	}
+	if _, _, err = store.InsertVerse(song.ID, 0, 0, "zero"); err != nil {
+		t.Fatalf("InsertVerse() error = %v", err)
+	}
+	if _, err = store.ReplaceVerse(song.ID, 0, 1, "ONE"); err != nil {
+		t.Fatalf("ReplaceVerse() error = %v", err)
+	}
+	if _, err = store.DeleteVerse(song.ID, 0, 2); err != nil {
+		t.Fatalf("DeleteVerse() error = %v", err)
+	}
+	if _, err = store.ReorderVerses(song.ID, 0, []int{2, 0, 1}); err != nil {
+		t.Fatalf("ReorderVerses() error = %v", err)
+	}
+	stored, _ := store.GetSong(song.ID)
+	if want := []string{"three", "zero", "ONE"}; !reflect.DeepEqual(stored.Text, want) {
+		t.Errorf("text = %v, want %v", stored.Text, want)
+	}
+
+	// Ошибочная правка не меняет текст
+	if _, _, err = store.InsertVerse(song.ID, 0, 4, "four"); err != ErrOutOfRange {
+		t.Errorf("InsertVerse() after the end error = %v, want %v", err, ErrOutOfRange)
+	}
+	if _, err = store.ReplaceVerse(song.ID, 0, 3, "four"); err != ErrOutOfRange {
+		t.Errorf("ReplaceVerse() of missing verse error = %v, want %v", err, ErrOutOfRange)
+	}
+	if _, err = store.ReorderVerses(song.ID, 0, []int{0, 1}); err != ErrOutOfRange {
+		t.Errorf("ReorderVerses() with partial order error = %v, want %v", err, ErrOutOfRange)
+	}
+	if _, err = store.DeleteVerse(song.ID+1, 0, 0); err != sql.ErrNoRows {
+		t.Errorf("DeleteVerse() of missing song error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if again, _ := store.GetSong(song.ID); !reflect.DeepEqual(again.Text, stored.Text) {
+		t.Errorf("text after failed edits = %v, want %v", again.Text, stored.Text)
+	}
+}
@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"database/sql"
+	"mus_lib/internal/app/models"
+	"slices"
+
+	"github.com/lib/pq"
+)
+
+// Сущность модельного репозитория плейлистов
+type PlaylistRepository struct {
+	storage *Storage // Хранит в себе БД, т.к. общение с БД реализовано посредством репозитория
+}
+
+// Колонки плейлиста, извлекаемые из БД (порядок соответствует scanPlaylist)
+const playlistColumns = `id, name, description, owner, public, created_at, updated_at`
+
+// Функция, считывающая плейлист из строки результата запроса
+func scanPlaylist(row scanner) (*models.Playlist, error) {
+	playlist := models.Playlist{}
+
+	err := row.Scan(&playlist.ID, &playlist.Name, &playlist.Description, &playlist.Owner, &playlist.Public, &playlist.CreatedAt, &playlist.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &playlist, nil
+}
+
+// Метод для получения публичных плейлистов и плейлистов viewer из БД с учетом пагинации (без записей)
+func (r *PlaylistRepository) GetPlaylists(viewer string, offset, limit int) ([]*models.Playlist, error) {
+	res, err := r.storage.db.Query(`SELECT `+playlistColumns+` FROM playlists WHERE public OR owner=$1 ORDER BY id OFFSET $2 LIMIT $3`, viewer, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	playlists := make([]*models.Playlist, 0)
+
+	for res.Next() {
+		playlist, err := scanPlaylist(res)
+		if err != nil {
+			return nil, err
+		}
+
+		playlists = append(playlists, playlist)
+	}
+
+	return playlists, res.Err()
+}
+
+// Метод для получения плейлиста из БД по идентификатору (вместе с записями по порядку)
+func (r *PlaylistRepository) GetPlaylist(id int64) (*models.Playlist, error) {
+	playlist, err := scanPlaylist(r.storage.db.QueryRow(`SELECT `+playlistColumns+` FROM playlists WHERE id=$1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	// Песни в корзине остаются в плейлисте, чтобы вернуться на свое место вместе с песней
+	res, err := r.storage.db.Query(`SELECT s.id, a.name, s.song, s.link, coalesce(cardinality(s.text), 0), s.deleted_at IS NOT NULL
+		FROM playlist_entries e JOIN `+songsTableName()+` s ON s.id=e.song_id JOIN artists a ON a.id=s.artist_id WHERE e.playlist_id=$1 ORDER BY e.position`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	playlist.Entries = make([]*models.PlaylistEntry, 0)
+
+	for res.Next() {
+		// Номера записей в БД могут идти с пропусками (после окончательного удаления песни), поэтому номер - это место записи в плейлисте
+		entry := models.PlaylistEntry{Position: len(playlist.Entries)}
+		err := res.Scan(&entry.SongID, &entry.Group, &entry.Song, &entry.Link, &entry.VerseCount, &entry.Deleted)
+		if err != nil {
+			return nil, err
+		}
+
+		playlist.Entries = append(playlist.Entries, &entry)
+	}
+
+	return playlist, res.Err()
+}
+
+// Метод для добавления плейлиста в БД
+func (r *PlaylistRepository) AddPlaylist(playlist *models.Playlist) error {
+	return r.storage.db.QueryRow(`INSERT INTO playlists (name, description, owner, public) VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`,
+		playlist.Name, playlist.Description, playlist.Owner, playlist.Public).Scan(&playlist.ID, &playlist.CreatedAt, &playlist.UpdatedAt)
+}
+
+// Метод для изменения названия, описания и видимости плейлиста (sql.ErrNoRows, если плейлист не найден)
+func (r *PlaylistRepository) UpdatePlaylist(playlist *models.Playlist) error {
+	res, err := r.storage.db.Exec(`UPDATE playlists SET name=$1, description=$2, public=$3, updated_at=now() WHERE id=$4`,
+		playlist.Name, playlist.Description, playlist.Public, playlist.ID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+// Метод для удаления плейлиста вместе с записями (sql.ErrNoRows, если плейлист не найден)
+func (r *PlaylistRepository) DeletePlaylist(id int64) error {
+	res, err := r.storage.db.Exec(`DELETE FROM playlists WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
+
+// Метод, вставляющий песню перед записью position (в конец, если position < 0) и возвращающий номер вставленной записи
+func (r *PlaylistRepository) AddEntry(id, songID int64, position int) (int, error) {
+	err := r.editEntries(id, func(tx *sql.Tx, songIDs []int64) ([]int64, error) {
+		if position < 0 {
+			position = len(songIDs)
+		}
+		if position > len(songIDs) {
+			return nil, ErrOutOfRange
+		}
+
+		// Песню блокируем, чтобы ее не удалили окончательно до конца транзакции
+		var exists bool
+		err := tx.QueryRow(`SELECT true FROM `+songsTableName()+` WHERE id=$1 AND deleted_at IS NULL FOR SHARE`, songID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return nil, ErrBrokenReference
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return slices.Insert(songIDs, position, songID), nil
+	})
+
+	return position, err
+}
+
+// Метод, удаляющий запись position
+func (r *PlaylistRepository) DeleteEntry(id int64, position int) error {
+	return r.editEntries(id, func(tx *sql.Tx, songIDs []int64) ([]int64, error) {
+		if position < 0 || position >= len(songIDs) {
+			return nil, ErrOutOfRange
+		}
+
+		return slices.Delete(songIDs, position, position+1), nil
+	})
+}
+
+// Метод, перемещающий запись from так, чтобы она получила номер to
+func (r *PlaylistRepository) MoveEntry(id int64, from, to int) error {
+	return r.editEntries(id, func(tx *sql.Tx, songIDs []int64) ([]int64, error) {
+		return moveEntry(songIDs, from, to)
+	})
+}
+
+// Метод, блокирующий плейлист до конца транзакции, передающий в edit песни его записей по порядку и сохраняющий записи, которые вернул edit
+// (одновременные правки плейлиста выполняются по очереди и не затирают друг друга; sql.ErrNoRows, если плейлист не найден)
+func (r *PlaylistRepository) editEntries(id int64, edit func(tx *sql.Tx, songIDs []int64) ([]int64, error)) error {
+	return r.storage.inTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`UPDATE playlists SET updated_at=now() WHERE id=$1`, id)
+		if err != nil {
+			return err
+		}
+		err = checkAffected(res)
+		if err != nil {
+			return err
+		}
+
+		var songIDs []int64
+		err = tx.QueryRow(`SELECT coalesce(array_agg(song_id ORDER BY position), '{}') FROM playlist_entries WHERE playlist_id=$1`, id).Scan(pq.Array(&songIDs))
+		if err != nil {
+			return err
+		}
+
+		songIDs, err = edit(tx, songIDs)
+		if err != nil {
+			return err
+		}
+
+		// Записи перезаписываются целиком, поэтому номера снова идут подряд с 0
+		_, err = tx.Exec(`DELETE FROM playlist_entries WHERE playlist_id=$1`, id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO playlist_entries (playlist_id, position, song_id) SELECT $1, e.ord-1, e.song_id FROM unnest($2::bigint[]) WITH ORDINALITY AS e(song_id, ord)`,
+			id, pq.Array(songIDs))
+		return err
+	})
+}
+
+// Функция, перемещающая запись from так, чтобы она получила номер to (ErrOutOfRange, если таких записей нет)
+func moveEntry(songIDs []int64, from, to int) ([]int64, error) {
+	if from < 0 || from >= len(songIDs) || to < 0 || to >= len(songIDs) {
+		return nil, ErrOutOfRange
+	}
+
+	songID := songIDs[from]
+	songIDs = slices.Delete(songIDs, from, from+1)
+	return slices.Insert(songIDs, to, songID), nil
+}
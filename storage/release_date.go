@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+)
+
+// Колонки с датой релиза в БД (дата хранится как DATE вместе с ее точностью)
+type releaseDateColumns struct {
+	date      sql.NullTime
+	precision sql.NullString
+}
+
+// Метод, возвращающий дату релиза в текстовом виде (пустая строка, если дата неизвестна)
+func (columns *releaseDateColumns) String() string {
+	if !columns.date.Valid {
+		return ""
+	}
+
+	return models.FormatReleaseDate(columns.date.Time, models.DatePrecision(columns.precision.String))
+}
+
+// Функция, преобразующая текстовую дату релиза в аргументы запроса (пустая строка превращается в NULL)
+func releaseDateArgs(value string) (any, any, error) {
+	if value == "" {
+		return nil, nil, nil
+	}
+
+	date, precision, err := models.ParseReleaseDate(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidReleaseDate, err)
+	}
+
+	return date, string(precision), nil
+}
+
+// Функция, приводящая текстовую дату релиза к тому виду, в котором ее возвращает Postgres (используется хранилищем в памяти)
+func normalizeReleaseDate(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	date, precision, err := models.ParseReleaseDate(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidReleaseDate, err)
+	}
+
+	return models.FormatReleaseDate(date, precision), nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReleaseDateArgs(t *testing.T) {
+	tests := []struct {
+		value         string
+		wantDate      any
+		wantPrecision any
+		wantErr       error
+	}{
+		{"", nil, nil, nil},
+		{"25.08.1991", time.Date(1991, 8, 25, 0, 0, 0, 0, time.UTC), "day", nil},
+		{"1991-08-25", time.Date(1991, 8, 25, 0, 0, 0, 0, time.UTC), "day", nil},
+		{"08.1991", time.Date(1991, 8, 1, 0, 0, 0, 0, time.UTC), "month", nil},
+		{"1991", time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC), "year", nil},
+		{"31.02.1991", nil, nil, ErrInvalidReleaseDate},
+		{"yesterday", nil, nil, ErrInvalidReleaseDate},
+	}
+
+	for _, tt := range tests {
+		date, precision, err := releaseDateArgs(tt.value)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("releaseDateArgs(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if date != tt.wantDate || precision != tt.wantPrecision {
+			t.Errorf("releaseDateArgs(%q) = %v, %v; want %v, %v", tt.value, date, precision, tt.wantDate, tt.wantPrecision)
+		}
+	}
+}
+
+func TestNormalizeReleaseDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr error
+	}{
+		{"", "", nil},
+		{"25.08.1991", "25.08.1991", nil},
+		{"5.8.1991", "05.08.1991", nil},
+		{"1991-08-25", "25.08.1991", nil},
+		{"1991-08", "08.1991", nil},
+		{"8.1991", "08.1991", nil},
+		{"1991", "1991", nil},
+		{"1991/08/25", "", ErrInvalidReleaseDate},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeReleaseDate(tt.value)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("normalizeReleaseDate(%q) = %q, %v; want %q, %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestReleaseDateColumnsString(t *testing.T) {
+	date := time.Date(1991, 8, 25, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		columns releaseDateColumns
+		want    string
+	}{
+		{"null date", releaseDateColumns{}, ""},
+		{"day", releaseDateColumns{sql.NullTime{Time: date, Valid: true}, sql.NullString{String: "day", Valid: true}}, "25.08.1991"},
+		{"month", releaseDateColumns{sql.NullTime{Time: date, Valid: true}, sql.NullString{String: "month", Valid: true}}, "08.1991"},
+		{"year", releaseDateColumns{sql.NullTime{Time: date, Valid: true}, sql.NullString{String: "year", Valid: true}}, "1991"},
+		{"unknown precision is day", releaseDateColumns{sql.NullTime{Time: date, Valid: true}, sql.NullString{}}, "25.08.1991"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.columns.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
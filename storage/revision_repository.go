@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"encoding/json"
+	"mus_lib/internal/app/models"
+	"time"
+)
+
+// Сущность модельного репозитория истории изменений песен
+type RevisionRepository struct {
+	storage *Storage
+}
+
+// Колонки ревизии, извлекаемые из БД (порядок соответствует scanRevision)
+const revisionColumns = `song_id, revision, action, coalesce(author, ''), changed_at, old, new`
+
+// Строка песни в том виде, в котором ее сохраняет в историю триггер (to_jsonb строки таблицы песен вместе с названием исполнителя)
+type revisionSongRow struct {
+	ID                   int64    `json:"id"`
+	ArtistID             int64    `json:"artist_id"`
+	Group                string   `json:"group"`
+	Song                 string   `json:"song"`
+	ReleaseDate          *string  `json:"release_date"`
+	ReleaseDatePrecision *string  `json:"release_date_precision"`
+	Text                 []string `json:"text"`
+	Link                 *string  `json:"link"`
+	AlbumID              *int64   `json:"album_id"`
+	DiscNumber           *int     `json:"disc_number"`
+	TrackNumber          *int     `json:"track_number"`
+	Version              int64    `json:"version"`
+}
+
+// Функция, считывающая ревизию из строки результата запроса
+func scanRevision(row scanner) (*models.SongRevision, error) {
+	var (
+		revision       models.SongRevision
+		oldRow, newRow []byte
+		err            error
+	)
+
+	err = row.Scan(&revision.SongID, &revision.Revision, &revision.Action, &revision.Author, &revision.ChangedAt, &oldRow, &newRow)
+	if err != nil {
+		return nil, err
+	}
+
+	revision.Old, err = decodeRevisionSong(oldRow)
+	if err != nil {
+		return nil, err
+	}
+	revision.New, err = decodeRevisionSong(newRow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// Функция, преобразующая сохраненную триггером строку песни в модель (nil, если строки нет)
+func decodeRevisionSong(data []byte) (*models.Song, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var row revisionSongRow
+	err := json.Unmarshal(data, &row)
+	if err != nil {
+		return nil, err
+	}
+
+	song := &models.Song{
+		ID:          row.ID,
+		ArtistID:    row.ArtistID,
+		Group:       row.Group,
+		Song:        row.Song,
+		Text:        row.Text,
+		AlbumID:     row.AlbumID,
+		DiscNumber:  row.DiscNumber,
+		TrackNumber: row.TrackNumber,
+		Version:     row.Version,
+	}
+	if song.Text == nil {
+		song.Text = []string{}
+	}
+	if row.Link != nil {
+		song.Link = *row.Link
+	}
+	if row.ReleaseDate != nil {
+		date, err := time.Parse(time.DateOnly, *row.ReleaseDate)
+		if err != nil {
+			return nil, err
+		}
+		precision := models.PrecisionDay
+		if row.ReleaseDatePrecision != nil {
+			precision = models.DatePrecision(*row.ReleaseDatePrecision)
+		}
+		song.ReleaseDate = models.FormatReleaseDate(date, precision)
+	}
+
+	return song, nil
+}
+
+// Метод для получения ревизий песни из БД (последние первыми)
+func (r *RevisionRepository) GetRevisions(songID int64, offset, limit int) ([]*models.SongRevision, error) {
+	res, err := r.storage.db.Query(`SELECT `+revisionColumns+` FROM song_revisions WHERE song_id=$1 ORDER BY revision DESC OFFSET $2 LIMIT $3`, songID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	revisions := make([]*models.SongRevision, 0)
+
+	for res.Next() {
+		revision, err := scanRevision(res)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, res.Err()
+}
+
+// Метод для получения ревизии песни из БД (sql.ErrNoRows, если ее нет)
+func (r *RevisionRepository) GetRevision(songID, revision int64) (*models.SongRevision, error) {
+	return scanRevision(r.storage.db.QueryRow(`SELECT `+revisionColumns+` FROM song_revisions WHERE song_id=$1 AND revision=$2`, songID, revision))
+}
@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"cmp"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Способ сравнения строкового значения фильтра со значением в хранилище
+type MatchMode string
+
+const (
+	MatchExact    MatchMode = "exact"    // Полное совпадение (по умолчанию)
+	MatchPrefix   MatchMode = "prefix"   // Значение начинается с заданной строки (без учета регистра)
+	MatchContains MatchMode = "contains" // Значение содержит заданную строку (без учета регистра)
+)
+
+// Функция, преобразующая строку из query string в способ сравнения (пустая строка означает полное совпадение)
+func ParseMatchMode(mode string) (MatchMode, error) {
+	switch MatchMode(mode) {
+	case "", MatchExact:
+		return MatchExact, nil
+	case MatchPrefix, MatchContains:
+		return MatchMode(mode), nil
+	default:
+		return "", fmt.Errorf("unknown match mode: %s", mode)
+	}
+}
+
+// Поле, по которому сортируются песни
+type SongSort string
+
+const (
+	SortDefault     SongSort = ""            // По идентификатору (или по порядку треков, если задан альбом)
+	SortReleaseDate SongSort = "releaseDate" // По дате релиза (песни без даты идут последними)
+)
+
+// Функция, преобразующая строку из query string в поле сортировки
+func ParseSongSort(sort string) (SongSort, error) {
+	switch SongSort(sort) {
+	case SortDefault, SortReleaseDate:
+		return SongSort(sort), nil
+	default:
+		return "", fmt.Errorf("unknown sort field: %s", sort)
+	}
+}
+
+// Параметры фильтрации, сортировки и пагинации, используемые при получении песен
+type SongFilter struct {
+	ArtistID     int64
+	AlbumID      int64
+	Group        string
+	GroupMatch   MatchMode
+	Song         string
+	SongMatch    MatchMode
+	ReleasedFrom time.Time // Начало диапазона дат релиза (включительно; нулевое значение — без ограничения)
+	ReleasedTo   time.Time // Конец диапазона дат релиза (не включительно; нулевое значение — без ограничения)
+	Text         string    // Слова, которые должны встречаться в тексте песни (в любом порядке и регистре)
+	Link         string
+	Sort         SongSort
+	Desc         bool
+	Offset       int
+	Limit        int
+}
+
+// Вспомогательная сущность для накопления аргументов параметризованного запроса
+type queryArgs struct {
+	args []any
+}
+
+// Метод, добавляющий аргумент и возвращающий его плейсхолдер ($1, $2, ...)
+func (q *queryArgs) add(arg any) string {
+	q.args = append(q.args, arg)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// Метод, формирующий WHERE часть запроса и аргументы к ней (значения фильтра никогда не попадают в текст запроса)
+func (filter SongFilter) whereSQL() (string, []any) {
+	var (
+		q                queryArgs
+		whereExpressions []string
+	)
+
+	if filter.ArtistID != 0 {
+		whereExpressions = append(whereExpressions, "s.artist_id="+q.add(filter.ArtistID))
+	}
+	if filter.AlbumID != 0 {
+		whereExpressions = append(whereExpressions, "s.album_id="+q.add(filter.AlbumID))
+	}
+	if filter.Group != "" {
+		whereExpressions = append(whereExpressions, matchSQL(&q, "a.name", "a.name_key", filter.Group, filter.GroupMatch))
+	}
+	if filter.Song != "" {
+		whereExpressions = append(whereExpressions, matchSQL(&q, "s.song", "s.song_key", filter.Song, filter.SongMatch))
+	}
+	if !filter.ReleasedFrom.IsZero() {
+		whereExpressions = append(whereExpressions, "s.release_date>="+q.add(filter.ReleasedFrom))
+	}
+	if !filter.ReleasedTo.IsZero() {
+		whereExpressions = append(whereExpressions, "s.release_date<"+q.add(filter.ReleasedTo))
+	}
+	if filter.Text != "" {
+		whereExpressions = append(whereExpressions, "s.text_search @@ plainto_tsquery('simple', "+q.add(filter.Text)+")")
+	}
+	if filter.Link != "" {
+		whereExpressions = append(whereExpressions, "s.link="+q.add(filter.Link))
+	}
+
+	if len(whereExpressions) == 0 {
+		return "", nil
+	}
+
+	return "WHERE " + strings.Join(whereExpressions, " AND "), q.args
+}
+
+// Метод, формирующий ORDER BY часть запроса (песни альбома возвращаются в порядке треков)
+func (filter SongFilter) orderSQL() string {
+	if filter.Sort == SortReleaseDate {
+		direction := "ASC"
+		if filter.Desc {
+			direction = "DESC"
+		}
+		return fmt.Sprintf("ORDER BY s.release_date %s NULLS LAST, s.id", direction)
+	}
+	if filter.AlbumID != 0 {
+		return "ORDER BY s.disc_number, s.track_number, s.id"
+	}
+
+	return "ORDER BY s.id"
+}
+
+// Функция, формирующая условие сравнения колонки со значением в зависимости от способа сравнения (полное совпадение сравнивается по колонке ключа названия)
+func matchSQL(q *queryArgs, column, keyColumn, value string, mode MatchMode) string {
+	switch mode {
+	case MatchPrefix:
+		return column + " ILIKE " + q.add(escapeLike(value)+"%")
+	case MatchContains:
+		return column + " ILIKE " + q.add("%"+escapeLike(value)+"%")
+	default:
+		return keyColumn + "=" + q.add(models.NameKey(value))
+	}
+}
+
+// Функция, экранирующая спецсимволы шаблона LIKE, чтобы они сравнивались буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// Метод для проверки, удовлетворяет ли песня фильтру (используется хранилищем в памяти)
+func (filter SongFilter) matches(song *models.Song) bool {
+	if filter.ArtistID != 0 && song.ArtistID != filter.ArtistID {
+		return false
+	}
+	if filter.AlbumID != 0 && (song.AlbumID == nil || *song.AlbumID != filter.AlbumID) {
+		return false
+	}
+	if filter.Group != "" && !matchString(song.Group, filter.Group, filter.GroupMatch) {
+		return false
+	}
+	if filter.Song != "" && !matchString(song.Song, filter.Song, filter.SongMatch) {
+		return false
+	}
+	if !filter.ReleasedFrom.IsZero() || !filter.ReleasedTo.IsZero() {
+		date, ok := songReleaseDate(song)
+		if !ok || (!filter.ReleasedFrom.IsZero() && date.Before(filter.ReleasedFrom)) || (!filter.ReleasedTo.IsZero() && !date.Before(filter.ReleasedTo)) {
+			return false
+		}
+	}
+	if filter.Text != "" && !containsAllWords(song.Text, filter.Text) {
+		return false
+	}
+	if filter.Link != "" && song.Link != filter.Link {
+		return false
+	}
+
+	return true
+}
+
+// Функция, сравнивающая строку со значением фильтра в зависимости от способа сравнения
+func matchString(value, pattern string, mode MatchMode) bool {
+	switch mode {
+	case MatchPrefix:
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(pattern))
+	case MatchContains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(pattern))
+	default:
+		return models.NameKey(value) == models.NameKey(pattern)
+	}
+}
+
+// Метод, сортирующий песни так же, как это делает orderSQL (используется хранилищем в памяти)
+func (filter SongFilter) sort(songs []*models.Song) {
+	if filter.Sort == SortReleaseDate {
+		slices.SortStableFunc(songs, func(a, b *models.Song) int {
+			aDate, aOk := songReleaseDate(a)
+			bDate, bOk := songReleaseDate(b)
+			// Песни без даты идут последними при любом направлении сортировки
+			if !aOk || !bOk {
+				return cmp.Compare(boolToInt(!aOk), boolToInt(!bOk))
+			}
+			if filter.Desc {
+				return bDate.Compare(aDate)
+			}
+			return aDate.Compare(bDate)
+		})
+		return
+	}
+	if filter.AlbumID == 0 {
+		return
+	}
+
+	slices.SortStableFunc(songs, compareTracks)
+}
+
+// Функция, сравнивающая песни по номерам диска и трека (песни без номера идут последними, как NULLS LAST в Postgres)
+func compareTracks(a, b *models.Song) int {
+	return cmp.Or(compareIntPtr(a.DiscNumber, b.DiscNumber), compareIntPtr(a.TrackNumber, b.TrackNumber))
+}
+
+// Функция, сравнивающая nullable числа (nil больше любого числа)
+func compareIntPtr(a, b *int) int {
+	if a == nil || b == nil {
+		return cmp.Compare(boolToInt(a == nil), boolToInt(b == nil))
+	}
+
+	return cmp.Compare(*a, *b)
+}
+
+// Функция, возвращающая значение числа или 0 для nil
+func intOrZero(number *int) int {
+	if number == nil {
+		return 0
+	}
+
+	return *number
+}
+
+// Функция, возвращающая дату релиза песни (false, если дата не задана)
+func songReleaseDate(song *models.Song) (time.Time, bool) {
+	if song.ReleaseDate == "" {
+		return time.Time{}, false
+	}
+
+	date, _, err := models.ParseReleaseDate(song.ReleaseDate)
+	return date, err == nil
+}
+
+// Функция, преобразующая логическое значение в число (для сравнения через cmp)
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
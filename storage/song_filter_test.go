@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"mus_lib/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseMatchMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    MatchMode
+		wantErr bool
+	}{
+		{"", MatchExact, false},
+		{"exact", MatchExact, false},
+		{"prefix", MatchPrefix, false},
+		{"contains", MatchContains, false},
+		{"regexp", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMatchMode(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMatchMode(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseSongSort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    SongSort
+		wantErr bool
+	}{
+		{"", SortDefault, false},
+		{"releaseDate", SortReleaseDate, false},
+		{"title", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSongSort(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSongSort(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSongFilterWhereSQL(t *testing.T) {
+	from := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    SongFilter
+		wantWhere string
+		wantArgs  []any
+	}{
+		{
+			name:      "empty filter",
+			filter:    SongFilter{},
+			wantWhere: "",
+			wantArgs:  nil,
+		},
+		{
+			name:      "exact match compares name keys",
+			filter:    SongFilter{Group: "  AC/DC ", Song: "Back  In Black"},
+			wantWhere: "WHERE a.name_key=$1 AND s.song_key=$2",
+			wantArgs:  []any{"ac/dc", "back in black"},
+		},
+		{
+			name:      "prefix and contains escape like wildcards",
+			filter:    SongFilter{Group: "50%", GroupMatch: MatchPrefix, Song: `a_b\c`, SongMatch: MatchContains},
+			wantWhere: "WHERE a.name ILIKE $1 AND s.song ILIKE $2",
+			wantArgs:  []any{`50\%%`, `%a\_b\\c%`},
+		},
+		{
+			name:      "value is never spliced into query",
+			filter:    SongFilter{Song: "x'; DROP TABLE songs; --"},
+			wantWhere: "WHERE s.song_key=$1",
+			wantArgs:  []any{"x'; drop table songs; --"},
+		},
+		{
+			name:      "ids, dates, text and link",
+			filter:    SongFilter{ArtistID: 3, AlbumID: 4, ReleasedFrom: from, ReleasedTo: to, Text: "love", Link: "https://example.com"},
+			wantWhere: "WHERE s.artist_id=$1 AND s.album_id=$2 AND s.release_date>=$3 AND s.release_date<$4 AND s.text_search @@ plainto_tsquery('simple', $5) AND s.link=$6",
+			wantArgs:  []any{int64(3), int64(4), from, to, "love", "https://example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := tt.filter.whereSQL()
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSongFilterOrderSQL(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter SongFilter
+		want   string
+	}{
+		{"default", SongFilter{}, "ORDER BY s.id"},
+		{"album tracks", SongFilter{AlbumID: 1}, "ORDER BY s.disc_number, s.track_number, s.id"},
+		{"release date", SongFilter{Sort: SortReleaseDate}, "ORDER BY s.release_date ASC NULLS LAST, s.id"},
+		{"release date desc", SongFilter{Sort: SortReleaseDate, Desc: true, AlbumID: 1}, "ORDER BY s.release_date DESC NULLS LAST, s.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.orderSQL(); got != tt.want {
+				t.Errorf("orderSQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSongFilterMatches(t *testing.T) {
+	albumID := int64(7)
+	song := &models.Song{
+		ArtistID:    1,
+		Group:       "AC/DC",
+		Song:        "Back In Black",
+		ReleaseDate: "07.1980",
+		Text:        []string{"Back in black", "I hit the sack"},
+		Link:        "https://example.com/bib",
+		AlbumID:     &albumID,
+	}
+
+	tests := []struct {
+		name   string
+		filter SongFilter
+		want   bool
+	}{
+		{"empty filter", SongFilter{}, true},
+		{"artist", SongFilter{ArtistID: 1}, true},
+		{"other artist", SongFilter{ArtistID: 2}, false},
+		{"album", SongFilter{AlbumID: 7}, true},
+		{"other album", SongFilter{AlbumID: 8}, false},
+		{"exact group ignores case and spaces", SongFilter{Group: " ac/dc "}, true},
+		{"exact song is not prefix", SongFilter{Song: "back"}, false},
+		{"prefix", SongFilter{Song: "back", SongMatch: MatchPrefix}, true},
+		{"contains", SongFilter{Song: "IN BL", SongMatch: MatchContains}, true},
+		{"released in range", SongFilter{ReleasedFrom: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), ReleasedTo: time.Date(1981, 1, 1, 0, 0, 0, 0, time.UTC)}, true},
+		{"released before range end is exclusive", SongFilter{ReleasedTo: time.Date(1980, 7, 1, 0, 0, 0, 0, time.UTC)}, false},
+		{"text words in any order", SongFilter{Text: "sack hit"}, true},
+		{"text word missing", SongFilter{Text: "white"}, false},
+		{"link", SongFilter{Link: "https://example.com/bib"}, true},
+		{"other link", SongFilter{Link: "https://example.com"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(song); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSongFilterMatchesWithoutReleaseDate(t *testing.T) {
+	filter := SongFilter{ReleasedFrom: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if filter.matches(&models.Song{}) {
+		t.Error("song without release date matches date range")
+	}
+}
+
+func TestSongFilterSort(t *testing.T) {
+	number := func(n int) *int { return &n }
+
+	tests := []struct {
+		name   string
+		filter SongFilter
+		songs  []*models.Song
+		want   []int64
+	}{
+		{
+			name:   "default keeps order",
+			filter: SongFilter{},
+			songs:  []*models.Song{{ID: 2}, {ID: 1}},
+			want:   []int64{2, 1},
+		},
+		{
+			name:   "release date ascending, songs without date last",
+			filter: SongFilter{Sort: SortReleaseDate},
+			songs:  []*models.Song{{ID: 1}, {ID: 2, ReleaseDate: "2001"}, {ID: 3, ReleaseDate: "05.1999"}},
+			want:   []int64{3, 2, 1},
+		},
+		{
+			name:   "release date descending, songs without date still last",
+			filter: SongFilter{Sort: SortReleaseDate, Desc: true},
+			songs:  []*models.Song{{ID: 1}, {ID: 2, ReleaseDate: "05.1999"}, {ID: 3, ReleaseDate: "2001"}},
+			want:   []int64{3, 2, 1},
+		},
+		{
+			name:   "album tracks, songs without numbers last",
+			filter: SongFilter{AlbumID: 1},
+			songs: []*models.Song{
+				{ID: 1, DiscNumber: number(1)},
+				{ID: 2, DiscNumber: number(2), TrackNumber: number(1)},
+				{ID: 3, DiscNumber: number(1), TrackNumber: number(2)},
+				{ID: 4},
+				{ID: 5, DiscNumber: number(1), TrackNumber: number(1)},
+			},
+			want: []int64{5, 3, 1, 2, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.sort(tt.songs)
+
+			got := make([]int64, 0, len(tt.songs))
+			for _, song := range tt.songs {
+				got = append(got, song.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sorted ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
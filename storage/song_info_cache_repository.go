@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"mus_lib/internal/app/models"
+)
+
+// Сущность модельного репозитория кэша ответов стороннего API
+type SongInfoCacheRepository struct {
+	storage *Storage
+}
+
+// Метод, возвращающий неистекший ответ стороннего API по ключу (sql.ErrNoRows, если его нет)
+func (r *SongInfoCacheRepository) GetCachedSongInfo(groupKey, songKey string) (*models.SongInfoCacheEntry, error) {
+	entry := models.SongInfoCacheEntry{GroupKey: groupKey, SongKey: songKey}
+
+	err := r.storage.db.QueryRow(`SELECT found, release_date, text, link, expires_at FROM song_info_cache WHERE group_key=$1 AND song_key=$2 AND expires_at > now()`, groupKey, songKey).
+		Scan(&entry.Found, &entry.ReleaseDate, &entry.Text, &entry.Link, &entry.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Метод, сохраняющий ответ стороннего API (если ответ по этому ключу уже есть, он заменяется)
+func (r *SongInfoCacheRepository) PutCachedSongInfo(entry *models.SongInfoCacheEntry) error {
+	_, err := r.storage.db.Exec(`INSERT INTO song_info_cache (group_key, song_key, found, release_date, text, link, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (group_key, song_key) DO UPDATE SET found=EXCLUDED.found, release_date=EXCLUDED.release_date, text=EXCLUDED.text, link=EXCLUDED.link, expires_at=EXCLUDED.expires_at`,
+		entry.GroupKey, entry.SongKey, entry.Found, entry.ReleaseDate, entry.Text, entry.Link, entry.ExpiresAt)
+
+	return err
+}
+
+// Метод, удаляющий истекшие ответы стороннего API
+func (r *SongInfoCacheRepository) DeleteExpiredSongInfo() (int64, error) {
+	res, err := r.storage.db.Exec(`DELETE FROM song_info_cache WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -1,9 +1,11 @@
 package storage
 
 import (
+	"database/sql"
 	"fmt"
 	"mus_lib/internal/app/models"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/lib/pq"
@@ -12,11 +14,69 @@ import (
 // Сущность модельного репозитория
 type SongRepository struct {
 	storage *Storage // Хранит в себе БД, т.к. общение с БД реализовано посредством репозитория (небольшое замыкание)
+	author  string   // Автор изменений, который записывается в историю изменений песен
+}
+
+// Метод, возвращающий репозиторий, изменения через который записываются в историю от имени author
+func (s *SongRepository) WithAuthor(author string) SongStore {
+	return &SongRepository{storage: s.storage, author: author}
+}
+
+// Колонки песни, извлекаемые из БД (порядок соответствует scanSong)
+const songColumns = `s.id, s.artist_id, a.name, s.song, s.release_date, s.release_date_precision, s.text, s.link, s.album_id, s.disc_number, s.track_number, s.version, s.deleted_at`
+
+// Запрос, возвращающий идентификатор исполнителя по ключу названия $1 (исполнитель создается с названием $2, если его еще нет)
+const upsertArtistQuery = `INSERT INTO artists (name, name_key, display_name) VALUES ($2, $1, $2) ON CONFLICT (name_key) DO UPDATE SET name_key=EXCLUDED.name_key RETURNING id`
+
+// Интерфейс строки результата запроса (реализуется *sql.Row и *sql.Rows)
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// Функция, возвращающая название таблицы песен (задается в конфигурации)
+func songsTableName() string {
+	return os.Getenv("TABLE_NAME")
+}
+
+// Функция, возвращающая источник данных для выборки песен (песни вместе с их исполнителями, без песен в корзине)
+func songsTable() string {
+	return fmt.Sprintf(`%s s JOIN artists a ON a.id=s.artist_id AND s.deleted_at IS NULL`, songsTableName())
+}
+
+// Функция, считывающая песню из строки результата запроса (extra - приемники для колонок, идущих после колонок песни)
+func scanSong(row scanner, extra ...any) (*models.Song, error) {
+	song := models.Song{}
+
+	var (
+		releaseDate                      releaseDateColumns
+		albumID, discNumber, trackNumber sql.NullInt64
+		deletedAt                        sql.NullTime
+	)
+	dest := []any{&song.ID, &song.ArtistID, &song.Group, &song.Song, &releaseDate.date, &releaseDate.precision, pq.Array(&song.Text), &song.Link, &albumID, &discNumber, &trackNumber, &song.Version, &deletedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	song.ReleaseDate = releaseDate.String()
+	if deletedAt.Valid {
+		song.DeletedAt = &deletedAt.Time
+	}
+	if albumID.Valid {
+		song.AlbumID = &albumID.Int64
+		song.DiscNumber = nullIntPtr(discNumber)
+		song.TrackNumber = nullIntPtr(trackNumber)
+	}
+
+	return &song, nil
 }
 
 // Метод для получения всех песен из БД
-func (s *SongRepository) GetSongs(query string) ([]*models.Song, error) {
-	res, err := s.storage.db.Query(query)
+func (s *SongRepository) GetSongs(filter SongFilter) ([]*models.Song, error) {
+	// Формируем параметризованный запрос в БД
+	where, args := filter.whereSQL()
+	query := fmt.Sprintf(`SELECT %s FROM %s %s %s OFFSET %d LIMIT %d`, songColumns, songsTable(), where, filter.orderSQL(), filter.Offset, filter.Limit)
+
+	res, err := s.storage.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -25,64 +85,324 @@ func (s *SongRepository) GetSongs(query string) ([]*models.Song, error) {
 	songs := make([]*models.Song, 0)
 
 	for res.Next() {
-		song := models.Song{}
-		err := res.Scan(&song.Group, &song.Song, &song.ReleaseDate, pq.Array(&song.Text), &song.Link)
+		song, err := scanSong(res)
 		if err != nil {
 			continue
 		}
 
-		songs = append(songs, &song)
+		songs = append(songs, song)
 	}
 
 	return songs, nil
 }
 
-// Метод для получения текста песни из БД
-func (s *SongRepository) GetSongText(group, song string, offset, limit int) ([]string, error) {
-	query := fmt.Sprintf(`SELECT text[$1:$2] FROM %s WHERE "group"=$3 AND song=$4`, os.Getenv("TABLE_NAME"))
-	res := s.storage.db.QueryRow(query, offset+1, limit+offset, strings.ToLower(group), strings.ToLower(song))
+// Метод для полнотекстового поиска песен по тексту (поддерживает синтаксис websearch_to_tsquery: "фраза", or, -слово)
+func (s *SongRepository) SearchSongs(query string, offset, limit int) ([]*models.SongSearchResult, error) {
+	// Песня подбирается по GIN индексу, а затем запрос проверяется отдельно для каждого куплета, чтобы совпадение
+	// (например, фраза) не склеивалось из соседних куплетов и было видно, в каком куплете оно найдено
+	sqlQuery := fmt.Sprintf(`SELECT %s, ts_rank(s.text_search, q.query) AS rank, m.verses, m.snippets
+		FROM %s CROSS JOIN websearch_to_tsquery('simple', $1) AS q(query)
+		CROSS JOIN LATERAL (
+			SELECT array_agg(v.n - 1 ORDER BY v.n) AS verses, array_agg(ts_headline('simple', v.verse, q.query) ORDER BY v.n) AS snippets
+			FROM unnest(s.text) WITH ORDINALITY AS v(verse, n)
+			WHERE to_tsvector('simple', v.verse) @@ q.query
+		) AS m
+		WHERE s.text_search @@ q.query AND m.verses IS NOT NULL
+		ORDER BY rank DESC, s.id OFFSET %d LIMIT %d`, songColumns, songsTable(), offset, limit)
+
+	res, err := s.storage.db.Query(sqlQuery, query)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	results := make([]*models.SongSearchResult, 0)
+
+	for res.Next() {
+		var (
+			result   models.SongSearchResult
+			verses   []int64
+			snippets []string
+		)
+		result.Song, err = scanSong(res, &result.Rank, pq.Array(&verses), pq.Array(&snippets))
+		if err != nil {
+			continue
+		}
+		for i := range verses {
+			result.Matches = append(result.Matches, models.VerseMatch{Verse: int(verses[i]), Snippet: snippets[i]})
+		}
+
+		results = append(results, &result)
+	}
+
+	return results, nil
+}
 
-	var text []string
+// Метод для нечеткого поиска песен по названию исполнителя или песни (порог сходства задается настройкой pg_trgm.similarity_threshold)
+func (s *SongRepository) SuggestSongs(query string, offset, limit int) ([]*models.SongSuggestion, error) {
+	sqlQuery := fmt.Sprintf(`SELECT %s, GREATEST(similarity(s.song, $1), similarity(a.name, $1)) AS score
+		FROM %s WHERE s.song %% $1 OR a.name %% $1
+		ORDER BY score DESC, s.id OFFSET %d LIMIT %d`, songColumns, songsTable(), offset, limit)
 
-	err := res.Scan(pq.Array(&text))
+	res, err := s.storage.db.Query(sqlQuery, strings.ToLower(strings.TrimSpace(query)))
 	if err != nil {
 		return nil, err
 	}
+	defer res.Close()
 
-	return text, nil
+	return scanSuggestions(res)
 }
 
-// Метод для изменения песни в БД
-func (s *SongRepository) UpdateSong(newgroup, newsong, oldgroup, oldsong string) error {
-	query := fmt.Sprintf(`UPDATE %s SET "group"=$1, song=$2 WHERE "group"=$3 AND song=$4`, os.Getenv("TABLE_NAME"))
+// Метод для поиска песен, похожих на добавляемую (и исполнитель, и название должны быть похожи не меньше порога)
+func (s *SongRepository) FindSimilarSongs(group, song string, threshold float64, limit int) ([]*models.SongSuggestion, error) {
+	var suggestions []*models.SongSuggestion
 
-	_, err := s.storage.db.Exec(query, strings.ToLower(newgroup), strings.ToLower(newsong), strings.ToLower(oldgroup), strings.ToLower(oldsong))
-	return err
+	err := s.storage.inTx(func(tx *sql.Tx) error {
+		// Порог оператора % действует только внутри транзакции, поэтому поиск по индексу учитывает заданный порог
+		_, err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', $1, true)`, strconv.FormatFloat(threshold, 'f', -1, 64))
+		if err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf(`SELECT %s, LEAST(similarity(a.name, $1), similarity(s.song, $2)) AS score
+			FROM %s WHERE a.name %% $1 AND s.song %% $2
+			ORDER BY score DESC, s.id LIMIT %d`, songColumns, songsTable(), limit)
+
+		res, err := tx.Query(query, strings.ToLower(strings.TrimSpace(group)), strings.ToLower(strings.TrimSpace(song)))
+		if err != nil {
+			return err
+		}
+		defer res.Close()
+
+		suggestions, err = scanSuggestions(res)
+		return err
+	})
+
+	return suggestions, err
 }
 
-// Метод для удаления песни из БД
-func (s *SongRepository) DeleteSong(group string, song string) error {
-	query := fmt.Sprintf(`DELETE FROM %s WHERE "group"=$1 AND song=$2`, os.Getenv("TABLE_NAME"))
+// Функция, считывающая песни вместе с их сходством с запросом
+func scanSuggestions(res *sql.Rows) ([]*models.SongSuggestion, error) {
+	suggestions := make([]*models.SongSuggestion, 0)
 
-	_, err := s.storage.db.Exec(query, strings.ToLower(group), strings.ToLower(song))
-	return err
+	for res.Next() {
+		var (
+			suggestion models.SongSuggestion
+			err        error
+		)
+		suggestion.Song, err = scanSong(res, &suggestion.Similarity)
+		if err != nil {
+			continue
+		}
+
+		suggestions = append(suggestions, &suggestion)
+	}
+
+	return suggestions, res.Err()
+}
+
+// Метод для получения песни из БД по ее идентификатору
+func (s *SongRepository) GetSong(id int64) (*models.Song, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE s.id=$1`, songColumns, songsTable())
+
+	return scanSong(s.storage.db.QueryRow(query, id))
+}
+
+// Метод для получения текста песни из БД вместе с версией песни
+func (s *SongRepository) GetSongText(id int64, offset, limit int) ([]string, int64, error) {
+	query := fmt.Sprintf(`SELECT text[$1:$2], version FROM %s WHERE id=$3 AND deleted_at IS NULL`, os.Getenv("TABLE_NAME"))
+	res := s.storage.db.QueryRow(query, offset+1, limit+offset, id)
+
+	var (
+		text    []string
+		version int64
+	)
+
+	err := res.Scan(pq.Array(&text), &version)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return text, version, nil
 }
 
-// Метод для добавления песни в БД
+// Метод для изменения всех полей песни song.ID в БД (исполнитель создается, если его еще нет; sql.ErrNoRows, если песня не найдена; ErrStaleVersion, если версия не равна song.Version)
+func (s *SongRepository) UpdateSong(song *models.Song) error {
+	releaseDate, precision, err := releaseDateArgs(song.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`WITH artist AS (%s) UPDATE %s SET artist_id=(SELECT id FROM artist), song=$3, song_key=$13, release_date=$4, release_date_precision=$5, text=$6, link=$7, album_id=$8, disc_number=$9, track_number=$10 WHERE id=$11 AND deleted_at IS NULL AND ($12::bigint=0 OR version=$12) RETURNING version`, upsertArtistQuery, os.Getenv("TABLE_NAME"))
+
+	// Версия проверяется в том же запросе, что и изменение, поэтому между проверкой и записью никто не успеет изменить песню
+	return s.storage.inTxAs(s.author, func(tx *sql.Tx) error {
+		err := tx.QueryRow(query, models.NameKey(song.Group), song.Group, song.Song, releaseDate, precision, pq.Array(song.Text), song.Link, song.AlbumID, song.DiscNumber, song.TrackNumber, song.ID, song.Version, models.NameKey(song.Song)).Scan(&song.Version)
+		if err == sql.ErrNoRows {
+			return staleOrMissing(tx, song.ID)
+		}
+		// Нарушение внешнего ключа при изменении означает, что альбома не существует, а нарушение уникальности - что такая песня уже есть или номер трека занят
+		if err = convertError(err); err == ErrInUse {
+			return ErrBrokenReference
+		}
+
+		return err
+	})
+}
+
+// Метод для изменения даты релиза, текста и ссылки песни в БД (sql.ErrNoRows, если песня не найдена; ErrStaleVersion, если версия не равна song.Version)
+func (s *SongRepository) UpdateSongInfo(song *models.Song) error {
+	return s.storage.inTxAs(s.author, func(tx *sql.Tx) error {
+		return updateSongInfo(tx, song)
+	})
+}
+
+// Метод для удаления песни в корзину (sql.ErrNoRows, если песня не найдена; ErrStaleVersion, если версия не равна version)
+func (s *SongRepository) DeleteSong(id int64, version int64) error {
+	query := fmt.Sprintf(`UPDATE %s SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL AND ($2::bigint=0 OR version=$2)`, os.Getenv("TABLE_NAME"))
+
+	return s.storage.inTxAs(s.author, func(tx *sql.Tx) error {
+		res, err := tx.Exec(query, id, version)
+		if err != nil {
+			return err
+		}
+
+		err = checkAffected(res)
+		if err == sql.ErrNoRows {
+			return staleOrMissing(tx, id)
+		}
+
+		return err
+	})
+}
+
+// Функция, выясняющая, почему условное изменение не затронуло песню: ее нет (sql.ErrNoRows) или у нее другая версия (ErrStaleVersion)
+func staleOrMissing(db queryRower, id int64) error {
+	query := fmt.Sprintf(`SELECT 1 FROM %s WHERE id=$1 AND deleted_at IS NULL`, os.Getenv("TABLE_NAME"))
+
+	var exists int
+
+	err := db.QueryRow(query, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	return ErrStaleVersion
+}
+
+// Метод для повторного добавления удаленной песни в БД с прежним идентификатором (версия продолжает историю изменений песни)
+func (s *SongRepository) RestoreSong(song *models.Song) error {
+	releaseDate, precision, err := releaseDateArgs(song.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`WITH artist AS (%s) INSERT INTO %s (id, artist_id, song, song_key, release_date, release_date_precision, text, link, album_id, disc_number, track_number, version)
+		SELECT $3, id, $4, $12, $5, $6, $7, $8, $9, $10, $11, (SELECT coalesce(max(revision), 0) + 1 FROM song_revisions WHERE song_id=$3) FROM artist RETURNING artist_id, version`, upsertArtistQuery, os.Getenv("TABLE_NAME"))
+
+	return s.storage.inTxAs(s.author, func(tx *sql.Tx) error {
+		// Песню из корзины нужно сначала вернуть из нее (ее идентификатор занят)
+		var trashed bool
+		err := tx.QueryRow(fmt.Sprintf(`SELECT deleted_at IS NOT NULL FROM %s WHERE id=$1`, os.Getenv("TABLE_NAME")), song.ID).Scan(&trashed)
+		if err == nil && trashed {
+			return ErrWrongState
+		}
+		if err == nil {
+			return ErrAlreadyExists
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		err = tx.QueryRow(query, models.NameKey(song.Group), song.Group, song.ID, song.Song, releaseDate, precision, pq.Array(song.Text), song.Link, song.AlbumID, song.DiscNumber, song.TrackNumber, models.NameKey(song.Song)).Scan(&song.ArtistID, &song.Version)
+		// Нарушение внешнего ключа означает, что альбома уже не существует
+		if err = convertError(err); err == ErrInUse {
+			return ErrBrokenReference
+		}
+
+		return err
+	})
+}
+
+// Метод для добавления песни в БД (ErrAlreadyExists, если такая песня уже есть; исполнитель создается, если его еще нет; идентификаторы и версия записываются в song.ID, song.ArtistID и song.Version)
 func (s *SongRepository) AddSong(song *models.Song) error {
-	query := fmt.Sprintf(`INSERT INTO %s VALUES ($1, $2, $3, $4, $5)`, os.Getenv("TABLE_NAME"))
+	return s.storage.inTxAs(s.author, func(tx *sql.Tx) error {
+		return addSong(tx, song)
+	})
+}
+
+// Интерфейс, через который выполняются запросы (реализуется *sql.DB и *sql.Tx)
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// Функция, добавляющая песню (используется как отдельно, так и в рамках транзакции)
+func addSong(db queryRower, song *models.Song) error {
+	releaseDate, precision, err := releaseDateArgs(song.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`WITH artist AS (%s) INSERT INTO %s (artist_id, song, song_key, release_date, release_date_precision, text, link) SELECT id, $3, $8, $4, $5, $6, $7 FROM artist RETURNING id, artist_id, version`, upsertArtistQuery, os.Getenv("TABLE_NAME"))
+
+	// Уникальность песни проверяет уникальный индекс по ключу названия (ErrAlreadyExists), поэтому одновременные добавления не создадут дубликат
+	err = db.QueryRow(query, models.NameKey(song.Group), song.Group, song.Song, releaseDate, precision, pq.Array(song.Text), song.Link, models.NameKey(song.Song)).Scan(&song.ID, &song.ArtistID, &song.Version)
+	return convertError(err)
+}
+
+// Функция, изменяющая дату релиза, текст и ссылку песни, если ее версия равна song.Version (0 - без проверки), и записывающая новую версию в song.Version (используется как отдельно, так и в рамках транзакции)
+func updateSongInfo(db queryRower, song *models.Song) error {
+	releaseDate, precision, err := releaseDateArgs(song.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET release_date=$1, release_date_precision=$2, text=$3, link=$4 WHERE id=$5 AND deleted_at IS NULL AND ($6::bigint=0 OR version=$6) RETURNING version`, os.Getenv("TABLE_NAME"))
+
+	err = db.QueryRow(query, releaseDate, precision, pq.Array(song.Text), song.Link, song.ID, song.Version).Scan(&song.Version)
+	if err == sql.ErrNoRows {
+		return staleOrMissing(db, song.ID)
+	}
 
-	_, err := s.storage.db.Exec(query, strings.ToLower(song.Group), strings.ToLower(song.Song), song.ReleaseDate, pq.Array(song.Text), song.Link)
 	return err
 }
 
 // Метод для проверки наличия песни в БД
 func (s *SongRepository) CheckSong(group string, song string) error {
-	query := fmt.Sprintf(`SELECT "group", song FROM %s WHERE "group"=$1 AND song=$2`, os.Getenv("TABLE_NAME"))
-	res := s.storage.db.QueryRow(query, strings.ToLower(group), strings.ToLower(song))
+	_, err := s.FindSongID(group, song)
+	return err
+}
 
-	songTemp := models.Song{}
+// Метод для получения идентификатора песни по названиям исполнителя и песни
+func (s *SongRepository) FindSongID(group string, song string) (int64, error) {
+	query := fmt.Sprintf(`SELECT s.id FROM %s WHERE a.name_key=$1 AND s.song_key=$2`, songsTable())
+	res := s.storage.db.QueryRow(query, models.NameKey(group), models.NameKey(song))
 
-	err := res.Scan(&songTemp.Group, &songTemp.Song)
-	return err
+	var id int64
+
+	err := res.Scan(&id)
+	return id, err
+}
+
+// Функция, преобразующая nullable число из БД в указатель (nil для NULL)
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+
+	number := int(value.Int64)
+	return &number
+}
+
+// Функция, возвращающая sql.ErrNoRows, если запрос не затронул ни одной строки
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
@@ -8,13 +8,21 @@ import (
 	"os"
 
 	_ "github.com/lib/pq"
+	"github.com/pressly/goose/v3"
 )
 
 // Инстанс хранилища для приложения
 type Storage struct {
 	// Поля неэкспортируемые (конфендициальная информация)
-	db             *sql.DB         // Сущность, представляющая собой мост между нашим приложением и БД
-	songRepository *SongRepository // Модельный репозиторий, через который будет проводиться работа с БД
+	db                 *sql.DB                  // Сущность, представляющая собой мост между нашим приложением и БД
+	songRepository     *SongRepository          // Модельный репозиторий, через который будет проводиться работа с БД
+	artistRepository   *ArtistRepository        // Модельный репозиторий исполнителей
+	albumRepository    *AlbumRepository         // Модельный репозиторий альбомов
+	jobRepository      *JobRepository           // Модельный репозиторий заданий на заполнение информации о песнях
+	songInfoCache      *SongInfoCacheRepository // Модельный репозиторий кэша ответов стороннего API
+	revisionRepository *RevisionRepository      // Модельный репозиторий истории изменений песен
+	playlistRepository *PlaylistRepository      // Модельный репозиторий плейлистов
+	userRepository     *UserRepository          // Модельный репозиторий пользователей
 }
 
 // Конструктор, возвращающий инстанс нашего хранилища
@@ -50,16 +58,61 @@ func (storage *Storage) Close() {
 	storage.db.Close()
 }
 
-// Метод, создающий таблицу в нашей БД (накатывающий миграцию)
-func (storage *Storage) CreateTable() error {
-	ctx := context.Background()
+// Метод, выполняющий функцию в рамках транзакции (транзакция откатывается, если функция вернула ошибку)
+func (storage *Storage) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := storage.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Метод, выполняющий функцию в рамках транзакции от имени автора изменений (триггер записывает его в историю изменений песен)
+func (storage *Storage) inTxAs(author string, fn func(tx *sql.Tx) error) error {
+	return storage.inTx(func(tx *sql.Tx) error {
+		if author != "" {
+			_, err := tx.Exec(`SELECT set_config('music_library.author', $1, true)`, author)
+			if err != nil {
+				return err
+			}
+		}
 
-	err := migrations.Up(ctx, storage.db)
-	return err
+		return fn(tx)
+	})
+}
+
+// Метод, создающий goose провайдер для управления миграциями нашей БД
+func (storage *Storage) Migrations() (*goose.Provider, error) {
+	return migrations.NewProvider(storage.db)
+}
+
+// Метод, проверяющий, что схема БД соответствует последней версии миграций (сервер не должен работать со старой схемой)
+func (storage *Storage) CheckMigrations() error {
+	provider, err := storage.Migrations()
+	if err != nil {
+		return err
+	}
+
+	current, target, err := provider.GetVersions(context.Background())
+	if err != nil {
+		return err
+	}
+	if current < target {
+		return fmt.Errorf("database schema is behind: current version %d, latest version %d (run 'music_library migrate up')", current, target)
+	}
+
+	return nil
 }
 
 // Метод, создающий публичный репозиторий для Song
-func (storage *Storage) Song() *SongRepository {
+func (storage *Storage) Song() SongStore {
 	if storage.songRepository != nil {
 		return storage.songRepository
 	}
@@ -70,3 +123,94 @@ func (storage *Storage) Song() *SongRepository {
 
 	return storage.songRepository
 }
+
+// Метод, создающий публичный репозиторий для Artist
+func (storage *Storage) Artist() ArtistStore {
+	if storage.artistRepository != nil {
+		return storage.artistRepository
+	}
+
+	storage.artistRepository = &ArtistRepository{
+		storage: storage,
+	}
+
+	return storage.artistRepository
+}
+
+// Метод, создающий публичный репозиторий для Album
+func (storage *Storage) Album() AlbumStore {
+	if storage.albumRepository != nil {
+		return storage.albumRepository
+	}
+
+	storage.albumRepository = &AlbumRepository{
+		storage: storage,
+	}
+
+	return storage.albumRepository
+}
+
+// Метод, создающий публичный репозиторий для Job
+func (storage *Storage) Job() JobStore {
+	if storage.jobRepository != nil {
+		return storage.jobRepository
+	}
+
+	storage.jobRepository = &JobRepository{
+		storage: storage,
+	}
+
+	return storage.jobRepository
+}
+
+// Метод, создающий публичный репозиторий для кэша ответов стороннего API
+func (storage *Storage) SongInfoCache() SongInfoCacheStore {
+	if storage.songInfoCache != nil {
+		return storage.songInfoCache
+	}
+
+	storage.songInfoCache = &SongInfoCacheRepository{
+		storage: storage,
+	}
+
+	return storage.songInfoCache
+}
+
+// Метод, создающий публичный репозиторий для истории изменений песен
+func (storage *Storage) Revision() RevisionStore {
+	if storage.revisionRepository != nil {
+		return storage.revisionRepository
+	}
+
+	storage.revisionRepository = &RevisionRepository{
+		storage: storage,
+	}
+
+	return storage.revisionRepository
+}
+
+// Метод, создающий публичный репозиторий для Playlist
+func (storage *Storage) Playlist() PlaylistStore {
+	if storage.playlistRepository != nil {
+		return storage.playlistRepository
+	}
+
+	storage.playlistRepository = &PlaylistRepository{
+		storage: storage,
+	}
+
+	return storage.playlistRepository
+}
+
+// Метод, создающий публичный репозиторий для User
+func (storage *Storage) User() UserStore {
+	if storage.userRepository != nil {
+		return storage.userRepository
+	}
+
+	storage.userRepository = &UserRepository{
+		storage: storage,
+	}
+
+	return storage.userRepository
+}
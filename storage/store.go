@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"mus_lib/internal/app/models"
+	"time"
+)
+
+// Интерфейс хранилища, с которым работает сервер (позволяет подменять Postgres на хранилище в памяти)
+type Store interface {
+	Open() error                       // Открывает соединение с хранилищем
+	Close()                            // Закрывает соединение с хранилищем
+	CheckMigrations() error            // Проверяет, что хранилище готово к работе (схема не отстает от миграций)
+	Song() SongStore                   // Возвращает репозиторий для работы с песнями
+	Artist() ArtistStore               // Возвращает репозиторий для работы с исполнителями
+	Album() AlbumStore                 // Возвращает репозиторий для работы с альбомами
+	Job() JobStore                     // Возвращает репозиторий для работы с заданиями на заполнение информации о песнях
+	SongInfoCache() SongInfoCacheStore // Возвращает репозиторий кэша ответов стороннего API
+	Revision() RevisionStore           // Возвращает репозиторий истории изменений песен
+	Playlist() PlaylistStore           // Возвращает репозиторий для работы с плейлистами
+	User() UserStore                   // Возвращает репозиторий пользователей и их API-ключей
+}
+
+// Интерфейс репозитория песен, который должен реализовывать каждый вид хранилища
+type SongStore interface {
+	CheckSong(group, song string) error                                                                  // Проверяет наличие песни (sql.ErrNoRows, если песня не найдена)
+	FindSongID(group, song string) (int64, error)                                                        // Возвращает идентификатор песни по названиям исполнителя и песни
+	AddSong(song *models.Song) error                                                                     // Добавляет песню и записывает ее идентификатор в song.ID
+	ImportSongs(songs []*models.Song, enrich bool) ([]ImportedSong, error)                               // Добавляет песни одним запросом (существующие пропускаются) и, если enrich, создает задания на заполнение недостающей информации
+	GetSongs(filter SongFilter) ([]*models.Song, error)                                                  // Возвращает песни, удовлетворяющие фильтру
+	ExportSongs(filter SongFilter, fn func(song *models.Song) error) error                               // Передает fn по очереди все песни, удовлетворяющие фильтру (без пагинации), и останавливается на первой ошибке fn
+	SearchSongs(query string, offset, limit int) ([]*models.SongSearchResult, error)                     // Ищет песни по тексту (самые релевантные первыми)
+	SuggestSongs(query string, offset, limit int) ([]*models.SongSuggestion, error)                      // Ищет песни, название или исполнитель которых похожи на запрос
+	FindSimilarSongs(group, song string, threshold float64, limit int) ([]*models.SongSuggestion, error) // Ищет песни, у которых и исполнитель, и название похожи на заданные не меньше порога
+	GetSong(id int64) (*models.Song, error)                                                              // Возвращает песню по идентификатору
+	GetSongText(id int64, offset, limit int) ([]string, int64, error)                                    // Возвращает куплеты песни с учетом пагинации и версию песни
+	UpdateSong(song *models.Song) error                                                                  // Изменяет все поля песни song.ID, если ее версия равна song.Version (0 - без проверки; ErrStaleVersion, если версия другая; ErrBrokenReference, если альбома нет; ErrAlreadyExists, если номер трека занят) и записывает новую версию в song.Version
+	UpdateSongInfo(song *models.Song) error                                                              // Изменяет дату релиза, текст и ссылку песни song.ID, если ее версия равна song.Version (0 - без проверки; ErrStaleVersion, если версия другая), и записывает новую версию в song.Version
+	DeleteSong(id int64, version int64) error                                                            // Удаляет песню в корзину, если ее версия равна version (0 - без проверки; ErrStaleVersion, если версия другая)
+	GetTrash(offset, limit int) ([]*models.Song, error)                                                  // Возвращает песни из корзины с учетом пагинации (недавно удаленные первыми)
+	UndeleteSong(id int64) error                                                                         // Возвращает песню из корзины (sql.ErrNoRows, если ее там нет; ErrAlreadyExists, если такая песня уже есть или номер трека занят)
+	PurgeDeletedSongs(before time.Time) (int64, error)                                                   // Окончательно удаляет песни, попавшие в корзину раньше before, и возвращает их количество
+	InsertVerse(id, version int64, position int, verse string) (int, int64, error)                       // Вставляет куплет перед куплетом position (в конец, если position < 0), если версия песни равна version (0 - без проверки; ErrStaleVersion, если версия другая), и возвращает номер куплета и новую версию
+	ReplaceVerse(id, version int64, position int, verse string) (int64, error)                           // Заменяет куплет position (ErrOutOfRange, если такого куплета нет; версия проверяется, как у InsertVerse) и возвращает новую версию
+	DeleteVerse(id, version int64, position int) (int64, error)                                          // Удаляет куплет position (ErrOutOfRange, если такого куплета нет; версия проверяется, как у InsertVerse) и возвращает новую версию
+	ReorderVerses(id, version int64, order []int) (int64, error)                                         // Переставляет куплеты: order[i] - прежний номер куплета, который станет i-м (ErrOutOfRange, если order не перестановка всех куплетов; версия проверяется, как у InsertVerse) и возвращает новую версию
+	RestoreSong(song *models.Song) error                                                                 // Добавляет удаленную песню заново с прежним идентификатором song.ID и записывает ее версию в song.Version (ErrAlreadyExists, если песня есть; ErrWrongState, если песня в корзине)
+	WithAuthor(author string) SongStore                                                                  // Возвращает репозиторий, изменения через который записываются в историю от имени author
+}
+
+// Интерфейс репозитория исполнителей, который должен реализовывать каждый вид хранилища
+type ArtistStore interface {
+	GetArtists(offset, limit int) ([]*models.Artist, error) // Возвращает исполнителей с учетом пагинации
+	GetArtist(id int64) (*models.Artist, error)             // Возвращает исполнителя по идентификатору
+	AddArtist(artist *models.Artist) error                  // Добавляет исполнителя и записывает его идентификатор в artist.ID
+	UpdateArtist(artist *models.Artist) error               // Изменяет исполнителя (вместе с ним меняются все его песни)
+	DeleteArtist(id int64) error                            // Удаляет исполнителя, у которого нет песен
+}
+
+// Интерфейс репозитория альбомов, который должен реализовывать каждый вид хранилища
+type AlbumStore interface {
+	GetAlbums(offset, limit int) ([]*models.Album, error) // Возвращает альбомы (без треков) с учетом пагинации
+	GetAlbum(id int64) (*models.Album, error)             // Возвращает альбом вместе с треками
+	AddAlbum(album *models.Album) error                   // Добавляет альбом и привязывает к нему песни из album.Tracks
+	UpdateAlbum(album *models.Album) error                // Изменяет альбом (треки заменяются, только если album.Tracks не nil)
+	DeleteAlbum(id int64) error                           // Удаляет альбом (песни остаются без альбома)
+}
+
+// Интерфейс репозитория заданий на заполнение информации о песнях из стороннего API, который должен реализовывать каждый вид хранилища
+type JobStore interface {
+	EnqueueSong(song *models.Song) (*models.Job, error)                        // Добавляет песню вместе с заданием на заполнение ее информации (в одной транзакции)
+	GetJob(id int64) (*models.Job, error)                                      // Возвращает задание по идентификатору
+	GetJobs(status models.JobStatus, offset, limit int) ([]*models.Job, error) // Возвращает задания с заданным статусом (все, если статус пустой)
+	ClaimJob(lease time.Duration) (*models.Job, error)                         // Забирает готовое к выполнению задание (sql.ErrNoRows, если таких нет); изменить его состояние можно, пока аренда с этим номером попытки не потеряна (иначе ErrLeaseLost)
+	CompleteJob(job *models.Job, song *models.Song) error                      // Сохраняет дату релиза, текст и ссылку песни и завершает задание (ErrStaleVersion, если версия песни не равна song.Version: задание не завершается)
+	RescheduleJob(job *models.Job, lastError string, runAt time.Time) error    // Возвращает задание в очередь для повторной попытки не раньше runAt
+	BuryJob(job *models.Job, lastError string) error                           // Переводит задание в dead (попытки исчерпаны или повторять бессмысленно)
+	RetryJob(id int64) error                                                   // Возвращает dead задание в очередь (ErrWrongState, если задание не dead)
+	WithAuthor(author string) JobStore                                         // Возвращает репозиторий, изменения песен через который записываются в историю от имени author
+}
+
+// Интерфейс репозитория истории изменений песен, который должен реализовывать каждый вид хранилища
+type RevisionStore interface {
+	GetRevisions(songID int64, offset, limit int) ([]*models.SongRevision, error) // Возвращает ревизии песни (последние первыми) с учетом пагинации
+	GetRevision(songID, revision int64) (*models.SongRevision, error)             // Возвращает ревизию песни (sql.ErrNoRows, если ее нет)
+}
+
+// Интерфейс репозитория плейлистов, который должен реализовывать каждый вид хранилища
+type PlaylistStore interface {
+	GetPlaylists(viewer string, offset, limit int) ([]*models.Playlist, error) // Возвращает публичные плейлисты и плейлисты viewer (без записей) с учетом пагинации
+	GetPlaylist(id int64) (*models.Playlist, error)                            // Возвращает плейлист вместе с записями по порядку
+	AddPlaylist(playlist *models.Playlist) error                               // Добавляет плейлист и записывает его идентификатор и время создания в playlist
+	UpdatePlaylist(playlist *models.Playlist) error                            // Изменяет название, описание и видимость плейлиста
+	DeletePlaylist(id int64) error                                             // Удаляет плейлист вместе с записями
+	AddEntry(id, songID int64, position int) (int, error)                      // Вставляет песню перед записью position (в конец, если position < 0) и возвращает номер записи (ErrOutOfRange, если position больше числа записей; ErrBrokenReference, если песни нет)
+	DeleteEntry(id int64, position int) error                                  // Удаляет запись position (ErrOutOfRange, если такой записи нет)
+	MoveEntry(id int64, from, to int) error                                    // Перемещает запись from так, чтобы она получила номер to (ErrOutOfRange, если таких записей нет)
+}
+
+// Интерфейс репозитория пользователей и их API-ключей, который должен реализовывать каждый вид хранилища
+type UserStore interface {
+	AddUser(user *models.User) error                       // Добавляет пользователя и записывает его идентификатор и время создания в user (ErrAlreadyExists, если имя занято)
+	GetUserByName(name string) (*models.User, error)       // Возвращает пользователя по имени (sql.ErrNoRows, если его нет)
+	FindUserByAPIKey(keyHash string) (*models.User, error) // Возвращает владельца ключа с хэшем keyHash и отмечает время использования ключа (sql.ErrNoRows, если ключа нет)
+	AddAPIKey(key *models.APIKey) error                    // Добавляет API-ключ и записывает его идентификатор и время создания в key
+	GetAPIKeys(userID int64) ([]*models.APIKey, error)     // Возвращает API-ключи пользователя в порядке создания
+	DeleteAPIKey(userID, id int64) error                   // Отзывает API-ключ пользователя (sql.ErrNoRows, если у пользователя нет такого ключа)
+}
+
+// Интерфейс репозитория кэша ответов стороннего API, который должен реализовывать каждый вид хранилища
+type SongInfoCacheStore interface {
+	GetCachedSongInfo(groupKey, songKey string) (*models.SongInfoCacheEntry, error) // Возвращает неистекший ответ (sql.ErrNoRows, если его нет)
+	PutCachedSongInfo(entry *models.SongInfoCacheEntry) error                       // Сохраняет ответ (заменяя предыдущий по тому же ключу)
+	DeleteExpiredSongInfo() (int64, error)                                          // Удаляет истекшие ответы и возвращает их количество
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Условие поискового запроса: слово или фраза, которые должны (или не должны) встречаться в тексте
+type textTerm struct {
+	words   []string // Слова фразы в нижнем регистре (для одиночного слова - одно слово)
+	negated bool     // Условие выполняется, если фразы в тексте нет
+}
+
+// Поисковый запрос в синтаксисе websearch_to_tsquery: группы условий через or, условия внутри группы через пробел (используется хранилищем в памяти)
+type textQuery [][]textTerm
+
+// Функция, разбирающая поисковый запрос ("фраза" - слова подряд, or - любая из групп, -слово - слова нет в тексте)
+func parseTextQuery(query string) textQuery {
+	var (
+		groups textQuery
+		group  []textTerm
+	)
+
+	for rest := strings.TrimSpace(query); rest != ""; rest = strings.TrimSpace(rest) {
+		negated := false
+		if rest[0] == '-' {
+			negated = true
+			rest = rest[1:]
+		}
+
+		var token string
+		if strings.HasPrefix(rest, `"`) {
+			// Незакрытая кавычка считается закрытой в конце запроса
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				token, rest = rest[1:], ""
+			} else {
+				token, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			token, rest = rest[:end], rest[end:]
+
+			if !negated && strings.EqualFold(token, "or") {
+				if len(group) > 0 {
+					groups = append(groups, group)
+					group = nil
+				}
+				continue
+			}
+		}
+
+		// Слово со знаками препинания внутри (например, rock'n'roll) ищется как фраза из его частей
+		words := textWords(token)
+		if len(words) > 0 {
+			group = append(group, textTerm{words: words, negated: negated})
+		}
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// Метод, проверяющий, удовлетворяют ли слова текста запросу (пустой запрос не удовлетворяет ничему)
+func (query textQuery) matches(words []string) bool {
+	for _, group := range query {
+		matched := true
+		for _, term := range group {
+			if (phraseCount(words, term.words) > 0) == term.negated {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Метод, оценивающий релевантность текста запросу (приближение ts_rank: число вхождений искомых слов и фраз)
+func (query textQuery) rank(words []string) float64 {
+	var hits int
+	for _, group := range query {
+		for _, term := range group {
+			if !term.negated {
+				hits += phraseCount(words, term.words)
+			}
+		}
+	}
+
+	return float64(hits)
+}
+
+// Метод, выделяющий в тексте тегами <b></b> искомые слова (как это делает ts_headline)
+func (query textQuery) highlight(text string) string {
+	highlighted := make(map[string]bool)
+	for _, group := range query {
+		for _, term := range group {
+			if !term.negated {
+				for _, word := range term.words {
+					highlighted[word] = true
+				}
+			}
+		}
+	}
+
+	var (
+		result strings.Builder
+		word   []rune
+	)
+	flush := func() {
+		if highlighted[strings.ToLower(string(word))] {
+			result.WriteString("<b>" + string(word) + "</b>")
+		} else {
+			result.WriteString(string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if isWordRune(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		result.WriteRune(r)
+	}
+	flush()
+
+	return result.String()
+}
+
+// Функция, разбивающая текст на слова в нижнем регистре (знаки препинания и пробелы считаются разделителями)
+func textWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+// Функция, проверяющая, является ли символ частью слова
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Функция, считающая количество вхождений фразы в последовательность слов
+func phraseCount(words, phrase []string) int {
+	var count int
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Функция, проверяющая, что в тексте песни встречаются все слова значения (как plainto_tsquery)
+func containsAllWords(verses []string, value string) bool {
+	words := textWords(strings.Join(verses, "\n"))
+	for _, word := range textWords(value) {
+		if phraseCount(words, []string{word}) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
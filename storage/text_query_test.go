@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTextQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  textQuery
+	}{
+		{"", nil},
+		{"Love me", textQuery{{{words: []string{"love"}}, {words: []string{"me"}}}}},
+		{`"hit the sack" -black`, textQuery{{{words: []string{"hit", "the", "sack"}}, {words: []string{"black"}, negated: true}}}},
+		{"love or hate", textQuery{{{words: []string{"love"}}}, {{words: []string{"hate"}}}}},
+		{"rock'n'roll", textQuery{{{words: []string{"rock", "n", "roll"}}}}},
+		{`"unclosed phrase`, textQuery{{{words: []string{"unclosed", "phrase"}}}}},
+	}
+
+	for _, tt := range tests {
+		if got := parseTextQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTextQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestTextQueryMatches(t *testing.T) {
+	words := textWords("Back in black, I hit the sack")
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", false},
+		{"BLACK sack", true},
+		{"black white", false},
+		{`"hit the sack"`, true},
+		{`"the hit"`, false},
+		{"black -sack", false},
+		{"white or sack", true},
+	}
+
+	for _, tt := range tests {
+		if got := parseTextQuery(tt.query).matches(words); got != tt.want {
+			t.Errorf("matches(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestTextQueryRankAndHighlight(t *testing.T) {
+	query := parseTextQuery("black -white")
+
+	if got := query.rank(textWords("black, black and white")); got != 2 {
+		t.Errorf("rank() = %v, want 2", got)
+	}
+	if got, want := query.highlight("Back in Black, white"), "Back in <b>Black</b>, white"; got != want {
+		t.Errorf("highlight() = %q, want %q", got, want)
+	}
+}
+
+func TestContainsAllWords(t *testing.T) {
+	verses := []string{"Back in black", "I hit the sack"}
+
+	if !containsAllWords(verses, "sack BACK") {
+		t.Error("containsAllWords() = false for words in any order")
+	}
+	if containsAllWords(verses, "bla") {
+		t.Error("containsAllWords() = true for part of word")
+	}
+}
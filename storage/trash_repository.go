@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"mus_lib/internal/app/models"
+	"os"
+	"time"
+)
+
+// Метод для получения песен из корзины с учетом пагинации (недавно удаленные первыми)
+func (s *SongRepository) GetTrash(offset, limit int) ([]*models.Song, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s s JOIN artists a ON a.id=s.artist_id WHERE s.deleted_at IS NOT NULL ORDER BY s.deleted_at DESC, s.id OFFSET $1 LIMIT $2`, songColumns, os.Getenv("TABLE_NAME"))
+
+	res, err := s.storage.db.Query(query, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	songs := make([]*models.Song, 0)
+
+	for res.Next() {
+		song, err := scanSong(res)
+		if err != nil {
+			return nil, err
+		}
+
+		songs = append(songs, song)
+	}
+
+	return songs, res.Err()
+}
+
+// Метод для возвращения песни из корзины (sql.ErrNoRows, если песни в корзине нет; ErrAlreadyExists, если такая песня уже есть или ее номер трека занят)
+func (s *SongRepository) UndeleteSong(id int64) error {
+	query := fmt.Sprintf(`UPDATE %s SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL`, os.Getenv("TABLE_NAME"))
+
+	return s.storage.inTxAs(s.author, func(tx *sql.Tx) error {
+		res, err := tx.Exec(query, id)
+		if err != nil {
+			// Пока песня лежала в корзине, такую же песню могли добавить заново, а ее номер трека - занять (уникальные индексы)
+			return convertError(err)
+		}
+
+		return checkAffected(res)
+	})
+}
+
+// Метод для окончательного удаления песен, которые лежат в корзине с момента раньше before, и возвращающий их количество
+func (s *SongRepository) PurgeDeletedSongs(before time.Time) (int64, error) {
+	res, err := s.storage.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE deleted_at < $1`, os.Getenv("TABLE_NAME")), before)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
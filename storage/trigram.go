@@ -0,0 +1,38 @@
+package storage
+
+import "strings"
+
+// Порог сходства по умолчанию, при котором pg_trgm считает строки похожими (оператор %)
+const defaultSimilarityThreshold = 0.3
+
+// Функция, возвращающая триграммное сходство строк так же, как similarity из pg_trgm (используется хранилищем в памяти)
+func trigramSimilarity(a, b string) float64 {
+	aTrigrams, bTrigrams := trigrams(a), trigrams(b)
+
+	var common int
+	for trigram := range aTrigrams {
+		if bTrigrams[trigram] {
+			common++
+		}
+	}
+
+	total := len(aTrigrams) + len(bTrigrams) - common
+	if total == 0 {
+		return 0
+	}
+
+	return float64(common) / float64(total)
+}
+
+// Функция, возвращающая множество триграмм строки (каждое слово дополняется двумя пробелами в начале и одним в конце, как в pg_trgm)
+func trigrams(value string) map[string]bool {
+	result := make(map[string]bool)
+	for _, word := range textWords(value) {
+		runes := []rune("  " + strings.ToLower(word) + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result[string(runes[i:i+3])] = true
+		}
+	}
+
+	return result
+}
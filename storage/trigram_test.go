@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrigramSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 0},
+		{"muse", "muse", 1},
+		{"Muse", "MUSE", 1},
+		{"cat", "cut", 1.0 / 7},
+		{"abc", "xyz", 0},
+	}
+
+	for _, tt := range tests {
+		if got := trigramSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("trigramSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTrigramSimilarityTypo(t *testing.T) {
+	if got := trigramSimilarity("Supermassive Black Hole", "Supermasive Black Hole"); got < defaultSimilarityThreshold || got == 1 {
+		t.Errorf("trigramSimilarity() = %v, want similar but not equal", got)
+	}
+}
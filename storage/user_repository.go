@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"mus_lib/internal/app/models"
+)
+
+// Сущность модельного репозитория пользователей и их API-ключей
+type UserRepository struct {
+	storage *Storage // Хранит в себе БД, т.к. общение с БД реализовано посредством репозитория
+}
+
+// Метод для добавления пользователя в БД (ErrAlreadyExists, если имя занято)
+func (r *UserRepository) AddUser(user *models.User) error {
+	err := r.storage.db.QueryRow(`INSERT INTO users (name, password_hash) VALUES ($1, $2) RETURNING id, created_at`,
+		user.Name, user.PasswordHash).Scan(&user.ID, &user.CreatedAt)
+	return convertError(err)
+}
+
+// Метод для получения пользователя из БД по имени (sql.ErrNoRows, если его нет)
+func (r *UserRepository) GetUserByName(name string) (*models.User, error) {
+	user := models.User{}
+
+	err := r.storage.db.QueryRow(`SELECT id, name, password_hash, created_at FROM users WHERE name=$1`, name).
+		Scan(&user.ID, &user.Name, &user.PasswordHash, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Метод для получения владельца API-ключа по хэшу ключа (время использования ключа отмечается тем же запросом; sql.ErrNoRows, если ключа нет)
+func (r *UserRepository) FindUserByAPIKey(keyHash string) (*models.User, error) {
+	user := models.User{}
+
+	err := r.storage.db.QueryRow(`WITH k AS (UPDATE api_keys SET last_used_at=now() WHERE key_hash=$1 RETURNING user_id)
+		SELECT u.id, u.name, u.password_hash, u.created_at FROM users u JOIN k ON k.user_id=u.id`, keyHash).
+		Scan(&user.ID, &user.Name, &user.PasswordHash, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Метод для добавления API-ключа в БД
+func (r *UserRepository) AddAPIKey(key *models.APIKey) error {
+	err := r.storage.db.QueryRow(`INSERT INTO api_keys (user_id, name, prefix, key_hash) VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		key.UserID, key.Name, key.Prefix, key.KeyHash).Scan(&key.ID, &key.CreatedAt)
+	return convertError(err)
+}
+
+// Метод для получения API-ключей пользователя из БД в порядке создания
+func (r *UserRepository) GetAPIKeys(userID int64) ([]*models.APIKey, error) {
+	res, err := r.storage.db.Query(`SELECT id, user_id, name, prefix, created_at, last_used_at FROM api_keys WHERE user_id=$1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	keys := make([]*models.APIKey, 0)
+
+	for res.Next() {
+		key := models.APIKey{}
+		err := res.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.CreatedAt, &key.LastUsedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, &key)
+	}
+
+	return keys, res.Err()
+}
+
+// Метод для отзыва API-ключа пользователя (sql.ErrNoRows, если у пользователя нет такого ключа)
+func (r *UserRepository) DeleteAPIKey(userID, id int64) error {
+	res, err := r.storage.db.Exec(`DELETE FROM api_keys WHERE id=$1 AND user_id=$2`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res)
+}
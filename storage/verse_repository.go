@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/lib/pq"
+)
+
+// Метод, вставляющий куплет перед куплетом position (в конец текста, если position < 0) и возвращающий номер вставленного куплета и новую версию песни
+func (s *SongRepository) InsertVerse(id, version int64, position int, verse string) (int, int64, error) {
+	version, err := s.editVerses(id, version, func(tx *sql.Tx, count int) (int64, error) {
+		if position < 0 {
+			position = count
+		}
+		if position > count {
+			return 0, ErrOutOfRange
+		}
+
+		// Номера куплетов отсчитываются с 0, а индексы массивов Postgres - с 1
+		return updateVerses(tx, `text=text[1:$1] || ARRAY[$2::text] || text[$1+1:cardinality(text)] WHERE id=$3`, position, verse, id)
+	})
+
+	return position, version, err
+}
+
+// Метод, заменяющий куплет position и возвращающий новую версию песни
+func (s *SongRepository) ReplaceVerse(id, version int64, position int, verse string) (int64, error) {
+	return s.editVerses(id, version, func(tx *sql.Tx, count int) (int64, error) {
+		if position < 0 || position >= count {
+			return 0, ErrOutOfRange
+		}
+
+		return updateVerses(tx, `text[$1]=$2 WHERE id=$3`, position+1, verse, id)
+	})
+}
+
+// Метод, удаляющий куплет position и возвращающий новую версию песни
+func (s *SongRepository) DeleteVerse(id, version int64, position int) (int64, error) {
+	return s.editVerses(id, version, func(tx *sql.Tx, count int) (int64, error) {
+		if position < 0 || position >= count {
+			return 0, ErrOutOfRange
+		}
+
+		return updateVerses(tx, `text=text[1:$1] || text[$1+2:cardinality(text)] WHERE id=$2`, position, id)
+	})
+}
+
+// Метод, переставляющий куплеты в порядке order (order[i] - прежний номер куплета, который станет i-м) и возвращающий новую версию песни
+func (s *SongRepository) ReorderVerses(id, version int64, order []int) (int64, error) {
+	return s.editVerses(id, version, func(tx *sql.Tx, count int) (int64, error) {
+		if !isPermutation(order, count) {
+			return 0, ErrOutOfRange
+		}
+
+		return updateVerses(tx, `text=ARRAY(SELECT text[o.position+1] FROM unnest($1::int[]) WITH ORDINALITY AS o(position, ord) ORDER BY o.ord) WHERE id=$2`, pq.Array(order), id)
+	})
+}
+
+// Метод, блокирующий строку песни до конца транзакции, проверяющий ее версию (0 - без проверки; ErrStaleVersion, если версия другая)
+// и передающий в edit количество ее куплетов (одновременные правки текста выполняются по очереди и не затирают друг друга)
+func (s *SongRepository) editVerses(id, version int64, edit func(tx *sql.Tx, count int) (int64, error)) (int64, error) {
+	var newVersion int64
+	err := s.storage.inTxAs(s.author, func(tx *sql.Tx) error {
+		var count int
+		var current int64
+		err := tx.QueryRow(fmt.Sprintf(`SELECT coalesce(cardinality(text), 0), version FROM %s WHERE id=$1 AND deleted_at IS NULL FOR UPDATE`, os.Getenv("TABLE_NAME")), id).Scan(&count, &current)
+		if err != nil {
+			return err
+		}
+		if version != 0 && current != version {
+			return ErrStaleVersion
+		}
+
+		newVersion, err = edit(tx, count)
+		return err
+	})
+
+	return newVersion, err
+}
+
+// Функция, изменяющая текст песни (set - часть запроса UPDATE после SET) и возвращающая новую версию песни
+func updateVerses(tx *sql.Tx, set string, args ...any) (int64, error) {
+	var version int64
+	err := tx.QueryRow(fmt.Sprintf(`UPDATE %s SET %s RETURNING version`, os.Getenv("TABLE_NAME"), set), args...).Scan(&version)
+	return version, err
+}
+
+// Функция, проверяющая, что order содержит каждый номер от 0 до count-1 ровно один раз
+func isPermutation(order []int, count int) bool {
+	if len(order) != count {
+		return false
+	}
+
+	seen := make([]bool, count)
+	for _, position := range order {
+		if position < 0 || position >= count || seen[position] {
+			return false
+		}
+		seen[position] = true
+	}
+
+	return true
+}